@@ -0,0 +1,112 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// AckUpTo acknowledges the messages of the durable consumer on stream up to and including the stream
+// sequence seq, e.g. to skip the messages a projection rebuild already covered. JetStream has no
+// request that moves the ack floor of a consumer directly, so the consumer's messages are fetched and
+// acknowledged until seq is reached; the first message past seq is negatively acknowledged so that it
+// is redelivered right away. AckUpTo returns immediately when the ack floor is already at seq, rejects
+// sequences past the consumer's delivered and pending messages, and fails with errAckFloorNotReached
+// when the consumer runs out of messages before reaching it.
+func (c *Client) AckUpTo(ctx context.Context, stream, consumer string, seq uint64) error {
+	return newNatsError(SubscribeError, c.ackUpTo(ctx, stream, consumer, seq))
+}
+
+func (c *Client) ackUpTo(ctx context.Context, stream, consumer string, seq uint64) error {
+	if seq == 0 {
+		return errInvalidAckSequence
+	}
+
+	js, err := c.connManager.jetStream()
+	if err != nil {
+		return err
+	}
+
+	cons, err := js.Consumer(ctx, stream, consumer)
+	if err != nil {
+		return fmt.Errorf("failed to get consumer %s on stream %s: %w", consumer, stream, err)
+	}
+
+	info, err := cons.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get info of consumer %s on stream %s: %w", consumer, stream, err)
+	}
+
+	if seq <= info.AckFloor.Stream {
+		c.logger.Debugf("consumer %s on stream %s already acknowledged up to %d", consumer, stream, info.AckFloor.Stream)
+
+		return nil
+	}
+
+	if last := info.Delivered.Stream + info.NumPending; seq > last {
+		return fmt.Errorf("%w: %d is past %d, the last sequence of consumer %s on stream %s", errAckSequenceOutOfRange,
+			seq, last, consumer, stream)
+	}
+
+	for ctx.Err() == nil {
+		msgs, err := cons.Fetch(fetchBatchSize(c.Config), fetchOptions(c.Config)...)
+		if err != nil {
+			return err
+		}
+
+		reached, count, err := ackMessagesUpTo(ctx, msgs, seq, c.Config.DoubleAck)
+		if err != nil {
+			return err
+		}
+
+		if reached {
+			c.logger.Logf("acknowledged messages of consumer %s on stream %s up to %d", consumer, stream, seq)
+
+			return nil
+		}
+
+		if count == 0 {
+			return fmt.Errorf("%w: consumer %s on stream %s has no messages left before %d", errAckFloorNotReached,
+				consumer, stream, seq)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// ackMessagesUpTo acknowledges the messages of msgs up to the stream sequence seq, waiting for the
+// server to confirm each ack when doubleAck is set, and naks the ones past it. It reports whether seq
+// was reached and how many messages the batch had.
+func ackMessagesUpTo(ctx context.Context, msgs jetstream.MessageBatch, seq uint64, doubleAck bool) (reached bool, count int, err error) {
+	for msg := range msgs.Messages() {
+		count++
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return reached, count, err
+		}
+
+		if meta.Sequence.Stream > seq {
+			reached = true
+
+			if err := msg.Nak(); err != nil {
+				return reached, count, err
+			}
+
+			continue
+		}
+
+		if err := ackMessage(ctx, msg, doubleAck); err != nil {
+			return reached, count, fmt.Errorf("%w %d: %w", errAckFailed, meta.Sequence.Stream, err)
+		}
+
+		reached = reached || meta.Sequence.Stream == seq
+	}
+
+	if err := msgs.Error(); err != nil && !isFetchTimeout(err) {
+		return reached, count, err
+	}
+
+	return reached, count, nil
+}
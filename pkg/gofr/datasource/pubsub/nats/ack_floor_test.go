@@ -0,0 +1,131 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestNATSClient_AckUpTo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{BatchSize: 3, MaxWait: time.Second},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().Consumer(ctx, "orders", "projector").Return(mockConsumer, nil)
+	mockConsumer.EXPECT().Info(ctx).Return(&jetstream.ConsumerInfo{
+		Delivered:  jetstream.SequenceInfo{Stream: 10},
+		AckFloor:   jetstream.SequenceInfo{Stream: 10},
+		NumPending: 5,
+	}, nil)
+	mockConsumer.EXPECT().Fetch(3, gomock.Any()).Return(mockBatch, nil)
+
+	msgChan := make(chan jetstream.Msg, 3)
+
+	// the messages up to the sequence are acknowledged and the one past it is redelivered
+	for _, seq := range []uint64{11, 12, 13} {
+		msg := NewMockMsg(ctrl)
+		msg.EXPECT().Metadata().Return(&jetstream.MsgMetadata{Sequence: jetstream.SequencePair{Stream: seq}}, nil)
+
+		if seq <= 12 {
+			msg.EXPECT().Ack().Return(nil)
+		} else {
+			msg.EXPECT().Nak().Return(nil)
+		}
+
+		msgChan <- msg
+	}
+
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+
+	require.NoError(t, client.AckUpTo(ctx, "orders", "projector", 12))
+}
+
+func TestNATSClient_AckUpTo_DoubleAck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{BatchSize: 1, MaxWait: time.Second, DoubleAck: true},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().Consumer(ctx, "orders", "projector").Return(mockConsumer, nil)
+	mockConsumer.EXPECT().Info(ctx).Return(&jetstream.ConsumerInfo{
+		Delivered:  jetstream.SequenceInfo{Stream: 11},
+		AckFloor:   jetstream.SequenceInfo{Stream: 11},
+		NumPending: 1,
+	}, nil)
+	mockConsumer.EXPECT().Fetch(1, gomock.Any()).Return(mockBatch, nil)
+
+	// with DoubleAck the acks are confirmed by the server, as they are for Commit
+	msg := NewMockMsg(ctrl)
+	msg.EXPECT().Metadata().Return(&jetstream.MsgMetadata{Sequence: jetstream.SequencePair{Stream: 12}}, nil)
+	msg.EXPECT().DoubleAck(ctx).Return(nil)
+
+	msgChan := make(chan jetstream.Msg, 1)
+	msgChan <- msg
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+
+	require.NoError(t, client.AckUpTo(ctx, "orders", "projector", 12))
+}
+
+func TestNATSClient_AckUpTo_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	require.ErrorIs(t, client.AckUpTo(ctx, "orders", "projector", 0), errInvalidAckSequence)
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(2)
+	mockJS.EXPECT().Consumer(ctx, "orders", "projector").Return(mockConsumer, nil).Times(2)
+	mockConsumer.EXPECT().Info(ctx).Return(&jetstream.ConsumerInfo{
+		Delivered:  jetstream.SequenceInfo{Stream: 10},
+		AckFloor:   jetstream.SequenceInfo{Stream: 8},
+		NumPending: 2,
+	}, nil).Times(2)
+
+	// a sequence already below the ack floor needs no fetch
+	require.NoError(t, client.AckUpTo(ctx, "orders", "projector", 8))
+	require.ErrorIs(t, client.AckUpTo(ctx, "orders", "projector", 13), errAckSequenceOutOfRange)
+}
@@ -0,0 +1,158 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// circuitState is the state of a circuitBreaker, reported as the app_pubsub_circuit_state gauge.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker short-circuits publishes once FailureThreshold publishes in a row failed. After
+// OpenDuration it lets a single publish through; the circuit closes again when it succeeds and opens
+// again when it fails. A nil circuitBreaker lets every publish through.
+type circuitBreaker struct {
+	threshold    int
+	openDuration time.Duration
+	clock        clock
+	metrics      Metrics
+	logger       pubsub.Logger
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker returns nil when no circuit breaker is configured.
+func newCircuitBreaker(conf *CircuitBreakerConfig, clk clock, metrics Metrics, logger pubsub.Logger) *circuitBreaker {
+	if conf == nil {
+		return nil
+	}
+
+	return &circuitBreaker{
+		threshold:    conf.FailureThreshold,
+		openDuration: conf.OpenDuration,
+		clock:        clockOrReal(clk),
+		metrics:      metrics,
+		logger:       logger,
+	}
+}
+
+// allow reports whether a publish may be attempted. Once the circuit has been open for OpenDuration,
+// it half-opens and allows a single publish whose outcome decides the next state.
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.openDuration {
+			return false
+		}
+
+		b.setState(circuitHalfOpen)
+		b.probing = true
+
+		return true
+	case circuitHalfOpen:
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the circuit with the outcome of an allowed publish made with ctx. Publishes that failed
+// because of the caller leave the circuit as it is, so that a misbehaving caller cannot open it for everyone.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if isCallerError(ctx, err) {
+		return
+	}
+
+	if err == nil {
+		b.failures = 0
+		b.setState(circuitClosed)
+
+		return
+	}
+
+	b.failures++
+
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+		b.setState(circuitOpen)
+	}
+}
+
+// isCallerError reports whether a publish failed because of its caller rather than the connection or
+// the server, e.g. because its payload was too large or the caller cancelled ctx. Deadlines are not
+// caller errors: a publish timing out is what a slow or unreachable server looks like.
+func isCallerError(ctx context.Context, err error) bool {
+	return errors.Is(err, errPayloadTooLarge) || errors.Is(err, errNoStreamForSubject) ||
+		errors.Is(err, errJetStreamNotConfigured) ||
+		(errors.Is(err, context.Canceled) && errors.Is(ctx.Err(), context.Canceled))
+}
+
+// setState changes the state of the circuit, logging and reporting changes. The lock must be held.
+func (b *circuitBreaker) setState(state circuitState) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+
+	switch {
+	case b.logger == nil:
+	case state == circuitOpen:
+		b.logger.Logf("WARN: publish circuit breaker opened after %d consecutive failures", b.failures)
+	default:
+		b.logger.Logf("publish circuit breaker is %v", state)
+	}
+
+	if b.metrics != nil {
+		b.metrics.SetGauge(circuitStateMetric, float64(state))
+	}
+}
@@ -0,0 +1,168 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	clk := newFakeClock()
+
+	b := newCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute}, clk, mockMetrics,
+		logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+
+	gomock.InOrder(
+		mockMetrics.EXPECT().SetGauge("app_pubsub_circuit_state", float64(circuitOpen)),
+		mockMetrics.EXPECT().SetGauge("app_pubsub_circuit_state", float64(circuitHalfOpen)),
+		mockMetrics.EXPECT().SetGauge("app_pubsub_circuit_state", float64(circuitOpen)),
+		mockMetrics.EXPECT().SetGauge("app_pubsub_circuit_state", float64(circuitHalfOpen)),
+		mockMetrics.EXPECT().SetGauge("app_pubsub_circuit_state", float64(circuitClosed)),
+	)
+
+	// closed: failures below the threshold keep the circuit closed
+	require.True(t, b.allow())
+	b.record(ctx, errPublishError)
+	assert.Equal(t, circuitClosed, b.state)
+
+	// closed -> open once the threshold is reached
+	require.True(t, b.allow())
+	b.record(ctx, errPublishError)
+	assert.Equal(t, circuitOpen, b.state)
+	assert.False(t, b.allow())
+
+	// open -> half-open after the open duration, letting a single publish through
+	clk.Advance(time.Minute)
+	require.True(t, b.allow())
+	assert.Equal(t, circuitHalfOpen, b.state)
+	assert.False(t, b.allow())
+
+	// half-open -> open when the trial publish fails
+	b.record(ctx, errPublishError)
+	assert.Equal(t, circuitOpen, b.state)
+	assert.False(t, b.allow())
+
+	// half-open -> closed when the trial publish succeeds
+	clk.Advance(time.Minute)
+	require.True(t, b.allow())
+	b.record(ctx, nil)
+	assert.Equal(t, circuitClosed, b.state)
+	assert.True(t, b.allow())
+}
+
+func TestCircuitBreaker_CallerErrors(t *testing.T) {
+	clk := newFakeClock()
+	b := newCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute}, clk, nil, nil)
+
+	ctx := context.Background()
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	// failures caused by the caller do not open the circuit
+	for _, err := range []error{errPayloadTooLarge, errNoStreamForSubject, errJetStreamNotConfigured} {
+		require.True(t, b.allow())
+		b.record(ctx, err)
+		assert.Equal(t, circuitClosed, b.state, "%v", err)
+	}
+
+	require.True(t, b.allow())
+	b.record(cancelled, context.Canceled)
+	assert.Equal(t, circuitClosed, b.state)
+
+	require.True(t, b.allow())
+	b.record(ctx, errPublishError)
+	assert.Equal(t, circuitOpen, b.state)
+
+	// nor do they decide the trial publish of a half-open circuit, which lets the next one through
+	clk.Advance(time.Minute)
+	require.True(t, b.allow())
+	b.record(cancelled, context.Canceled)
+	assert.Equal(t, circuitHalfOpen, b.state)
+	assert.True(t, b.allow())
+
+	// a cancellation the caller did not ask for is a failure
+	b.record(ctx, context.Canceled)
+	assert.Equal(t, circuitOpen, b.state)
+}
+
+func TestCircuitBreaker_Timeouts(t *testing.T) {
+	testCases := []struct {
+		desc string
+		err  error
+	}{
+		{desc: "deadline exceeded", err: context.DeadlineExceeded},
+		{desc: "request timeout", err: nats.ErrTimeout},
+	}
+
+	for i, tc := range testCases {
+		b := newCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 3, OpenDuration: time.Minute}, newFakeClock(), nil, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		<-ctx.Done()
+
+		// publishes timing out are failures of the server, not of the caller whose deadline they hit
+		for range 3 {
+			require.True(t, b.allow(), "TEST[%d], Failed.\n%s", i, tc.desc)
+			b.record(ctx, tc.err)
+		}
+
+		assert.Equal(t, circuitOpen, b.state, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.False(t, b.allow(), "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		cancel()
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	b := newCircuitBreaker(nil, nil, nil, nil)
+	assert.Nil(t, b)
+
+	for range 10 {
+		assert.True(t, b.allow())
+		b.record(context.Background(), errPublishError)
+	}
+}
+
+func TestNATSClient_Publish_CircuitOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	logger := logging.NewMockLogger(logging.DEBUG)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{},
+		logger:      logger,
+		breaker: newCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute},
+			newFakeClock(), nil, logger),
+	}
+
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().Publish(ctx, "test-subject", []byte("test-message"), nil).Return(errPublishError)
+
+	err := client.Publish(ctx, "test-subject", []byte("test-message"))
+	require.ErrorIs(t, err, errPublishError)
+
+	// the open circuit fails publishes without reaching the server
+	err = client.Publish(ctx, "test-subject", []byte("test-message"))
+	require.ErrorIs(t, err, errCircuitOpen)
+	require.ErrorIs(t, err, &NatsError{Kind: PublishError})
+	// async publishes go through the same circuit
+	_, err = client.PublishAsync(ctx, "test-subject", []byte("test-message"))
+	require.ErrorIs(t, err, errCircuitOpen)
+}
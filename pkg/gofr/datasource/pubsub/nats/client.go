@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"go.opentelemetry.io/otel/trace"
 	"gofr.dev/pkg/gofr/datasource/pubsub"
@@ -19,41 +21,93 @@ type Client struct {
 	connManager      ConnectionManagerInterface
 	subManager       SubscriptionManagerInterface
 	subscriptions    map[string]context.CancelFunc
+	handlers         map[string]handlerSubscription
+	responders       map[*nats.Subscription]context.CancelFunc
 	subMutex         sync.Mutex
 	streamManager    StreamManagerInterface
 	Config           *Config
 	logger           pubsub.Logger
 	metrics          Metrics
 	tracer           trace.Tracer
+	validator        func(subject string, msg []byte) error
 	natsConnector    Connector
 	jetStreamCreator JetStreamCreator
+	metricsOnce      sync.Once
+	dedupOnce        sync.Once
+	dedup            *dedupCache
+	active           *subscriptionCount
+	pauseOnce        sync.Once
+	paused           *pausedSubjects
+	filterOnce       sync.Once
+	filter           *subscribeFilter
+	streamMutex      sync.Mutex
+	templateStreams  map[string]struct{}
+	streamNames      *streamNameCache
+	breaker          *circuitBreaker
+	limiter          *publishRateLimiter
+	// clock defaults to the real clock when nil.
+	clock clock
 }
 
 type messageHandler func(context.Context, jetstream.Msg) error
 
+// handlerSubscription is a subscription made with SubscribeWithHandler. Its context is kept so that
+// Reconnect subscribes the handler again for as long as the caller meant it to run.
+type handlerSubscription struct {
+	ctx     context.Context
+	handler messageHandler
+}
+
 // Connect establishes a connection to NATS and sets up jStream.
 func (c *Client) Connect() error {
-	c.logger.Debugf("connecting to NATS server at %v", c.Config.Server)
+	c.logger.Debugf("connecting to NATS server at %v", serverList(c.Config))
 
 	if err := c.validateAndPrepare(); err != nil {
-		return err
+		return newNatsError(ConfigError, err)
 	}
 
+	c.streamNames = newStreamNameCache(c.Config.StreamInfoCacheTTL, c.clock)
+
 	connManager := NewConnectionManager(c.Config, c.logger, c.natsConnector, c.jetStreamCreator)
+	connManager.metrics = c.metrics
+	connManager.streamNames = c.streamNames
+	connManager.clock = c.clock
+
 	if err := connManager.Connect(); err != nil {
-		c.logger.Errorf("failed to connect to NATS server at %v: %v", c.Config.Server, err)
-		return err
+		c.logger.Errorf("failed to connect to NATS server at %v: %v", serverList(c.Config), err)
+		return newNatsError(ConnectionError, err)
 	}
 
 	c.connManager = connManager
 
 	js, err := c.connManager.jetStream()
 	if err != nil {
-		return err
+		return newNatsError(ConnectionError, err)
+	}
+
+	streamManager := newStreamManager(js, c.logger)
+	streamManager.dryRun = c.Config.DryRun
+	streamManager.verifyOnly = !autoCreateStream(c.Config)
+	streamManager.updateIfExists = c.Config.UpdateStreamIfExists
+
+	c.streamManager = streamManager
+	c.active = newSubscriptionCount(c.metrics)
+	c.breaker = newCircuitBreaker(c.Config.CircuitBreaker, c.clock, c.metrics, c.logger)
+	c.limiter = newPublishRateLimiter(c.Config, c.clock)
+
+	subManager := newSubscriptionManager(batchSize)
+	subManager.active = c.active
+	subManager.paused = c.pausedSubjects()
+	subManager.filter = c.subscribeFilter()
+	subManager.clock = c.clock
+
+	c.subManager = subManager
+
+	// Reconnect connects again, and registering the metrics once more would only log that they exist.
+	if c.metrics != nil {
+		c.metricsOnce.Do(func() { registerMetrics(c.metrics, c.Config) })
 	}
 
-	c.streamManager = newStreamManager(js, c.logger)
-	c.subManager = newSubscriptionManager(batchSize)
 	c.logSuccessfulConnection()
 
 	return nil
@@ -71,14 +125,14 @@ func (c *Client) validateAndPrepare() error {
 
 func (c *Client) logSuccessfulConnection() {
 	if c.logger != nil {
-		c.logger.Logf("connected to NATS server '%s'", c.Config.Server)
+		c.logger.Logf("connected to NATS server '%s'", serverList(c.Config))
 	}
 }
 
-// UseLogger sets the logger for the NATS client.
+// UseLogger sets the logger for the NATS client. Its log lines carry Config.LoggerName.
 func (c *Client) UseLogger(logger any) {
 	if l, ok := logger.(pubsub.Logger); ok {
-		c.logger = l
+		c.logger = newNamedLogger(l, c.Config)
 	}
 }
 
@@ -96,26 +150,397 @@ func (c *Client) UseMetrics(metrics any) {
 	}
 }
 
+// SetPublishValidator registers a validator that runs on every message before it is published.
+// Messages it returns an error for are rejected. A nil validator disables validation.
+func (c *Client) SetPublishValidator(validator func(subject string, msg []byte) error) {
+	c.validator = validator
+}
+
+// validatePublish runs the registered validator, if any, on a message about to be published.
+func (c *Client) validatePublish(ctx context.Context, subject string, message []byte) error {
+	if c.validator == nil {
+		return nil
+	}
+
+	if err := c.validator(subject, message); err != nil {
+		if c.metrics != nil {
+			c.metrics.IncrementCounter(ctx, publishValidationErrorMetric, "subject", subject)
+		}
+
+		return fmt.Errorf("%w for subject %s: %w", errPublishValidation, subject, err)
+	}
+
+	return nil
+}
+
 // Publish publishes a message to a topic.
 func (c *Client) Publish(ctx context.Context, subject string, message []byte) error {
-	return c.connManager.Publish(ctx, subject, message, c.metrics)
+	return c.PublishWithHeaders(ctx, subject, message, nil)
+}
+
+// PublishWithHeaders publishes a message to a topic along with the provided headers. Failures are
+// returned as a NatsError of kind PublishError.
+// The trace context of ctx is added to the headers so that consumers can continue the trace.
+func (c *Client) PublishWithHeaders(ctx context.Context, subject string, message []byte, headers nats.Header) error {
+	return newNatsError(PublishError, c.publishWithHeaders(ctx, subject, message, headers))
+}
+
+func (c *Client) publishWithHeaders(ctx context.Context, subject string, message []byte, headers nats.Header) error {
+	subject = prefixSubject(c.Config, subject)
+
+	return c.publishMessage(ctx, subject, message, headers, func(ctx context.Context, payload []byte, headers nats.Header) error {
+		if len(headers) == 0 {
+			return c.connManager.Publish(ctx, subject, payload, c.metrics)
+		}
+
+		return c.connManager.PublishWithHeaders(ctx, subject, payload, headers, c.metrics)
+	})
+}
+
+// PublishAck publishes a message to a subject like Publish and returns the acknowledgement of the
+// server, which holds the stream sequence assigned to the message. PubAck.Duplicate reports a message
+// the server discarded as a duplicate. Messages published over core NATS have a nil acknowledgement.
+func (c *Client) PublishAck(ctx context.Context, subject string, message []byte) (*jetstream.PubAck, error) {
+	subject = prefixSubject(c.Config, subject)
+
+	var ack *jetstream.PubAck
+
+	err := c.publishMessage(ctx, subject, message, nil, func(ctx context.Context, payload []byte, headers nats.Header) error {
+		var err error
+
+		ack, err = c.connManager.PublishAck(ctx, subject, payload, headers, c.metrics)
+
+		return err
+	})
+	if err != nil {
+		return nil, newNatsError(PublishError, err)
+	}
+
+	return ack, nil
+}
+
+// publishMessage validates, traces, compresses and logs a message published to subject with send.
+func (c *Client) publishMessage(ctx context.Context, subject string, message []byte, headers nats.Header,
+	send func(ctx context.Context, payload []byte, headers nats.Header) error) error {
+	stream, _ := streamForSubject(c.Config, subject)
+
+	start := clockOrReal(c.clock).Now()
+	ctx, span := c.startSpan(ctx, "nats.publish", spanAttributes(subject, stream, "")...)
+
+	if err := c.validatePublish(ctx, subject, message); err != nil {
+		endSpan(span, err)
+
+		return err
+	}
+
+	if err := c.waitForRateLimit(ctx, subject); err != nil {
+		endSpan(span, err)
+
+		return err
+	}
+
+	headers = injectCorrelationID(ctx, injectTraceContext(ctx, headers))
+
+	payload, headers, err := compressPayload(c.Config, message, headers)
+	if err != nil {
+		c.logger.Errorf("failed to compress message for subject %s: %v", subject, err)
+		endSpan(span, err)
+
+		return err
+	}
+
+	if !c.breaker.allow() {
+		endSpan(span, errCircuitOpen)
+
+		return errCircuitOpen
+	}
+
+	err = send(ctx, payload, headers)
+	c.breaker.record(ctx, err)
+
+	if err == nil {
+		c.logMessage("PUB", correlationID(ctx), subject, message, start)
+	}
+
+	endSpan(span, err)
+
+	return err
+}
+
+// waitForRateLimit applies Config.PublishRateLimit to a publish to subject, incrementing
+// app_pubsub_rate_limited_count when the publish exceeded it.
+func (c *Client) waitForRateLimit(ctx context.Context, subject string) error {
+	limited, err := c.limiter.wait(ctx, subject)
+
+	if limited && c.metrics != nil {
+		c.metrics.IncrementCounter(ctx, rateLimitedCountMetric, "subject", subject)
+	}
+
+	if errors.Is(err, errRateLimited) {
+		return fmt.Errorf("%w for subject %s", err, subject)
+	}
+
+	return err
+}
+
+// PublishWithID publishes a message with the Nats-Msg-Id header set to id. The server discards messages
+// with an ID already seen within the stream's duplicate window (see StreamConfig.DuplicateWindow). An
+// empty id behaves like Publish.
+// With Config.DedupCacheSize set, IDs published successfully by this client are skipped locally.
+func (c *Client) PublishWithID(ctx context.Context, subject string, message []byte, id string) error {
+	if id == "" {
+		return c.Publish(ctx, subject, message)
+	}
+
+	published := c.publishedIDs()
+	if published.contains(id) {
+		c.logger.Debugf("skipping publish of duplicate message %s to subject %s", id, subject)
+
+		if c.metrics != nil {
+			c.metrics.IncrementCounter(ctx, localDedupCountMetric, "subject", subject)
+		}
+
+		return nil
+	}
+
+	if err := c.PublishWithHeaders(ctx, subject, message, nats.Header{nats.MsgIdHdr: []string{id}}); err != nil {
+		return err
+	}
+
+	published.add(id)
+
+	return nil
+}
+
+// publishedIDs returns the cache of recently published message IDs, which is nil when disabled.
+func (c *Client) publishedIDs() *dedupCache {
+	c.dedupOnce.Do(func() {
+		c.dedup = newDedupCache(c.Config.DedupCacheSize)
+	})
+
+	return c.dedup
+}
+
+// PublishAsync publishes a message to a topic without waiting for the acknowledgement. It is validated,
+// rate limited, compressed and traced like Publish; the circuit breaker only sees whether the message
+// could be handed to the client, as its acknowledgement arrives after PublishAsync returns.
+func (c *Client) PublishAsync(ctx context.Context, subject string, message []byte) (jetstream.PubAckFuture, error) {
+	subject = prefixSubject(c.Config, subject)
+
+	var future jetstream.PubAckFuture
+
+	err := c.publishMessage(ctx, subject, message, nil, func(ctx context.Context, payload []byte, headers nats.Header) error {
+		var err error
+
+		future, err = c.connManager.PublishAsync(ctx, subject, payload, headers, c.metrics)
+
+		return err
+	})
+	if err != nil {
+		return nil, newNatsError(PublishError, err)
+	}
+
+	return future, nil
+}
+
+// PublishAsyncComplete waits until all pending async publishes are acknowledged or ctx is done.
+func (c *Client) PublishAsyncComplete(ctx context.Context) error {
+	return newNatsError(PublishError, c.connManager.PublishAsyncComplete(ctx))
+}
+
+// PublishBatch publishes the messages to a subject asynchronously and waits for all of them to be
+// acknowledged. The returned error joins the failures along with the index of each failed message.
+// Nothing is published when a message is rejected by the publish validator. The batch is sent as it is:
+// it is not rate limited, compressed or traced, and it bypasses the circuit breaker, which tracks the
+// outcome of single publishes.
+func (c *Client) PublishBatch(ctx context.Context, subject string, msgs [][]byte) error {
+	subject = prefixSubject(c.Config, subject)
+
+	for i, msg := range msgs {
+		if err := c.validatePublish(ctx, subject, msg); err != nil {
+			return newNatsError(PublishError, fmt.Errorf("%w at index %d: %w", errBatchPublishFailed, i, err))
+		}
+	}
+
+	return newNatsError(PublishError, c.connManager.PublishBatch(ctx, subject, msgs, c.metrics))
+}
+
+// Request sends a request on the given subject over core NATS and returns the reply.
+// The reply timeout is governed by the context deadline.
+func (c *Client) Request(ctx context.Context, subject string, data []byte) (*pubsub.Message, error) {
+	msg, err := c.connManager.Request(ctx, prefixSubject(c.Config, subject), data, c.metrics)
+	if msg != nil {
+		msg.Topic = subject
+	}
+
+	return msg, newNatsError(RequestError, err)
+}
+
+// RequestWithHeaders sends a request with headers like Request, e.g. to pass an auth token or trace
+// context. Responders registered with RegisterResponder receive the headers as the message MetaData.
+func (c *Client) RequestWithHeaders(ctx context.Context, subject string, data []byte, headers nats.Header) (*pubsub.Message, error) {
+	msg, err := c.connManager.RequestWithHeaders(ctx, prefixSubject(c.Config, subject), data, headers, c.metrics)
+	if msg != nil {
+		msg.Topic = subject
+	}
+
+	return msg, newNatsError(RequestError, err)
 }
 
 // Subscribe subscribes to a topic and returns a single message.
+// The span of the call is linked to the trace the message was published in.
 func (c *Client) Subscribe(ctx context.Context, topic string) (*pubsub.Message, error) {
+	return c.subscribe(ctx, topic, c.Config)
+}
+
+// SubscribeWithOptions subscribes to a subject like Subscribe, with opts taking precedence over the
+// Config for this subject. The options apply when the subscription of the subject is first created.
+func (c *Client) SubscribeWithOptions(ctx context.Context, subject string, opts SubscribeOptions) (*pubsub.Message, error) {
+	return c.subscribe(ctx, subject, opts.apply(c.Config))
+}
+
+// SubscribeChan consumes subject in the background and delivers its messages on the returned channel,
+// which is buffered to Config.BatchSize. The channel is closed once ctx is done or the subscription
+// stops with an error, so callers can simply range over it.
+func (c *Client) SubscribeChan(ctx context.Context, subject string) (<-chan *pubsub.Message, error) {
+	if _, err := c.connManager.jetStream(); err != nil {
+		return nil, newNatsError(SubscribeError, err)
+	}
+
+	msgs := make(chan *pubsub.Message, fetchBatchSize(c.Config))
+
+	go func() {
+		defer close(msgs)
+
+		for {
+			msg, err := c.subscribe(ctx, subject, c.Config)
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Errorf("subscription to %s stopped: %v", subject, err)
+				}
+
+				return
+			}
+
+			// the subscription was closed
+			if msg == nil {
+				return
+			}
+
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return msgs, nil
+}
+
+// subscribe returns the next message on topic, with failures returned as a NatsError of kind SubscribeError.
+func (c *Client) subscribe(ctx context.Context, topic string, cfg *Config) (*pubsub.Message, error) {
+	msg, err := c.nextMessage(ctx, prefixSubject(cfg, topic), cfg)
+
+	return msg, newNatsError(SubscribeError, err)
+}
+
+func (c *Client) nextMessage(ctx context.Context, topic string, cfg *Config) (*pubsub.Message, error) {
+	consumer := ""
+	if isDurable(cfg) {
+		consumer = durableName(cfg, topic)
+	}
+
+	start := clockOrReal(c.clock).Now()
+	ctx, span := c.startSpan(ctx, "nats.subscribe", spanAttributes(topic, consumerStream(cfg, topic), consumer)...)
+
 	js, err := c.connManager.jetStream()
 	if err != nil {
+		endSpan(span, err)
+
 		return nil, err
 	}
 
-	return c.subManager.Subscribe(ctx, topic, js, c.Config, c.logger, c.metrics)
+	msg, err := c.subManager.Subscribe(ctx, topic, js, cfg, c.logger, c.metrics)
+	if err != nil && cfg.FallbackToCoreNATS && isJetStreamUnavailable(err) {
+		err = fmt.Errorf("%w: %w", errCoreFallbackSubscribe, err)
+	}
+
+	// no message is returned when the subscription was closed while waiting
+	if err == nil && msg != nil {
+		if span.IsRecording() {
+			span.AddLink(trace.LinkFromContext(msg.Context()))
+		}
+
+		c.logMessage("SUB", messageCorrelationID(msg), msg.Topic, msg.Value, start)
+	}
+
+	endSpan(span, err)
+
+	return msg, err
+}
+
+// AckBatch acknowledges the messages in order, stopping at the first failure. The returned error
+// reports the index of the message that could not be acknowledged; messages after it are left unacked.
+func (*Client) AckBatch(ctx context.Context, msgs []*pubsub.Message) error {
+	for i, msg := range msgs {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%w at index %d: %w", errAckFailed, i, err)
+		}
+
+		committer, ok := msg.Committer.(*natsCommitter)
+		if !ok {
+			return fmt.Errorf("%w at index %d: %w", errAckFailed, i, errNotNATSMessage)
+		}
+
+		if err := committer.ack(ctx, committer.doubleAck); err != nil {
+			return fmt.Errorf("%w at index %d: %w", errAckFailed, i, err)
+		}
+	}
+
+	return nil
+}
+
+// AttachConsumer returns the existing consumer on stream, e.g. one provisioned out-of-band, without
+// creating or updating it. It fails with errConsumerNotFound when the consumer does not exist.
+func (c *Client) AttachConsumer(ctx context.Context, stream, consumer string) (jetstream.Consumer, error) {
+	js, err := c.connManager.jetStream()
+	if err != nil {
+		return nil, newNatsError(SubscribeError, err)
+	}
+
+	cons, err := attachConsumer(ctx, js, stream, consumer)
+	if err != nil {
+		c.logger.Errorf("failed to attach to consumer %s on stream %s: %v", consumer, stream, err)
+
+		return nil, newNatsError(SubscribeError, err)
+	}
+
+	return cons, nil
 }
 
 func (c *Client) generateConsumerName(subject string) string {
-	return fmt.Sprintf("%s_%s", c.Config.Consumer, strings.ReplaceAll(subject, ".", "_"))
+	return durableName(c.Config, subject)
 }
 
+// SubscribeWithHandler consumes subject in the background, calling handler for every message. Up to
+// Config.Concurrency messages are handled at once, so handlers must not assume any ordering. Messages
+// are acknowledged when the handler succeeds and negatively acknowledged when it fails.
 func (c *Client) SubscribeWithHandler(ctx context.Context, subject string, handler messageHandler) error {
+	cons, err := c.subscribeWithHandler(ctx, subject, handler)
+	if err != nil {
+		return newNatsError(SubscribeError, err)
+	}
+
+	// the callback runs without the lock held, so it may subscribe or unsubscribe itself
+	notifySubscribed(c.Config, subject, cons, c.logger)
+
+	return nil
+}
+
+func (c *Client) subscribeWithHandler(ctx context.Context, subject string, handler messageHandler) (jetstream.Consumer, error) {
+	subject = prefixSubject(c.Config, subject)
+
 	c.subMutex.Lock()
 	defer c.subMutex.Unlock()
 
@@ -124,25 +549,152 @@ func (c *Client) SubscribeWithHandler(ctx context.Context, subject string, handl
 
 	js, err := c.connManager.jetStream()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	consumerName := c.generateConsumerName(subject)
 
 	cons, err := c.createOrUpdateConsumer(ctx, js, subject, consumerName)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[string]context.CancelFunc)
+	}
+
+	if c.handlers == nil {
+		c.handlers = make(map[string]handlerSubscription)
 	}
 
 	// Create a new context for this subscription
 	subCtx, cancel := context.WithCancel(ctx)
 	c.subscriptions[subject] = cancel
+	c.handlers[subject] = handlerSubscription{ctx: ctx, handler: handler}
+	c.active.add(1)
 
 	go func() {
 		defer cancel() // Ensure the cancellation is handled properly
 		c.processMessages(subCtx, cons, subject, handler)
 	}()
 
+	return cons, nil
+}
+
+// SubscribeWithMessageHandler is SubscribeWithHandler for handlers of pubsub messages. The messages
+// are acknowledged based on the result of the handler, so committing them is a no-op.
+func (c *Client) SubscribeWithMessageHandler(ctx context.Context, subject string, handler func(*pubsub.Message) error) error {
+	return c.SubscribeWithHandler(ctx, subject, func(_ context.Context, msg jetstream.Msg) error {
+		pubsubMsg, err := newPubSubMessage(msg, c.handlerConfig())
+		if err != nil {
+			return err
+		}
+
+		return handler(pubsubMsg)
+	})
+}
+
+// SubscribeAll is SubscribeWithMessageHandler for several subjects sharing one handler. Every subject
+// gets its own consumer, and the handler is called concurrently for messages of different subjects.
+// Subjects listed more than once are rejected before anything is subscribed. When a subject cannot be
+// subscribed, the subscriptions this call created are unsubscribed again; subjects that were already
+// subscribed stay subscribed. The subscriptions are stopped by Unsubscribe or Close like any other.
+func (c *Client) SubscribeAll(ctx context.Context, subjects []string, handler func(*pubsub.Message) error) error {
+	if len(subjects) == 0 {
+		return newNatsError(SubscribeError, errNoSubjects)
+	}
+
+	seen := make(map[string]struct{}, len(subjects))
+
+	for _, subject := range subjects {
+		if _, ok := seen[subject]; ok {
+			return newNatsError(SubscribeError, fmt.Errorf("%w: %s", errDuplicateSubject, subject))
+		}
+
+		seen[subject] = struct{}{}
+	}
+
+	created := make([]string, 0, len(subjects))
+
+	for _, subject := range subjects {
+		c.subMutex.Lock()
+		_, subscribed := c.subscriptions[prefixSubject(c.Config, subject)]
+		c.subMutex.Unlock()
+
+		if err := c.SubscribeWithMessageHandler(ctx, subject, handler); err != nil {
+			for _, name := range created {
+				if err := c.Unsubscribe(ctx, name, false); err != nil {
+					c.logger.Errorf("failed to unsubscribe from subject %s: %v", name, err)
+				}
+			}
+
+			return err
+		}
+
+		if !subscribed {
+			created = append(created, subject)
+		}
+	}
+
+	c.logger.Debugf("subscribed to subjects %s", strings.Join(subjects, ", "))
+
+	return nil
+}
+
+// handlerConfig returns the Config of messages passed to handlers, which acknowledge them based on
+// the result of the handler.
+func (c *Client) handlerConfig() *Config {
+	cfg := &Config{ManualAck: true}
+	if c.Config != nil {
+		cfg.SubjectPrefix = c.Config.SubjectPrefix
+		cfg.DoubleAck = c.Config.DoubleAck
+	}
+
+	return cfg
+}
+
+// Unsubscribe stops consuming from subject while keeping the connection open, for subscriptions made
+// with either Subscribe or SubscribeWithHandler, and drains the responders registered for subject.
+// When deleteConsumer is set, the durable consumer of the subject is deleted as well, discarding its
+// position in the stream.
+func (c *Client) Unsubscribe(ctx context.Context, subject string, deleteConsumer bool) error {
+	return newNatsError(SubscribeError, c.unsubscribe(ctx, prefixSubject(c.Config, subject), deleteConsumer))
+}
+
+func (c *Client) unsubscribe(ctx context.Context, subject string, deleteConsumer bool) error {
+	c.subMutex.Lock()
+	_, handled := c.subscriptions[subject]
+	c.cancelExistingSubscription(subject)
+	delete(c.handlers, subject)
+	c.subMutex.Unlock()
+
+	c.pausedSubjects().set(subject, false)
+
+	responded := c.drainResponders(subject)
+	subscribed := c.subManager.Unsubscribe(subject) || handled
+
+	if !subscribed && !responded {
+		return fmt.Errorf("%w: %s", errSubscriptionNotFound, subject)
+	}
+
+	c.logger.Debugf("unsubscribed from subject %s", subject)
+
+	if !subscribed || !deleteConsumer || !isDurable(c.Config) {
+		return nil
+	}
+
+	js, err := c.connManager.jetStream()
+	if err != nil {
+		return err
+	}
+
+	stream, consumer := consumerStream(c.Config, subject), durableName(c.Config, subject)
+	if err := js.DeleteConsumer(ctx, stream, consumer); err != nil {
+		return fmt.Errorf("failed to delete consumer %s on stream %s: %w", consumer, stream, err)
+	}
+
+	c.logger.Logf("deleted consumer %s on stream %s", consumer, stream)
+
 	return nil
 }
 
@@ -150,18 +702,35 @@ func (c *Client) cancelExistingSubscription(subject string) {
 	if cancel, exists := c.subscriptions[subject]; exists {
 		cancel()
 		delete(c.subscriptions, subject)
+		c.active.add(-1)
 	}
 }
 
 func (c *Client) createOrUpdateConsumer(
 	ctx context.Context, js jetstream.JetStream, subject, consumerName string) (jetstream.Consumer, error) {
-	cons, err := js.CreateOrUpdateConsumer(ctx, c.Config.Stream.Stream, jetstream.ConsumerConfig{
-		Durable:       consumerName,
+	if c.Config.OrderedConsumer {
+		return js.OrderedConsumer(ctx, consumerStream(c.Config, subject), orderedConsumerConfig(c.Config, subject))
+	}
+
+	if c.Config.AttachOnly {
+		return attachConsumer(ctx, js, consumerStream(c.Config, subject), consumerName)
+	}
+
+	consumerCfg := jetstream.ConsumerConfig{
 		AckPolicy:     jetstream.AckExplicitPolicy,
-		FilterSubject: subject,
-		MaxDeliver:    c.Config.Stream.MaxDeliver,
-		DeliverPolicy: jetstream.DeliverNewPolicy,
-	})
+		FilterSubject: consumerFilterSubject(c.Config, subject),
+		MaxDeliver:    consumerMaxDeliver(c.Config),
+		AckWait:       consumerAckWait(c.Config),
+		BackOff:       c.Config.BackoffSchedule,
+	}
+
+	consumerCfg.DeliverPolicy, consumerCfg.OptStartSeq, consumerCfg.OptStartTime = deliverOptions(c.Config, jetstream.DeliverNewPolicy)
+
+	if isDurable(c.Config) {
+		consumerCfg.Durable = consumerName
+	}
+
+	cons, err := createConsumer(ctx, js, consumerStream(c.Config, subject), consumerCfg, c.logger)
 	if err != nil {
 		c.logger.Errorf("failed to create or update consumer: %v", err)
 		return nil, err
@@ -172,14 +741,61 @@ func (c *Client) createOrUpdateConsumer(
 
 func (c *Client) processMessages(ctx context.Context, cons jetstream.Consumer, subject string, handler messageHandler) {
 	for ctx.Err() == nil {
-		if err := c.fetchAndProcessMessages(ctx, cons, subject, handler); err != nil {
+		if c.pausedSubjects().acquire(ctx, subject) != nil {
+			return
+		}
+
+		err := c.fetchAndProcessMessages(ctx, cons, subject, handler)
+		c.pausedSubjects().release()
+
+		if isConsumerDeleted(err) && ctx.Err() == nil {
+			if !c.Config.RecreateConsumerOnDelete {
+				c.logger.Logf("WARN: consumer for subject %s was deleted, stopping the subscription", subject)
+
+				return
+			}
+
+			c.logger.Logf("WARN: consumer for subject %s was deleted, recreating it", subject)
+
+			cons = c.recreateConsumer(ctx, cons, subject)
+
+			continue
+		}
+
+		if err != nil {
 			c.logger.Errorf("Error in message processing loop for subject %s: %v", subject, err)
 		}
 	}
 }
 
+// recreateConsumer creates the consumer of subject again, returning cons when that fails so that
+// the next fetch retries.
+func (c *Client) recreateConsumer(ctx context.Context, cons jetstream.Consumer, subject string) jetstream.Consumer {
+	js, err := c.connManager.jetStream()
+	if err == nil {
+		var recreated jetstream.Consumer
+
+		recreated, err = c.createOrUpdateConsumer(ctx, js, subject, c.generateConsumerName(subject))
+		if err == nil {
+			return recreated
+		}
+	}
+
+	c.logger.Errorf("failed to recreate consumer for subject %s: %v", subject, err)
+
+	select {
+	case <-ctx.Done():
+	case <-clockOrReal(c.clock).After(consumeMessageDelay):
+	}
+
+	return cons
+}
+
 func (c *Client) fetchAndProcessMessages(ctx context.Context, cons jetstream.Consumer, subject string, handler messageHandler) error {
-	msgs, err := cons.Fetch(1, jetstream.FetchMaxWait(c.Config.MaxWait))
+	// fetch enough messages to keep all workers busy
+	batch := max(fetchBatchSize(c.Config), handlerConcurrency(c.Config))
+
+	msgs, err := cons.Fetch(batch, fetchOptions(c.Config)...)
 	if err != nil {
 		if !errors.Is(err, context.DeadlineExceeded) {
 			c.logger.Errorf("Error fetching messages for subject %s: %v", subject, err)
@@ -191,10 +807,18 @@ func (c *Client) fetchAndProcessMessages(ctx context.Context, cons jetstream.Con
 	return c.processFetchedMessages(ctx, msgs, handler, subject)
 }
 
+// processFetchedMessages handles the messages of a batch one at a time, or dispatches them to a pool
+// of Config.Concurrency workers and waits for the handlers in flight to return before the next fetch.
 func (c *Client) processFetchedMessages(ctx context.Context, msgs jetstream.MessageBatch, handler messageHandler, subject string) error {
-	for msg := range msgs.Messages() {
-		if err := c.handleMessage(ctx, msg, handler); err != nil {
-			c.logger.Errorf("Error processing message: %v", err)
+	if handlerConcurrency(c.Config) > 1 {
+		if err := c.dispatchFetchedMessages(ctx, msgs, handler); err != nil {
+			return err
+		}
+	} else {
+		for msg := range msgs.Messages() {
+			if err := c.handleMessage(ctx, msg, handler); err != nil {
+				c.logger.Errorf("Error processing message: %v", err)
+			}
 		}
 	}
 
@@ -206,10 +830,59 @@ func (c *Client) processFetchedMessages(ctx context.Context, msgs jetstream.Mess
 	return nil
 }
 
+func (c *Client) dispatchFetchedMessages(ctx context.Context, msgs jetstream.MessageBatch, handler messageHandler) error {
+	var wg sync.WaitGroup
+
+	workers := make(chan struct{}, handlerConcurrency(c.Config))
+
+	for msg := range msgs.Messages() {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+
+		go func(msg jetstream.Msg) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			if err := c.handleMessage(ctx, msg, handler); err != nil {
+				c.logger.Errorf("Error processing message: %v", err)
+			}
+		}(msg)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
 func (c *Client) handleMessage(ctx context.Context, msg jetstream.Msg, handler messageHandler) error {
+	reportPoisonSuspect(ctx, msg, c.Config, c.logger, c.metrics)
+
+	if c.dropFiltered(ctx, msg) {
+		return nil
+	}
+
+	clk := clockOrReal(c.clock)
+
+	start := clk.Now()
 	err := handler(ctx, msg)
+
+	c.reportSlowHandler(ctx, msg, clk.Now().Sub(start))
+
+	if c.Config.ManualAck || c.Config.OrderedConsumer {
+		return err
+	}
+
 	if err == nil {
-		if ackErr := msg.Ack(); ackErr != nil {
+		if ackErr := ackMessage(ctx, msg, c.Config.DoubleAck); ackErr != nil {
 			c.logger.Errorf("Error sending ACK for message: %v", ackErr)
 			return ackErr
 		}
@@ -228,43 +901,275 @@ func (c *Client) handleMessage(ctx context.Context, msg jetstream.Msg, handler m
 	return err
 }
 
-// Close closes the Client.
+// dropFiltered reports whether msg was dropped by the predicate set with SetSubscribeFilter.
+func (c *Client) dropFiltered(ctx context.Context, msg jetstream.Msg) bool {
+	filter := c.subscribeFilter()
+	if !filter.enabled() {
+		return false
+	}
+
+	pubsubMsg, err := newPubSubMessage(msg, c.handlerConfig())
+	if err != nil {
+		// leave messages that cannot be read to the handler
+		return false
+	}
+
+	return filter.drop(ctx, msg, pubsubMsg, c.logger, c.metrics)
+}
+
+// reportSlowHandler logs a warning and increments app_pubsub_slow_handler_count when a handler took
+// longer than Config.SlowHandlerThreshold.
+func (c *Client) reportSlowHandler(ctx context.Context, msg jetstream.Msg, elapsed time.Duration) {
+	if c.Config.SlowHandlerThreshold <= 0 || elapsed <= c.Config.SlowHandlerThreshold {
+		return
+	}
+
+	subject := msg.Subject()
+
+	c.logger.Logf("WARN: handler for subject %s took %v, longer than %v", subject, elapsed, c.Config.SlowHandlerThreshold)
+
+	if c.metrics != nil {
+		c.metrics.IncrementCounter(ctx, slowHandlerCountMetric, "subject", subject)
+	}
+}
+
+// Close closes the Client. The configured streams are deleted only when Config.DeleteStreamOnClose is set.
 func (c *Client) Close(ctx context.Context) error {
-	c.subManager.Close()
+	c.stopConsuming(ctx)
 
 	if c.connManager != nil {
-		c.connManager.Close(ctx)
+		return c.connManager.Close(ctx)
 	}
 
 	return nil
 }
 
+// Shutdown closes the Client in phases, unlike Close which stops everything at once. It first pauses
+// all subscriptions so that no more messages are fetched, then waits for the handlers of the messages
+// already fetched to return, and finally stops the subscriptions and drains the connection. When ctx is
+// done before the handlers return, the Client is closed anyway and the context error is returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.logger.Logf("shutdown: pausing all subscriptions")
+	c.pausedSubjects().pauseAll()
+
+	c.logger.Logf("shutdown: waiting for in-flight messages to be handled")
+
+	waitErr := c.pausedSubjects().idle(ctx)
+	if waitErr != nil {
+		c.logger.Errorf("shutdown: in-flight messages were not handled in time: %v", waitErr)
+	}
+
+	c.logger.Logf("shutdown: stopping subscriptions")
+	c.stopConsuming(ctx)
+
+	if c.connManager == nil {
+		return waitErr
+	}
+
+	c.logger.Logf("shutdown: draining connection")
+
+	if err := c.connManager.Drain(ctx); err != nil {
+		c.logger.Errorf("shutdown: failed to drain connection: %v", err)
+
+		return err
+	}
+
+	c.logger.Logf("shutdown: complete")
+
+	return waitErr
+}
+
+// stopConsuming stops all subscriptions and responders, deleting the configured streams when
+// Config.DeleteStreamOnClose is set.
+func (c *Client) stopConsuming(ctx context.Context) {
+	c.subManager.Close()
+
+	c.subMutex.Lock()
+	for subject := range c.subscriptions {
+		c.cancelExistingSubscription(subject)
+	}
+	c.subMutex.Unlock()
+
+	c.drainResponders("")
+
+	if c.Config != nil && c.Config.DeleteStreamOnClose && c.streamManager != nil {
+		for _, stream := range configuredStreams(c.Config) {
+			if err := c.streamManager.DeleteStream(ctx, stream.Stream); err != nil {
+				c.logger.Errorf("failed to delete stream %s on close: %v", stream.Stream, err)
+			}
+		}
+	}
+}
+
 // CreateTopic creates a new topic (stream) in NATS jStream.
 func (c *Client) CreateTopic(ctx context.Context, name string) error {
-	return c.streamManager.CreateStream(ctx, StreamConfig{
+	defer c.streamNames.clear()
+
+	return newNatsError(StreamError, c.streamManager.CreateStream(ctx, prefixStreamSubjects(c.Config, StreamConfig{
 		Stream:   name,
 		Subjects: []string{name},
-	})
+	})))
+}
+
+// CreateStreamFromTemplate creates the stream name from a copy of tmpl, for streams created on demand
+// such as one per tenant. Occurrences of "{name}" in the template subjects are replaced with name, and a
+// template without subjects, unless it is a mirror, gives the stream the subject name. Streams already
+// created by the client from a template are not created again.
+func (c *Client) CreateStreamFromTemplate(ctx context.Context, name string, tmpl StreamConfig) error {
+	return newNatsError(StreamError, c.createStreamFromTemplate(ctx, name, tmpl))
+}
+
+func (c *Client) createStreamFromTemplate(ctx context.Context, name string, tmpl StreamConfig) error {
+	c.streamMutex.Lock()
+	defer c.streamMutex.Unlock()
+
+	if _, ok := c.templateStreams[name]; ok {
+		return nil
+	}
+
+	defer c.streamNames.clear()
+
+	if err := c.streamManager.CreateStream(ctx, prefixStreamSubjects(c.Config, streamFromTemplate(name, tmpl))); err != nil {
+		return err
+	}
+
+	if c.templateStreams == nil {
+		c.templateStreams = make(map[string]struct{})
+	}
+
+	c.templateStreams[name] = struct{}{}
+
+	return nil
 }
 
 // DeleteTopic deletes a topic (stream) in NATS jStream.
 func (c *Client) DeleteTopic(ctx context.Context, name string) error {
-	return c.streamManager.DeleteStream(ctx, name)
+	c.streamMutex.Lock()
+	delete(c.templateStreams, name)
+	c.streamMutex.Unlock()
+
+	defer c.streamNames.clear()
+
+	return newNatsError(StreamError, c.streamManager.DeleteStream(ctx, name))
 }
 
 // CreateStream creates a new stream in NATS jStream.
 func (c *Client) CreateStream(ctx context.Context, cfg StreamConfig) error {
-	return c.streamManager.CreateStream(ctx, cfg)
+	defer c.streamNames.clear()
+
+	return newNatsError(StreamError, c.streamManager.CreateStream(ctx, prefixStreamSubjects(c.Config, cfg)))
+}
+
+// UpdateStream changes the configuration of an existing stream in NATS jStream, e.g. its retention or limits.
+func (c *Client) UpdateStream(ctx context.Context, cfg StreamConfig) error {
+	defer c.streamNames.clear()
+
+	return newNatsError(StreamError, c.streamManager.UpdateStream(ctx, prefixStreamSubjects(c.Config, cfg)))
 }
 
 // DeleteStream deletes a stream in NATS jStream.
 func (c *Client) DeleteStream(ctx context.Context, name string) error {
-	return c.streamManager.DeleteStream(ctx, name)
+	defer c.streamNames.clear()
+
+	return newNatsError(StreamError, c.streamManager.DeleteStream(ctx, name))
+}
+
+// DeleteStreamsByPrefix deletes every stream whose name starts with prefix, e.g. the streams of an
+// offboarded tenant, and returns how many were deleted. A stream that fails to be deleted does not
+// stop the others; the returned error joins the failures.
+func (c *Client) DeleteStreamsByPrefix(ctx context.Context, prefix string) (int, error) {
+	deleted, err := c.deleteStreamsByPrefix(ctx, prefix)
+
+	return deleted, newNatsError(StreamError, err)
+}
+
+func (c *Client) deleteStreamsByPrefix(ctx context.Context, prefix string) (int, error) {
+	if prefix == "" {
+		return 0, errStreamPrefixRequired
+	}
+
+	defer c.streamNames.clear()
+
+	names, err := c.streamManager.StreamNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	errs := make([]error, 0)
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if err := c.streamManager.DeleteStream(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete stream %s: %w", name, err))
+
+			continue
+		}
+
+		c.streamMutex.Lock()
+		delete(c.templateStreams, name)
+		c.streamMutex.Unlock()
+
+		deleted++
+	}
+
+	c.logger.Logf("deleted %d streams with prefix %s", deleted, prefix)
+
+	return deleted, errors.Join(errs...)
 }
 
 // CreateOrUpdateStream creates or updates a stream in NATS jStream.
 func (c *Client) CreateOrUpdateStream(ctx context.Context, cfg *jetstream.StreamConfig) (jetstream.Stream, error) {
-	return c.streamManager.CreateOrUpdateStream(ctx, cfg)
+	defer c.streamNames.clear()
+
+	stream, err := c.streamManager.CreateOrUpdateStream(ctx, cfg)
+
+	return stream, newNatsError(StreamError, err)
+}
+
+// ConsumerInfo returns the state of a consumer on a stream in NATS jStream.
+func (c *Client) ConsumerInfo(ctx context.Context, stream, consumer string) (*jetstream.ConsumerInfo, error) {
+	info, err := c.streamManager.ConsumerInfo(ctx, stream, consumer)
+
+	return info, newNatsError(StreamError, err)
+}
+
+// PurgeStream removes the messages of a stream in NATS jStream without deleting the stream.
+func (c *Client) PurgeStream(ctx context.Context, stream string, opts ...jetstream.StreamPurgeOpt) error {
+	return newNatsError(StreamError, c.streamManager.PurgeStream(ctx, stream, opts...))
+}
+
+// Flush blocks until all buffered messages, including those of PublishAsync, have been sent to and
+// processed by the server, or ctx is done. Use it for a deterministic point after a burst of publishes.
+func (c *Client) Flush(ctx context.Context) error {
+	if c.connManager == nil {
+		return newNatsError(ConnectionError, errConnectionNotEstablished)
+	}
+
+	return newNatsError(ConnectionError, c.connManager.Flush(ctx))
+}
+
+// RTT returns the round trip time to the NATS server, for latency monitoring.
+func (c *Client) RTT(ctx context.Context) (time.Duration, error) {
+	if c.connManager == nil {
+		return 0, newNatsError(ConnectionError, errConnectionNotEstablished)
+	}
+
+	rtt, err := c.connManager.RTT(ctx)
+
+	return rtt, newNatsError(ConnectionError, err)
+}
+
+// Status returns the current status of the NATS connection.
+func (c *Client) Status() nats.Status {
+	if c.connManager == nil {
+		return nats.DISCONNECTED
+	}
+
+	return c.connManager.Status()
 }
 
 // GetJetStreamStatus returns the status of the jStream connection.
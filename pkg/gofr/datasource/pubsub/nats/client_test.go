@@ -2,10 +2,14 @@ package nats
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,8 +20,277 @@ import (
 	"gofr.dev/pkg/gofr/testutil"
 )
 
-func TestValidateConfigs(*testing.T) {
-	// This test remains unchanged
+func TestValidateConfigs(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *Config
+		err    error
+	}{
+		{
+			desc:   "durable consumer with name",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer"},
+		},
+		{
+			desc:   "durable consumer without name",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}},
+			err:    errConsumerRequiredForDurable,
+		},
+		{
+			desc:   "ephemeral consumer without name",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Ephemeral: true},
+		},
+		{
+			desc:   "queue group without consumer name",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, QueueGroup: "workers"},
+		},
+		{
+			desc: "queue group with ephemeral consumer",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}},
+				QueueGroup: "workers", Ephemeral: true},
+			err: errQueueGroupWithEphemeral,
+		},
+		{
+			desc: "filter subject within stream subjects",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"events.*"}}, Consumer: "test-consumer",
+				FilterSubject: "events.payment"},
+		},
+		{
+			desc: "filter subject outside stream subjects",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"events.*"}}, Consumer: "test-consumer",
+				FilterSubject: "orders.created"},
+			err: errFilterSubjectNotInStream,
+		},
+		{
+			desc:   "ordered consumer",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, OrderedConsumer: true},
+		},
+		{
+			desc: "ordered consumer with consumer name",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				OrderedConsumer: true},
+			err: errOrderedConsumerConflict,
+		},
+		{
+			desc: "ordered consumer with queue group",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, QueueGroup: "workers",
+				OrderedConsumer: true},
+			err: errOrderedConsumerConflict,
+		},
+		{
+			desc: "idle heartbeat with flow control",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				MaxWait: time.Second, FlowControl: true, IdleHeartbeat: 200 * time.Millisecond},
+		},
+		{
+			desc: "idle heartbeat with ordered consumer",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, OrderedConsumer: true,
+				IdleHeartbeat: 200 * time.Millisecond},
+		},
+		{
+			desc: "idle heartbeat without flow control",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				IdleHeartbeat: 200 * time.Millisecond},
+			err: errHeartbeatWithoutFlowControl,
+		},
+		{
+			desc: "idle heartbeat longer than half of max wait",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				MaxWait: time.Second, FlowControl: true, IdleHeartbeat: time.Second},
+			err: errHeartbeatTooLong,
+		},
+		{
+			desc: "start from sequence and time",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				StartFrom: StartFrom{StartSequence: 10, StartTime: time.Now()}},
+			err: errStartFromConflict,
+		},
+		{
+			desc: "unlimited max deliver",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				MaxDeliver: -1},
+		},
+		{
+			desc: "invalid max deliver",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				MaxDeliver: -2},
+			err: errInvalidMaxDeliver,
+		},
+		{
+			desc: "non-positive backoff delay",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				BackoffSchedule: []time.Duration{time.Second, 0}},
+			err: fmt.Errorf("%w: %v", errInvalidBackoff, time.Duration(0)),
+		},
+		{
+			desc: "attach only without consumer",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, QueueGroup: "workers",
+				AttachOnly: true},
+			err: errAttachOnlyRequiresConsumer,
+		},
+		{
+			desc: "circuit breaker without open duration",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 3}},
+			err: errInvalidCircuitBreaker,
+		},
+		{
+			desc: "backoff schedule longer than max deliver",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				MaxDeliver: 3, BackoffSchedule: []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}},
+			err: fmt.Errorf("%w: max deliver %d, %d backoff delays", errBackoffExceedsMaxDeliver, 3, 3),
+		},
+		{
+			desc: "backoff schedule with unlimited deliveries",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				BackoffSchedule: []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}},
+		},
+		{
+			desc: "multiple auth methods",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				Token: "secret-token", CredsFile: "user.creds"},
+			err: errMultipleAuthMethods,
+		},
+		{
+			desc: "nkey file with token",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				Token: "secret-token", NKeyFile: "user.nk"},
+			err: errMultipleAuthMethods,
+		},
+		{
+			desc: "jStream domain with API prefix",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				JetStreamDomain: "hub", APIPrefix: "$JS.hub.API"},
+			err: errDomainWithAPIPrefix,
+		},
+		{
+			desc:   "empty servers",
+			config: &Config{Servers: []string{" "}, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer"},
+			err:    errServerNotProvided,
+		},
+		{
+			desc: "wildcard in subject prefix",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				SubjectPrefix: "prod.*."},
+			err: fmt.Errorf("%w: %q", errInvalidSubjectPrefix, "prod.*."),
+		},
+		{
+			desc: "single auth method",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				Username: "user", Password: "pass"},
+		},
+		{
+			desc: "TLS cert without key",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				TLS: &TLSConfig{CertFile: "client.crt"}},
+			err: errCertAndKeyRequired,
+		},
+		{
+			desc: "TLS cert and key",
+			config: &Config{Server: NATSServer, Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer",
+				TLS: &TLSConfig{CertFile: "client.crt", KeyFile: "client.key"}},
+		},
+		{
+			desc:   "subjects only on additional streams",
+			config: &Config{Server: NATSServer, Streams: []StreamConfig{{Stream: "s1", Subjects: []string{"a.b"}}}, Consumer: "test-consumer"},
+		},
+		{
+			desc:   "missing server",
+			config: &Config{Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer"},
+			err:    errServerNotProvided,
+		},
+		{
+			desc:   "server without scheme",
+			config: &Config{Server: "localhost:4222", Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer"},
+			err:    fmt.Errorf("%w: %q", errInvalidServerURL, "localhost:4222"),
+		},
+		{
+			desc: "server list with an unsupported scheme",
+			config: &Config{Server: "nats://n1:4222,http://n2:4222", Stream: StreamConfig{Subjects: []string{"test-subject"}},
+				Consumer: "test-consumer"},
+			err: fmt.Errorf("%w: %q", errInvalidServerURL, "http://n2:4222"),
+		},
+		{
+			desc: "server list",
+			config: &Config{Server: "nats://n1:4222, tls://n2:4222,nats://n3:4222", Stream: StreamConfig{Subjects: []string{"test-subject"}},
+				Consumer: "test-consumer"},
+		},
+		{
+			desc:   "empty server list",
+			config: &Config{Server: " , ", Stream: StreamConfig{Subjects: []string{"test-subject"}}, Consumer: "test-consumer"},
+			err:    errServerNotProvided,
+		},
+		{
+			desc:   "missing subjects",
+			config: &Config{Server: NATSServer, Consumer: "test-consumer"},
+			err:    errSubjectsNotProvided,
+		},
+	}
+
+	for i, tc := range testCases {
+		err := validateConfigs(tc.config)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestMessageBuckets(t *testing.T) {
+	assert.Equal(t, []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576}, messageBuckets(&Config{}))
+	assert.Equal(t, []float64{100, 1000}, messageBuckets(&Config{MetricBuckets: []float64{100, 1000}}))
+}
+
+func TestSubjectSubsetOf(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		filter  string
+		subset  bool
+	}{
+		{pattern: "events.*", filter: "events.payment", subset: true},
+		{pattern: "events.*", filter: "events.*", subset: true},
+		{pattern: "events.>", filter: "events.*.created", subset: true},
+		{pattern: "events.>", filter: "events.>", subset: true},
+		{pattern: "events.*", filter: "events.>", subset: false},
+		{pattern: "events.*", filter: "events.payment.created", subset: false},
+		{pattern: "events.payment", filter: "events.*", subset: false},
+		{pattern: "events.>", filter: "events", subset: false},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.subset, subjectSubsetOf(tc.pattern, tc.filter), "TEST[%d], Failed.\n%s in %s", i, tc.filter, tc.pattern)
+	}
+}
+
+func TestStreamForSubject(t *testing.T) {
+	conf := &Config{
+		Stream: StreamConfig{Stream: "orders", Subjects: []string{"orders.*"}},
+		Streams: []StreamConfig{
+			{Stream: "shipments", Subjects: []string{"shipments.>"}},
+			{Stream: "audit", Subjects: []string{"audit.log"}},
+		},
+	}
+
+	testCases := []struct {
+		subject string
+		stream  string
+		found   bool
+	}{
+		{subject: "orders.created", stream: "orders", found: true},
+		{subject: "orders.created.eu", found: false},
+		{subject: "shipments.eu.dispatched", stream: "shipments", found: true},
+		{subject: "shipments", found: false},
+		{subject: "audit.log", stream: "audit", found: true},
+		{subject: "unknown", found: false},
+	}
+
+	for i, tc := range testCases {
+		stream, found := streamForSubject(conf, tc.subject)
+
+		assert.Equal(t, tc.found, found, "TEST[%d], Failed.\n%s", i, tc.subject)
+		assert.Equal(t, tc.stream, stream, "TEST[%d], Failed.\n%s", i, tc.subject)
+	}
+
+	assert.Equal(t, "orders", consumerStream(conf, "unknown"))
+	assert.Len(t, configuredStreams(conf), 3)
+	assert.Len(t, configuredStreams(&Config{Streams: conf.Streams}), 2)
 }
 
 func TestNATSClient_Publish(t *testing.T) {
@@ -91,695 +364,2167 @@ func TestNATSClient_PublishError(t *testing.T) {
 		Return(expectedErr)
 
 	err := client.Publish(ctx, subject, message)
-	require.Error(t, err)
-	assert.Equal(t, expectedErr, err)
+	require.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, err, &NatsError{Kind: PublishError})
 }
 
-func TestNATSClient_SubscribeSuccess(t *testing.T) {
+func TestNATSClient_PublishWithHeaders(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
-	mockConnManager := NewMockConnectionManagerInterface(ctrl)
 	mockMetrics := NewMockMetrics(ctrl)
-	mockJetStream := NewMockJetStream(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
 
 	client := &Client{
 		connManager: mockConnManager,
-		subManager:  mockSubManager,
-		Config: &Config{
-			Stream: StreamConfig{
-				Stream:   "test-stream",
-				Subjects: []string{"test-subject"},
-			},
-			Consumer: "test-consumer",
-			MaxWait:  time.Second,
-		},
-		metrics: mockMetrics,
-		logger:  logging.NewMockLogger(logging.DEBUG),
+		Config:      &Config{},
+		metrics:     mockMetrics,
+		logger:      logging.NewMockLogger(logging.DEBUG),
 	}
 
 	ctx := context.Background()
-	expectedMsg := &pubsub.Message{
-		Topic: "test-subject",
-		Value: []byte("test message"),
-	}
-
-	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).AnyTimes()
-
-	mockSubManager.EXPECT().
-		Subscribe(ctx, "test-subject", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(expectedMsg, nil)
+	headers := nats.Header{"X-Trace-Id": []string{"abc"}}
 
-	msg, err := client.Subscribe(ctx, "test-subject")
+	mockConnManager.EXPECT().
+		PublishWithHeaders(ctx, "test-subject", []byte("test-message"), headers, mockMetrics).
+		Return(nil)
 
+	err := client.PublishWithHeaders(ctx, "test-subject", []byte("test-message"), headers)
 	require.NoError(t, err)
-	assert.Equal(t, expectedMsg, msg)
 }
 
-func TestNATSClient_SubscribeError(t *testing.T) {
+func TestNATSClient_PublishAck(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
-	mockConnManager := NewMockConnectionManagerInterface(ctrl)
 	mockMetrics := NewMockMetrics(ctrl)
-	mockJetStream := NewMockJetStream(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
 
 	client := &Client{
 		connManager: mockConnManager,
-		subManager:  mockSubManager,
-		Config: &Config{
-			Stream: StreamConfig{
-				Stream:   "test-stream",
-				Subjects: []string{"test-subject"},
-			},
-			Consumer: "test-consumer",
-		},
-		metrics: mockMetrics,
-		logger:  logging.NewMockLogger(logging.DEBUG),
+		Config:      &Config{},
+		metrics:     mockMetrics,
+		logger:      logging.NewMockLogger(logging.DEBUG),
 	}
 
 	ctx := context.Background()
-	expectedErr := errSubscriptionError
+	expected := &jetstream.PubAck{Stream: "orders", Sequence: 42, Duplicate: true}
 
-	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).AnyTimes()
+	mockConnManager.EXPECT().PublishAck(ctx, "orders.created", []byte("test-message"), gomock.Any(), mockMetrics).Return(expected, nil)
 
-	mockSubManager.EXPECT().
-		Subscribe(ctx, "test-subject", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(nil, expectedErr)
+	ack, err := client.PublishAck(ctx, "orders.created", []byte("test-message"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, ack)
+	assert.True(t, ack.Duplicate)
 
-	msg, err := client.Subscribe(ctx, "test-subject")
+	mockConnManager.EXPECT().PublishAck(ctx, "orders.created", gomock.Any(), gomock.Any(), mockMetrics).Return(nil, errPublishError)
 
-	require.Error(t, err)
-	assert.Nil(t, msg)
-	assert.Equal(t, expectedErr, err)
+	ack, err = client.PublishAck(ctx, "orders.created", []byte("test-message"))
+	require.ErrorIs(t, err, errPublishError)
+	require.ErrorIs(t, err, &NatsError{Kind: PublishError})
+	assert.Nil(t, ack)
 }
 
-func TestNATSClient_Close(t *testing.T) {
+func TestNATSClient_PublishWithID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
-	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	logger := logging.NewMockLogger(logging.DEBUG)
 
 	client := &Client{
-		connManager: mockConnManager,
-		subManager:  mockSubManager,
-		logger:      logging.NewMockLogger(logging.DEBUG),
-		Config: &Config{
-			Stream: StreamConfig{
-				Stream: "test-stream",
-			},
-		},
+		connManager: &ConnectionManager{jStream: mockJS, logger: logger},
+		Config:      &Config{},
+		metrics:     mockMetrics,
+		logger:      logger,
 	}
 
 	ctx := context.Background()
+	subject := "test-subject"
+	message := []byte("test-message")
 
-	mockSubManager.EXPECT().Close()
-	mockConnManager.EXPECT().Close(ctx)
-
-	err := client.Close(ctx)
-	require.NoError(t, err)
-}
-
-func TestNew(t *testing.T) {
-	config := &Config{
-		Server: NATSServer,
-		Stream: StreamConfig{
-			Stream:   "test-stream",
-			Subjects: []string{"test-subject"},
-		},
-		Consumer: "test-consumer",
-		MaxWait:  5 * time.Second,
-	}
-
-	mockLogger := logging.NewMockLogger(logging.DEBUG)
-
-	natsClient := New(config, mockLogger)
-	assert.NotNil(t, natsClient)
+	mockMetrics.EXPECT().IncrementCounter(ctx, gomock.Any(), "subject", subject).AnyTimes()
+	mockMetrics.EXPECT().RecordHistogram(ctx, gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-	// Check PubSubWrapper struct
-	assert.NotNil(t, natsClient)
-	assert.NotNil(t, natsClient.Client)
+	mockJS.EXPECT().PublishMsg(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			assert.Equal(t, "msg-1", msg.Header.Get(nats.MsgIdHdr))
+			assert.Equal(t, message, msg.Data)
 
-	// Check Client struct
-	assert.Equal(t, config, natsClient.Client.Config)
-	assert.NotNil(t, natsClient.Client.subManager)
+			return &jetstream.PubAck{}, nil
+		})
 
-	// Check methods
-	assert.NotNil(t, natsClient.DeleteTopic)
-	assert.NotNil(t, natsClient.CreateTopic)
-	assert.NotNil(t, natsClient.Subscribe)
-	assert.NotNil(t, natsClient.Publish)
-	assert.NotNil(t, natsClient.Close)
+	err := client.PublishWithID(ctx, subject, message, "msg-1")
+	require.NoError(t, err)
 
-	// Check new methods
-	assert.NotNil(t, natsClient.UseLogger)
-	assert.NotNil(t, natsClient.UseMetrics)
-	assert.NotNil(t, natsClient.UseTracer)
-	assert.NotNil(t, natsClient.Connect)
+	// an empty id falls back to a plain publish
+	mockJS.EXPECT().Publish(ctx, subject, message).Return(&jetstream.PubAck{}, nil)
 
-	// Check that Connect hasn't been called yet
-	assert.Nil(t, natsClient.Client.connManager)
+	err = client.PublishWithID(ctx, subject, message, "")
+	require.NoError(t, err)
 }
 
-func TestNATSClient_DeleteTopic(t *testing.T) {
+func TestNATSClient_PublishWithID_LocalDedup(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+
 	client := &Client{
-		streamManager: mockStreamManager,
-		logger:        logging.NewMockLogger(logging.DEBUG),
-		Config:        &Config{},
+		connManager: mockConnManager,
+		Config:      &Config{DedupCacheSize: 2},
+		metrics:     mockMetrics,
+		logger:      logging.NewMockLogger(logging.DEBUG),
 	}
 
 	ctx := context.Background()
-
-	mockStreamManager.EXPECT().DeleteStream(ctx, "test-topic").Return(nil)
-
-	err := client.DeleteTopic(ctx, "test-topic")
-	require.NoError(t, err)
+	subject := "orders.created"
+	message := []byte("order")
+
+	published := make([]string, 0, 5)
+
+	mockConnManager.EXPECT().PublishWithHeaders(ctx, subject, message, gomock.Any(), mockMetrics).
+		DoAndReturn(func(_ context.Context, _ string, _ []byte, headers nats.Header, _ Metrics) error {
+			published = append(published, headers.Get(nats.MsgIdHdr))
+
+			// the first attempt of msg-2 fails
+			if len(published) == 2 {
+				return errJetStream
+			}
+
+			return nil
+		}).Times(5)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_local_dedup_count", "subject", subject).Times(2)
+
+	require.NoError(t, client.PublishWithID(ctx, subject, message, "msg-1"))
+	// a hit is skipped locally
+	require.NoError(t, client.PublishWithID(ctx, subject, message, "msg-1"))
+	// a failed publish is not remembered, so it can be retried
+	require.ErrorIs(t, client.PublishWithID(ctx, subject, message, "msg-2"), errJetStream)
+	require.NoError(t, client.PublishWithID(ctx, subject, message, "msg-2"))
+	// msg-1 is the least recently used ID and is evicted by msg-3
+	require.NoError(t, client.PublishWithID(ctx, subject, message, "msg-3"))
+	require.NoError(t, client.PublishWithID(ctx, subject, message, "msg-3"))
+	require.NoError(t, client.PublishWithID(ctx, subject, message, "msg-1"))
+
+	assert.Equal(t, []string{"msg-1", "msg-2", "msg-2", "msg-3", "msg-1"}, published)
 }
 
-func TestNATSClient_DeleteTopic_Error(t *testing.T) {
+func TestNATSClient_PublishAsync(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockFuture := NewMockPubAckFuture(ctrl)
+
 	client := &Client{
-		streamManager: mockStreamManager,
-		logger:        logging.NewMockLogger(logging.DEBUG),
-		Config:        &Config{},
+		connManager: mockConnManager,
+		Config:      &Config{},
+		metrics:     mockMetrics,
+		logger:      logging.NewMockLogger(logging.DEBUG),
 	}
 
 	ctx := context.Background()
+	subject := "test-subject"
+	message := []byte("test-message")
 
-	expectedErr := errFailedToDeleteStream
-	mockStreamManager.EXPECT().DeleteStream(ctx, "test-topic").Return(expectedErr)
+	mockConnManager.EXPECT().PublishAsync(ctx, subject, message, gomock.Any(), mockMetrics).Return(mockFuture, nil)
+	mockConnManager.EXPECT().PublishAsyncComplete(ctx).Return(nil)
 
-	err := client.DeleteTopic(ctx, "test-topic")
-	require.Error(t, err)
-	assert.Equal(t, expectedErr, err)
+	future, err := client.PublishAsync(ctx, subject, message)
+	require.NoError(t, err)
+	assert.Equal(t, mockFuture, future)
+
+	err = client.PublishAsyncComplete(ctx)
+	require.NoError(t, err)
 }
 
-func TestNATSClient_CreateTopic(t *testing.T) {
+func TestNATSClient_Request(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+
 	client := &Client{
-		streamManager: mockStreamManager,
-		logger:        logging.NewMockLogger(logging.DEBUG),
-		Config:        &Config{},
+		connManager: mockConnManager,
+		metrics:     mockMetrics,
+		logger:      logging.NewMockLogger(logging.DEBUG),
 	}
 
 	ctx := context.Background()
+	expectedMsg := &pubsub.Message{Topic: "test.rpc", Value: []byte("pong")}
 
-	mockStreamManager.EXPECT().
-		CreateStream(ctx, StreamConfig{
-			Stream:   "test-topic",
-			Subjects: []string{"test-topic"},
-		}).
-		Return(nil)
+	mockConnManager.EXPECT().Request(ctx, "test.rpc", []byte("ping"), mockMetrics).Return(expectedMsg, nil)
 
-	err := client.CreateTopic(ctx, "test-topic")
+	msg, err := client.Request(ctx, "test.rpc", []byte("ping"))
 	require.NoError(t, err)
+	assert.Equal(t, expectedMsg, msg)
 }
 
-func TestClient_Connect(t *testing.T) {
+func TestNATSClient_SubscribeSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Create mocks
-	mockLogger := logging.NewMockLogger(logging.DEBUG)
-	mockNATSConnector := NewMockNATSConnector(ctrl)
-	mockJSCreator := NewMockJetStreamCreator(ctrl)
-	mockConn := NewMockConnInterface(ctrl)
-	mockJS := NewMockJetStream(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
 
-	// Set up client with mocks
 	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
 		Config: &Config{
-			Server: "nats://localhost:4222",
 			Stream: StreamConfig{
 				Stream:   "test-stream",
 				Subjects: []string{"test-subject"},
 			},
 			Consumer: "test-consumer",
+			MaxWait:  time.Second,
 		},
-		logger:           mockLogger,
-		natsConnector:    mockNATSConnector,
-		jetStreamCreator: mockJSCreator,
+		metrics: mockMetrics,
+		logger:  logging.NewMockLogger(logging.DEBUG),
 	}
 
-	// Set expectations
-	mockNATSConnector.EXPECT().
-		Connect("nats://localhost:4222", gomock.Any()).
-		Return(mockConn, nil).
-		Times(2)
+	ctx := context.Background()
+	expectedMsg := &pubsub.Message{
+		Topic: "test-subject",
+		Value: []byte("test message"),
+	}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).AnyTimes()
+
+	mockSubManager.EXPECT().
+		Subscribe(ctx, "test-subject", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(expectedMsg, nil)
+
+	msg, err := client.Subscribe(ctx, "test-subject")
+
+	require.NoError(t, err)
+	assert.Equal(t, expectedMsg, msg)
+}
+
+func TestNATSClient_SubjectPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMsg := NewMockMsg(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  newSubscriptionManager(1),
+		Config: &Config{
+			Stream:        StreamConfig{Stream: "orders", Subjects: []string{"orders"}},
+			Consumer:      "test-consumer",
+			MaxWait:       time.Second,
+			SubjectPrefix: "prod.",
+		},
+		metrics: mockMetrics,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// the application publishes to orders, which goes to prod.orders
+	mockConnManager.EXPECT().Publish(ctx, "prod.orders", []byte("test message"), mockMetrics).Return(nil)
+
+	require.NoError(t, client.Publish(ctx, "orders", []byte("test message")))
+
+	msgChan := make(chan jetstream.Msg, 1)
+	msgChan <- mockMsg
+	close(msgChan)
+
+	mockMsg.EXPECT().Subject().Return("prod.orders").AnyTimes()
+	mockMsg.EXPECT().Data().Return([]byte("test message"))
+	mockMsg.EXPECT().Headers().Return(nil)
+	mockBatch.EXPECT().Messages().Return(msgChan).AnyTimes()
+	mockBatch.EXPECT().Error().Return(nil).AnyTimes()
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "orders", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Equal(t, "prod.orders", consumerCfg.FilterSubject)
+
+			return mockConsumer, nil
+		})
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil).AnyTimes()
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), gomock.Any(), "topic", "prod.orders").Times(2)
+	mockMetrics.EXPECT().RecordHistogram(gomock.Any(), "app_pubsub_message_bytes", gomock.Any(), gomock.Any()).AnyTimes()
+
+	// the subscribed message is reported without the prefix
+	msg, err := client.Subscribe(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", msg.Topic)
+
+	client.subManager.Close()
+}
+
+func TestNATSClient_SubscribeWithOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config: &Config{
+			Stream:     StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}},
+			Consumer:   "orders-consumer",
+			QueueGroup: "workers",
+			MaxWait:    time.Second,
+			BatchSize:  1,
+		},
+		logger: logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	expectedMsg := &pubsub.Message{Topic: "orders.created"}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).Times(2)
+	mockSubManager.EXPECT().
+		Subscribe(gomock.Any(), "orders.created", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ jetstream.JetStream, cfg *Config, _ pubsub.Logger,
+			_ Metrics) (*pubsub.Message, error) {
+			assert.Equal(t, 50*time.Millisecond, cfg.MaxWait)
+			assert.Equal(t, 10, cfg.BatchSize)
+			assert.Equal(t, "audit_orders_created", durableName(cfg, "orders.created"))
+
+			return expectedMsg, nil
+		})
+	mockSubManager.EXPECT().
+		Subscribe(gomock.Any(), "orders.updated", mockJetStream, client.Config, gomock.Any(), gomock.Any()).
+		Return(expectedMsg, nil)
+
+	msg, err := client.SubscribeWithOptions(ctx, "orders.created",
+		SubscribeOptions{MaxWait: 50 * time.Millisecond, BatchSize: 10, Durable: "audit"})
+	require.NoError(t, err)
+	assert.Equal(t, expectedMsg, msg)
+
+	// zero options keep the Config, which is left untouched by the overrides
+	_, err = client.SubscribeWithOptions(ctx, "orders.updated", SubscribeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, client.Config.MaxWait)
+	assert.Equal(t, "workers", client.Config.QueueGroup)
+}
+
+func TestNATSClient_SubscribeChan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      &Config{Consumer: "orders-consumer", BatchSize: 5},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const count = 3
+
+	delivered := 0
+
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).AnyTimes()
+	mockSubManager.EXPECT().
+		Subscribe(gomock.Any(), "orders.created", mockJetStream, client.Config, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, topic string, _ jetstream.JetStream, _ *Config, _ pubsub.Logger,
+			_ Metrics) (*pubsub.Message, error) {
+			if delivered == count {
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			}
+
+			delivered++
+
+			return &pubsub.Message{Topic: topic, Value: []byte{byte(delivered)}}, nil
+		}).MinTimes(count)
+
+	msgs, err := client.SubscribeChan(ctx, "orders.created")
+	require.NoError(t, err)
+	assert.Equal(t, 5, cap(msgs))
+
+	received := 0
+
+	for msg := range msgs {
+		received++
+
+		assert.Equal(t, []byte{byte(received)}, msg.Value)
+
+		if received == count {
+			cancel()
+		}
+	}
+
+	assert.Equal(t, count, received)
+}
+
+func TestNATSClient_SubscribeChan_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      &Config{Consumer: "orders-consumer"},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	mockConnManager.EXPECT().JetStream().Return(nil, errJetStreamNotConfigured)
+
+	_, err := client.SubscribeChan(context.Background(), "orders.created")
+	require.ErrorIs(t, err, errJetStreamNotConfigured)
+
+	// the channel is closed once the subscription fails
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).Times(2)
+	mockSubManager.EXPECT().
+		Subscribe(gomock.Any(), "orders.created", mockJetStream, client.Config, gomock.Any(), gomock.Any()).
+		Return(nil, errConsumerNotProvided)
+
+	msgs, err := client.SubscribeChan(context.Background(), "orders.created")
+	require.NoError(t, err)
+
+	_, ok := <-msgs
+	assert.False(t, ok)
+}
+
+func TestNATSClient_Subscribe_CoreFallbackUnsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      &Config{Consumer: "orders-consumer", FallbackToCoreNATS: true},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil)
+	mockSubManager.EXPECT().
+		Subscribe(gomock.Any(), "orders.created", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, jetstream.ErrJetStreamNotEnabled)
+
+	_, err := client.Subscribe(context.Background(), "orders.created")
+	require.ErrorIs(t, err, errCoreFallbackSubscribe)
+	require.ErrorIs(t, err, jetstream.ErrJetStreamNotEnabled)
+}
+
+func TestNATSClient_SubscribeError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config: &Config{
+			Stream: StreamConfig{
+				Stream:   "test-stream",
+				Subjects: []string{"test-subject"},
+			},
+			Consumer: "test-consumer",
+		},
+		metrics: mockMetrics,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	expectedErr := errSubscriptionError
+
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil).AnyTimes()
+
+	mockSubManager.EXPECT().
+		Subscribe(ctx, "test-subject", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, expectedErr)
+
+	msg, err := client.Subscribe(ctx, "test-subject")
+
+	require.ErrorIs(t, err, expectedErr)
+	assert.Nil(t, msg)
+	assert.ErrorIs(t, err, &NatsError{Kind: SubscribeError})
+}
+
+func TestNATSClient_Subscribe_Closed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockJetStream := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      createTestConfig(),
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().JetStream().Return(mockJetStream, nil)
+	// a subscription closed while waiting returns no message
+	mockSubManager.EXPECT().Subscribe(ctx, "test-subject", mockJetStream, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+
+	msg, err := client.Subscribe(ctx, "test-subject")
+
+	require.NoError(t, err)
+	assert.Nil(t, msg)
+}
+
+func TestNATSClient_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+
+	client := &Client{
+		connManager:   mockConnManager,
+		subManager:    mockSubManager,
+		streamManager: mockStreamManager,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		Config: &Config{
+			Stream: StreamConfig{
+				Stream: "test-stream",
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	t.Run("Without stream deletion", func(t *testing.T) {
+		mockSubManager.EXPECT().Close()
+		mockStreamManager.EXPECT().DeleteStream(gomock.Any(), gomock.Any()).Times(0)
+		mockConnManager.EXPECT().Close(ctx).Return(nil)
+
+		err := client.Close(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("With stream deletion", func(t *testing.T) {
+		client.Config.DeleteStreamOnClose = true
+
+		mockSubManager.EXPECT().Close()
+		mockStreamManager.EXPECT().DeleteStream(ctx, "test-stream").Return(nil)
+		mockConnManager.EXPECT().Close(ctx).Return(nil)
+
+		err := client.Close(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("Drain timeout", func(t *testing.T) {
+		client.Config.DeleteStreamOnClose = false
+
+		mockSubManager.EXPECT().Close()
+		mockConnManager.EXPECT().Close(ctx).Return(errDrainTimeout)
+
+		err := client.Close(ctx)
+		require.ErrorIs(t, err, errDrainTimeout)
+	})
+}
+
+func TestNew(t *testing.T) {
+	config := &Config{
+		Server: NATSServer,
+		Stream: StreamConfig{
+			Stream:   "test-stream",
+			Subjects: []string{"test-subject"},
+		},
+		Consumer: "test-consumer",
+		MaxWait:  5 * time.Second,
+	}
+
+	mockLogger := logging.NewMockLogger(logging.DEBUG)
+
+	natsClient := New(config, mockLogger)
+	assert.NotNil(t, natsClient)
+
+	// Check PubSubWrapper struct
+	assert.NotNil(t, natsClient)
+	assert.NotNil(t, natsClient.Client)
+
+	// Check Client struct
+	assert.Equal(t, config, natsClient.Client.Config)
+	assert.NotNil(t, natsClient.Client.subManager)
+
+	// Check methods
+	assert.NotNil(t, natsClient.DeleteTopic)
+	assert.NotNil(t, natsClient.CreateTopic)
+	assert.NotNil(t, natsClient.Subscribe)
+	assert.NotNil(t, natsClient.Publish)
+	assert.NotNil(t, natsClient.Close)
+
+	// Check new methods
+	assert.NotNil(t, natsClient.UseLogger)
+	assert.NotNil(t, natsClient.UseMetrics)
+	assert.NotNil(t, natsClient.UseTracer)
+	assert.NotNil(t, natsClient.Connect)
+
+	// Check that Connect hasn't been called yet
+	assert.Nil(t, natsClient.Client.connManager)
+}
+
+func TestNATSClient_DeleteTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		Config:        &Config{},
+	}
+
+	ctx := context.Background()
+
+	mockStreamManager.EXPECT().DeleteStream(ctx, "test-topic").Return(nil)
+
+	err := client.DeleteTopic(ctx, "test-topic")
+	require.NoError(t, err)
+}
+
+func TestNATSClient_DeleteTopic_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		Config:        &Config{},
+	}
+
+	ctx := context.Background()
+
+	expectedErr := errFailedToDeleteStream
+	mockStreamManager.EXPECT().DeleteStream(ctx, "test-topic").Return(expectedErr)
+
+	err := client.DeleteTopic(ctx, "test-topic")
+	require.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, err, &NatsError{Kind: StreamError})
+}
+
+func TestNATSClient_CreateTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		Config:        &Config{},
+	}
+
+	ctx := context.Background()
+
+	mockStreamManager.EXPECT().
+		CreateStream(ctx, StreamConfig{
+			Stream:   "test-topic",
+			Subjects: []string{"test-topic"},
+		}).
+		Return(nil)
+
+	err := client.CreateTopic(ctx, "test-topic")
+	require.NoError(t, err)
+
+	// the stream captures the prefixed subject, while keeping the name of the topic
+	client.Config = &Config{SubjectPrefix: "prod."}
+
+	mockStreamManager.EXPECT().
+		CreateStream(ctx, StreamConfig{
+			Stream:   "test-topic",
+			Subjects: []string{"prod.test-topic"},
+		}).
+		Return(nil)
+
+	require.NoError(t, client.CreateTopic(ctx, "test-topic"))
+}
+
+func TestClient_UpdateStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		Config:        &Config{},
+	}
+
+	ctx := context.Background()
+	cfg := StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}, MaxAge: 24 * time.Hour}
+
+	mockStreamManager.EXPECT().UpdateStream(ctx, cfg).Return(nil)
+
+	require.NoError(t, client.UpdateStream(ctx, cfg))
+}
+
+func TestClient_CreateStreamFromTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	logger := logging.NewMockLogger(logging.DEBUG)
+
+	client := &Client{
+		streamManager: newStreamManager(mockJS, logger),
+		logger:        logger,
+		Config:        &Config{},
+	}
+
+	ctx := context.Background()
+	tmpl := StreamConfig{Subjects: []string{"{name}.orders.>", "{name}.events.>"}, MaxAge: time.Hour}
+
+	created := make([]jetstream.StreamConfig, 0, 2)
+
+	mockJS.EXPECT().CreateStream(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error) {
+			created = append(created, cfg)
+
+			return NewMockStream(ctrl), nil
+		}).Times(2)
+
+	require.NoError(t, client.CreateStreamFromTemplate(ctx, "tenant-1", tmpl))
+	require.NoError(t, client.CreateStreamFromTemplate(ctx, "tenant-2", tmpl))
+	// streams already created are not created again
+	require.NoError(t, client.CreateStreamFromTemplate(ctx, "tenant-1", tmpl))
+
+	require.Len(t, created, 2)
+	assert.Equal(t, "tenant-1", created[0].Name)
+	assert.Equal(t, []string{"tenant-1.orders.>", "tenant-1.events.>"}, created[0].Subjects)
+	assert.Equal(t, "tenant-2", created[1].Name)
+	assert.Equal(t, []string{"tenant-2.orders.>", "tenant-2.events.>"}, created[1].Subjects)
+	assert.Equal(t, time.Hour, created[1].MaxAge)
+
+	// the template itself is left untouched
+	assert.Equal(t, []string{"{name}.orders.>", "{name}.events.>"}, tmpl.Subjects)
+}
+
+func TestStreamFromTemplate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		tmpl     StreamConfig
+		subjects []string
+	}{
+		{desc: "no subjects", subjects: []string{"tenant-1"}},
+		{desc: "subject without placeholder", tmpl: StreamConfig{Subjects: []string{"orders"}}, subjects: []string{"orders"}},
+		{desc: "mirror", tmpl: StreamConfig{Mirror: &StreamSource{Name: "orders"}}},
+	}
+
+	for i, tc := range testCases {
+		cfg := streamFromTemplate("tenant-1", tc.tmpl)
+
+		assert.Equal(t, "tenant-1", cfg.Stream, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.subjects, cfg.Subjects, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestClient_CreateStreamFromTemplate_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{streamManager: mockStreamManager, logger: logging.NewMockLogger(logging.DEBUG), Config: &Config{}}
+	ctx := context.Background()
+
+	// a failed creation is not cached, so it is attempted again
+	mockStreamManager.EXPECT().CreateStream(ctx, StreamConfig{Stream: "tenant-1", Subjects: []string{"tenant-1"}}).
+		Return(errJetStream).Times(2)
+
+	require.ErrorIs(t, client.CreateStreamFromTemplate(ctx, "tenant-1", StreamConfig{}), errJetStream)
+	require.ErrorIs(t, client.CreateStreamFromTemplate(ctx, "tenant-1", StreamConfig{}), errJetStream)
+}
+
+func TestClient_Connect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mocks
+	mockLogger := logging.NewMockLogger(logging.DEBUG)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+	mockConn := NewMockConnInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	// Set up client with mocks
+	client := &Client{
+		Config: &Config{
+			Server: "nats://localhost:4222",
+			Stream: StreamConfig{
+				Stream:   "test-stream",
+				Subjects: []string{"test-subject"},
+			},
+			Consumer: "test-consumer",
+		},
+		logger:           mockLogger,
+		metrics:          mockMetrics,
+		natsConnector:    mockNATSConnector,
+		jetStreamCreator: mockJSCreator,
+	}
+
+	// Set expectations
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_dlq_total_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_nats_reconnect_total_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_nats_disconnect_total_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_publish_validation_error_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_publish_retry_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_fetch_timeout_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_fetch_error_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_local_dedup_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_core_fallback_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_slow_handler_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_rate_limited_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_filtered_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_poison_suspect_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_request_total_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewCounter("app_pubsub_request_success_count", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewGauge("app_pubsub_consumer_pending", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewGauge("app_pubsub_buffer_depth", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewGauge("app_pubsub_active_subscriptions", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewGauge("app_pubsub_circuit_state", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewGauge("app_nats_rtt_seconds", gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewHistogram("app_pubsub_publish_duration_seconds", gomock.Any(), gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewHistogram("app_pubsub_message_bytes", gomock.Any(), gomock.Any()).
+		Times(1)
+	mockMetrics.EXPECT().
+		NewHistogram("app_nats_objectstore_bytes", gomock.Any(), gomock.Any()).
+		Times(1)
+
+	mockNATSConnector.EXPECT().
+		Connect("nats://localhost:4222", gomock.Any()).
+		Return(mockConn, nil).
+		Times(2)
+
+	mockJSCreator.EXPECT().
+		New(mockConn).
+		Return(mockJS, nil).
+		Times(2)
+
+	mockConn.EXPECT().
+		MaxPayload().
+		Return(int64(1048576)).
+		Times(2)
+
+	// Call the Connect method on the client
+	err := client.Connect()
+	require.NoError(t, err)
+
+	// Assert that the connection manager was set
+	assert.NotNil(t, client.connManager)
+
+	// Assert that the stream manager and subscription manager were created
+	assert.NotNil(t, client.streamManager)
+	assert.NotNil(t, client.subManager)
+
+	// Check for log output
+	out := testutil.StdoutOutputForFunc(func() {
+		client.logger = logging.NewMockLogger(logging.DEBUG)
+		err := client.Connect()
+		require.NoError(t, err)
+	})
+
+	// Assert that the expected log message is produced
+	assert.Contains(t, out, "connected to NATS server 'nats://localhost:4222'")
+}
+
+func TestClient_ConnectError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+
+	config := &Config{
+		Server: "nats://localhost:4222",
+		Stream: StreamConfig{
+			Stream:   "test-stream",
+			Subjects: []string{"test-subject"},
+		},
+		Consumer: "test-consumer",
+	}
+
+	client := &Client{
+		Config:           config,
+		logger:           logging.NewMockLogger(logging.DEBUG),
+		natsConnector:    mockNATSConnector,
+		jetStreamCreator: mockJSCreator,
+	}
+
+	// Simulate a connection error
+	expectedErr := errConnectionError
+	mockNATSConnector.EXPECT().
+		Connect(config.Server, gomock.Any()).
+		Return(nil, expectedErr)
+
+	// Capture stderr output
+	output := testutil.StderrOutputForFunc(func() {
+		client.logger = logging.NewMockLogger(logging.DEBUG)
+		err := client.Connect()
+		require.ErrorIs(t, err, expectedErr)
+		assert.ErrorIs(t, err, &NatsError{Kind: ConnectionError})
+	})
+
+	// Check for the error log
+	assert.Contains(t, output, "failed to connect to NATS server at nats://localhost:4222: connection error")
+
+	// Assert that the connection manager, stream manager, and subscription manager were not set
+	assert.Nil(t, client.connManager)
+	assert.Nil(t, client.streamManager)
+	assert.Nil(t, client.subManager)
+}
+
+func TestClient_ValidateAndPrepare(t *testing.T) {
+	client := &Client{
+		Config: &Config{},
+		logger: logging.NewMockLogger(logging.DEBUG),
+	}
+
+	err := client.validateAndPrepare()
+	require.Error(t, err)
+
+	client.Config = &Config{
+		Server: "nats://localhost:4222",
+		Stream: StreamConfig{
+			Stream:   "test-stream",
+			Subjects: []string{"test-subject"},
+		},
+		Consumer: "test-consumer",
+	}
+
+	err = client.validateAndPrepare()
+	assert.NoError(t, err)
+}
+
+func TestClient_LogSuccessfulConnection(t *testing.T) {
+	mockLogger := logging.NewMockLogger(logging.DEBUG)
+	client := &Client{
+		Config: &Config{Server: "nats://localhost:4222"},
+		logger: mockLogger,
+	}
+
+	logs := testutil.StdoutOutputForFunc(func() {
+		client.logger = logging.NewMockLogger(logging.DEBUG)
+		client.logSuccessfulConnection()
+	})
+
+	assert.Contains(t, logs, "connected to NATS server 'nats://localhost:4222'")
+}
+
+func TestClient_UseLogger(t *testing.T) {
+	client := &Client{}
+	mockLogger := logging.NewMockLogger(logging.DEBUG)
+
+	// the logger is wrapped to carry the logger name
+	client.UseLogger(mockLogger)
+	assert.Equal(t, newNamedLogger(mockLogger, nil), client.logger)
+
+	client.UseLogger("not a logger")
+	assert.Equal(t, newNamedLogger(mockLogger, nil), client.logger) // Should not change
+}
+
+func TestClient_UseTracer(t *testing.T) {
+	client := &Client{}
+	mockTracer := noop.NewTracerProvider().Tracer("test")
+
+	client.UseTracer(mockTracer)
+	assert.Equal(t, mockTracer, client.tracer)
+
+	client.UseTracer("not a tracer")
+	assert.Equal(t, mockTracer, client.tracer) // Should not change
+}
+
+func TestClient_UseMetrics(t *testing.T) {
+	client := &Client{}
+	mockMetrics := NewMockMetrics(gomock.NewController(t))
+
+	client.UseMetrics(mockMetrics)
+	assert.Equal(t, mockMetrics, client.metrics)
+
+	client.UseMetrics("not metrics")
+	assert.Equal(t, mockMetrics, client.metrics) // Should not change
+}
+
+func TestClient_SubscribeWithHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client, mocks := setupClientAndMocks(t, ctrl)
+
+	var wg sync.WaitGroup
+
+	wg.Add(2) // Two handlers
+
+	t.Run("First_Subscription", func(t *testing.T) {
+		testFirstSubscription(t, client, mocks, &wg)
+	})
+
+	t.Run("Second_Subscription", func(t *testing.T) {
+		testSecondSubscription(t, client, mocks, &wg)
+	})
+
+	waitForHandlersToComplete(t, &wg)
+
+	err := client.Close(context.Background())
+	require.NoError(t, err)
+}
+
+func setupClientAndMocks(t *testing.T, ctrl *gomock.Controller) (*Client, *testMocks) {
+	t.Helper()
+
+	mocks := &testMocks{
+		connManager:   NewMockConnectionManagerInterface(ctrl),
+		jetStream:     NewMockJetStream(ctrl),
+		consumer1:     NewMockConsumer(ctrl),
+		messageBatch1: NewMockMessageBatch(ctrl),
+		msg1:          NewMockMsg(ctrl),
+		consumer2:     NewMockConsumer(ctrl),
+		messageBatch2: NewMockMessageBatch(ctrl),
+		msg2:          NewMockMsg(ctrl),
+		subManager:    NewMockSubscriptionManagerInterface(ctrl),
+		messageChan1:  make(chan jetstream.Msg, 1),
+		messageChan2:  make(chan jetstream.Msg, 1),
+	}
+
+	client := &Client{
+		connManager:   mocks.connManager,
+		subManager:    mocks.subManager,
+		Config:        createTestConfig(),
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+
+	setupCommonExpectations(mocks)
+
+	return client, mocks
+}
+
+func createTestConfig() *Config {
+	return &Config{
+		Consumer: "test-consumer",
+		Stream: StreamConfig{
+			Stream:     "test-stream",
+			MaxDeliver: 3,
+		},
+		MaxWait: time.Second,
+	}
+}
+
+func setupCommonExpectations(mocks *testMocks) {
+	mocks.connManager.EXPECT().JetStream().Return(mocks.jetStream, nil).AnyTimes()
+	mocks.subManager.EXPECT().Close().Times(1)
+	mocks.connManager.EXPECT().Close(gomock.Any()).AnyTimes()
+}
+
+func testFirstSubscription(t *testing.T, client *Client, mocks *testMocks, wg *sync.WaitGroup) {
+	t.Helper()
+
+	setupFirstSubscriptionExpectations(mocks)
+
+	firstHandlerCalled := make(chan bool, 1)
+	firstHandler := createFirstHandler(t, firstHandlerCalled, wg)
+
+	err := client.SubscribeWithHandler(context.Background(), "test-subject", firstHandler)
+	require.NoError(t, err)
+
+	mocks.messageChan1 <- mocks.msg1
+	close(mocks.messageChan1)
 
-	mockJSCreator.EXPECT().
-		New(mockConn).
-		Return(mockJS, nil).
+	assertHandlerCalled(t, firstHandlerCalled, "First handler")
+}
+
+func testSecondSubscription(t *testing.T, client *Client, mocks *testMocks, wg *sync.WaitGroup) {
+	t.Helper()
+
+	setupSecondSubscriptionExpectations(mocks)
+
+	errorHandlerCalled := make(chan bool, 1)
+	errorHandler := createErrorHandler(t, errorHandlerCalled, wg)
+
+	err := client.SubscribeWithHandler(context.Background(), "test-subject", errorHandler)
+	require.NoError(t, err)
+
+	mocks.messageChan2 <- mocks.msg2
+	close(mocks.messageChan2)
+
+	assertHandlerCalled(t, errorHandlerCalled, "Error handler")
+}
+
+func setupFirstSubscriptionExpectations(mocks *testMocks) {
+	mocks.jetStream.EXPECT().
+		CreateOrUpdateConsumer(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(mocks.consumer1, nil).
+		Times(1)
+
+	mocks.consumer1.EXPECT().
+		Fetch(gomock.Any(), gomock.Any()).
+		Return(mocks.messageBatch1, nil).
 		Times(2)
 
-	// Call the Connect method on the client
-	err := client.Connect()
+	gomock.InOrder(
+		mocks.messageBatch1.EXPECT().
+			Messages().
+			Return(mocks.messageChan1).
+			Times(1),
+
+		mocks.messageBatch1.EXPECT().
+			Messages().
+			Return(nil).
+			Times(1),
+	)
+
+	mocks.messageBatch1.EXPECT().
+		Error().
+		Return(nil).
+		Times(1)
+
+	mocks.msg1.EXPECT().
+		Ack().
+		Return(nil).
+		Times(1)
+}
+
+func setupSecondSubscriptionExpectations(mocks *testMocks) {
+	mocks.jetStream.EXPECT().
+		CreateOrUpdateConsumer(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(mocks.consumer2, nil).
+		Times(1)
+
+	mocks.consumer2.EXPECT().
+		Fetch(gomock.Any(), gomock.Any()).
+		Return(mocks.messageBatch2, nil).
+		Times(2)
+
+	gomock.InOrder(
+		mocks.messageBatch2.EXPECT().
+			Messages().
+			Return(mocks.messageChan2).
+			Times(1),
+
+		mocks.messageBatch2.EXPECT().
+			Messages().
+			Return(nil).
+			Times(1),
+	)
+
+	mocks.messageBatch2.EXPECT().
+		Error().
+		Return(nil).
+		Times(1)
+
+	mocks.msg2.EXPECT().
+		Nak().
+		Return(nil).
+		Times(1)
+}
+
+func createFirstHandler(t *testing.T, handlerCalled chan<- bool, wg *sync.WaitGroup) func(context.Context, jetstream.Msg) error {
+	t.Helper()
+
+	return func(context.Context, jetstream.Msg) error {
+		t.Log("First handler called")
+		handlerCalled <- true
+
+		wg.Done()
+
+		return nil
+	}
+}
+
+func createErrorHandler(t *testing.T, handlerCalled chan<- bool, wg *sync.WaitGroup) func(context.Context, jetstream.Msg) error {
+	t.Helper()
+
+	return func(context.Context, jetstream.Msg) error {
+		t.Log("Error handler called")
+		handlerCalled <- true
+
+		t.Logf("Error handling message: %v", errHandlerError)
+		t.Logf("Error processing message: %v", errHandlerError)
+
+		wg.Done()
+
+		return errHandlerError
+	}
+}
+
+func assertHandlerCalled(t *testing.T, handlerCalled <-chan bool, handlerName string) {
+	t.Helper()
+
+	select {
+	case <-handlerCalled:
+		t.Logf("%s was called successfully", handlerName)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("%s was not called within the expected time", handlerName)
+	}
+}
+
+func waitForHandlersToComplete(t *testing.T, wg *sync.WaitGroup) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// All handlers completed
+	case <-time.After(5 * time.Second):
+		t.Fatal("Handlers did not complete within the expected time")
+	}
+}
+
+type testMocks struct {
+	connManager   *MockConnectionManagerInterface
+	jetStream     *MockJetStream
+	consumer1     *MockConsumer
+	messageBatch1 *MockMessageBatch
+	messageChan1  chan jetstream.Msg
+	msg1          *MockMsg
+	consumer2     *MockConsumer
+	messageBatch2 *MockMessageBatch
+	messageChan2  chan jetstream.Msg
+	msg2          *MockMsg
+	subManager    *MockSubscriptionManagerInterface
+}
+
+func TestClient_CreateStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+	}
+
+	cfg := StreamConfig{
+		Stream:   "test-stream",
+		Subjects: []string{"test-subject"},
+	}
+
+	mockStreamManager.EXPECT().CreateStream(gomock.Any(), cfg).Return(nil)
+
+	err := client.CreateStream(context.Background(), cfg)
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+	}
+
+	mockStreamManager.EXPECT().DeleteStream(gomock.Any(), "test-stream").Return(nil)
+
+	err := client.DeleteStream(context.Background(), "test-stream")
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteStreamsByPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	mockStreamManager.EXPECT().StreamNames(ctx).Return([]string{"tenant-a-orders", "tenant-b-orders", "tenant-a-events"}, nil)
+	mockStreamManager.EXPECT().DeleteStream(ctx, "tenant-a-orders").Return(nil)
+	mockStreamManager.EXPECT().DeleteStream(ctx, "tenant-a-events").Return(nil)
+
+	deleted, err := client.DeleteStreamsByPrefix(ctx, "tenant-a-")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	// a failed deletion does not stop the others
+	mockStreamManager.EXPECT().StreamNames(ctx).Return([]string{"tenant-a-orders", "tenant-b-orders", "tenant-a-events"}, nil)
+	mockStreamManager.EXPECT().DeleteStream(ctx, "tenant-a-orders").Return(errDeleteStream)
+	mockStreamManager.EXPECT().DeleteStream(ctx, "tenant-a-events").Return(nil)
+
+	deleted, err = client.DeleteStreamsByPrefix(ctx, "tenant-a-")
+	require.ErrorIs(t, err, errDeleteStream)
+	require.ErrorContains(t, err, "tenant-a-orders")
+	assert.Equal(t, 1, deleted)
+
+	_, err = client.DeleteStreamsByPrefix(ctx, "")
+	require.ErrorIs(t, err, errStreamPrefixRequired)
+}
+
+func TestClient_PurgeStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+	}
+
+	mockStreamManager.EXPECT().PurgeStream(gomock.Any(), "test-stream").Return(nil)
+
+	err := client.PurgeStream(context.Background(), "test-stream")
 	require.NoError(t, err)
+}
+
+func TestClient_CreateOrUpdateStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockStream := NewMockStream(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+	}
+
+	cfg := jetstream.StreamConfig{
+		Name:     "test-stream",
+		Subjects: []string{"test-subject"},
+	}
+
+	mockStreamManager.EXPECT().CreateOrUpdateStream(gomock.Any(), &cfg).Return(mockStream, nil)
+
+	stream, err := client.CreateOrUpdateStream(context.Background(), &cfg)
+	require.NoError(t, err)
+	assert.Equal(t, mockStream, stream)
+}
+
+func TestNATSClient_AttachConsumer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
 
-	// Assert that the connection manager was set
-	assert.NotNil(t, client.connManager)
+	ctx := context.Background()
 
-	// Assert that the stream manager and subscription manager were created
-	assert.NotNil(t, client.streamManager)
-	assert.NotNil(t, client.subManager)
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(2)
+	mockJS.EXPECT().Consumer(ctx, "orders", "provisioned").Return(mockConsumer, nil)
+	mockJS.EXPECT().Consumer(ctx, "orders", "missing").Return(nil, jetstream.ErrConsumerNotFound)
 
-	// Check for log output
-	out := testutil.StdoutOutputForFunc(func() {
-		client.logger = logging.NewMockLogger(logging.DEBUG)
-		err := client.Connect()
-		require.NoError(t, err)
-	})
+	consumer, err := client.AttachConsumer(ctx, "orders", "provisioned")
+	require.NoError(t, err)
+	assert.Equal(t, mockConsumer, consumer)
 
-	// Assert that the expected log message is produced
-	assert.Contains(t, out, "connected to NATS server 'nats://localhost:4222'")
+	_, err = client.AttachConsumer(ctx, "orders", "missing")
+	require.ErrorIs(t, err, errConsumerNotFound)
+	require.ErrorIs(t, err, &NatsError{Kind: SubscribeError})
 }
 
-func TestClient_ConnectError(t *testing.T) {
+func TestNATSClient_AckBatch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockNATSConnector := NewMockNATSConnector(ctrl)
-	mockJSCreator := NewMockJetStreamCreator(ctrl)
+	client := &Client{}
+	msgs := make([]*pubsub.Message, 4)
+	mocks := make([]*MockMsg, 4)
 
-	config := &Config{
-		Server: "nats://localhost:4222",
-		Stream: StreamConfig{
-			Stream:   "test-stream",
-			Subjects: []string{"test-subject"},
-		},
-		Consumer: "test-consumer",
+	for i := range msgs {
+		mocks[i] = NewMockMsg(ctrl)
+		msgs[i] = pubsub.NewMessage(context.Background())
+		msgs[i].Committer = &natsCommitter{msg: mocks[i], manualAck: true}
 	}
 
-	client := &Client{
-		Config:           config,
-		logger:           logging.NewMockLogger(logging.DEBUG),
-		natsConnector:    mockNATSConnector,
-		jetStreamCreator: mockJSCreator,
-	}
+	gomock.InOrder(
+		mocks[0].EXPECT().Ack().Return(nil),
+		mocks[1].EXPECT().Ack().Return(nil),
+		mocks[2].EXPECT().Ack().Return(errJetStream),
+	)
 
-	// Simulate a connection error
-	expectedErr := errConnectionError
-	mockNATSConnector.EXPECT().
-		Connect(config.Server, gomock.Any()).
-		Return(nil, expectedErr)
+	// the fourth message is never acknowledged as the batch stops at the third
+	err := client.AckBatch(context.Background(), msgs)
+	require.ErrorIs(t, err, errAckFailed)
+	require.ErrorIs(t, err, errJetStream)
+	assert.Contains(t, err.Error(), "at index 2")
 
-	// Capture stderr output
-	output := testutil.StderrOutputForFunc(func() {
-		client.logger = logging.NewMockLogger(logging.DEBUG)
-		err := client.Connect()
-		require.Error(t, err)
-		assert.Equal(t, expectedErr, err)
-	})
+	// already acknowledged messages are skipped on retry
+	mocks[2].EXPECT().Ack().Return(nil)
+	mocks[3].EXPECT().Ack().Return(nil)
 
-	// Check for the error log
-	assert.Contains(t, output, "failed to connect to NATS server at nats://localhost:4222: connection error")
+	require.NoError(t, client.AckBatch(context.Background(), msgs))
 
-	// Assert that the connection manager, stream manager, and subscription manager were not set
-	assert.Nil(t, client.connManager)
-	assert.Nil(t, client.streamManager)
-	assert.Nil(t, client.subManager)
+	err = client.AckBatch(context.Background(), []*pubsub.Message{pubsub.NewMessage(context.Background())})
+	require.ErrorIs(t, err, errNotNATSMessage)
 }
 
-func TestClient_ValidateAndPrepare(t *testing.T) {
+func TestNATSClient_createOrUpdateConsumer_Redelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
 	client := &Client{
-		Config: &Config{},
+		Config: &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"},
+			AckWait: 10 * time.Second, MaxDeliver: 4},
 		logger: logging.NewMockLogger(logging.DEBUG),
 	}
 
-	err := client.validateAndPrepare()
-	require.Error(t, err)
+	ctx := context.Background()
 
-	client.Config = &Config{
-		Server: "nats://localhost:4222",
-		Stream: StreamConfig{
-			Stream:   "test-stream",
-			Subjects: []string{"test-subject"},
-		},
-		Consumer: "test-consumer",
-	}
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "test-stream", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Equal(t, 10*time.Second, consumerCfg.AckWait)
+			assert.Equal(t, 4, consumerCfg.MaxDeliver)
 
-	err = client.validateAndPrepare()
-	assert.NoError(t, err)
+			return NewMockConsumer(ctrl), nil
+		})
+
+	_, err := client.createOrUpdateConsumer(ctx, mockJS, "test-subject", "test-consumer_test-subject")
+	require.NoError(t, err)
 }
 
-func TestClient_LogSuccessfulConnection(t *testing.T) {
-	mockLogger := logging.NewMockLogger(logging.DEBUG)
-	client := &Client{
-		Config: &Config{Server: "nats://localhost:4222"},
-		logger: mockLogger,
-	}
+func TestNATSClient_Status(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	logs := testutil.StdoutOutputForFunc(func() {
-		client.logger = logging.NewMockLogger(logging.DEBUG)
-		client.logSuccessfulConnection()
-	})
+	client := &Client{}
+	assert.Equal(t, nats.DISCONNECTED, client.Status())
 
-	assert.Contains(t, logs, "connected to NATS server 'nats://localhost:4222'")
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockConnManager.EXPECT().Status().Return(nats.CONNECTED)
+
+	client.connManager = mockConnManager
+	assert.Equal(t, nats.CONNECTED, client.Status())
 }
 
-func TestClient_UseLogger(t *testing.T) {
+func TestNATSClient_Flush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	client := &Client{}
-	mockLogger := logging.NewMockLogger(logging.DEBUG)
+	require.ErrorIs(t, client.Flush(context.Background()), errConnectionNotEstablished)
 
-	client.UseLogger(mockLogger)
-	assert.Equal(t, mockLogger, client.logger)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockConnManager.EXPECT().Flush(gomock.Any()).Return(errConnectionError)
 
-	client.UseLogger("not a logger")
-	assert.Equal(t, mockLogger, client.logger) // Should not change
+	client.connManager = mockConnManager
+	require.ErrorIs(t, client.Flush(context.Background()), errConnectionError)
 }
 
-func TestClient_UseTracer(t *testing.T) {
+func TestNATSClient_RTT(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	client := &Client{}
-	mockTracer := noop.NewTracerProvider().Tracer("test")
 
-	client.UseTracer(mockTracer)
-	assert.Equal(t, mockTracer, client.tracer)
+	_, err := client.RTT(context.Background())
+	require.ErrorIs(t, err, errConnectionNotEstablished)
 
-	client.UseTracer("not a tracer")
-	assert.Equal(t, mockTracer, client.tracer) // Should not change
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockConnManager.EXPECT().RTT(gomock.Any()).Return(time.Millisecond, nil)
+
+	client.connManager = mockConnManager
+
+	rtt, err := client.RTT(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Millisecond, rtt)
 }
 
-func TestClient_UseMetrics(t *testing.T) {
-	client := &Client{}
-	mockMetrics := NewMockMetrics(gomock.NewController(t))
+func TestNATSClient_PublishBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	client.UseMetrics(mockMetrics)
-	assert.Equal(t, mockMetrics, client.metrics)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	client := &Client{connManager: mockConnManager, metrics: mockMetrics}
 
-	client.UseMetrics("not metrics")
-	assert.Equal(t, mockMetrics, client.metrics) // Should not change
+	msgs := [][]byte{[]byte("first"), []byte("second")}
+
+	mockConnManager.EXPECT().PublishBatch(gomock.Any(), "test.subject", msgs, mockMetrics).Return(errPublishError)
+
+	require.ErrorIs(t, client.PublishBatch(context.Background(), "test.subject", msgs), errPublishError)
 }
 
-func TestClient_SubscribeWithHandler(t *testing.T) {
+func TestNATSClient_PublishValidator(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	client, mocks := setupClientAndMocks(t, ctrl)
-
-	var wg sync.WaitGroup
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	client := &Client{connManager: mockConnManager, Config: &Config{}, metrics: mockMetrics, logger: logging.NewMockLogger(logging.DEBUG)}
 
-	wg.Add(2) // Two handlers
+	client.SetPublishValidator(func(_ string, msg []byte) error {
+		if !json.Valid(msg) {
+			return errInvalidJSON
+		}
 
-	t.Run("First_Subscription", func(t *testing.T) {
-		testFirstSubscription(t, client, mocks, &wg)
+		return nil
 	})
 
-	t.Run("Second_Subscription", func(t *testing.T) {
-		testSecondSubscription(t, client, mocks, &wg)
-	})
+	ctx := context.Background()
 
-	waitForHandlersToComplete(t, &wg)
+	mockConnManager.EXPECT().Publish(ctx, "orders.created", []byte(`{"id":1}`), mockMetrics).Return(nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_validation_error_count", "subject", "orders.created").Times(3)
 
-	err := client.Close(context.Background())
-	require.NoError(t, err)
+	require.NoError(t, client.Publish(ctx, "orders.created", []byte(`{"id":1}`)))
+
+	err := client.Publish(ctx, "orders.created", []byte("not json"))
+	require.ErrorIs(t, err, errPublishValidation)
+	require.ErrorIs(t, err, errInvalidJSON)
+
+	_, err = client.PublishAsync(ctx, "orders.created", []byte("not json"))
+	require.ErrorIs(t, err, errPublishValidation)
+
+	// the batch is rejected before any message is published
+	err = client.PublishBatch(ctx, "orders.created", [][]byte{[]byte(`{"id":1}`), []byte("not json")})
+	require.ErrorIs(t, err, errPublishValidation)
+	assert.Contains(t, err.Error(), "at index 1")
+
+	client.SetPublishValidator(nil)
+
+	mockConnManager.EXPECT().Publish(ctx, "orders.created", []byte("not json"), mockMetrics).Return(nil)
+
+	require.NoError(t, client.Publish(ctx, "orders.created", []byte("not json")))
 }
 
-func setupClientAndMocks(t *testing.T, ctrl *gomock.Controller) (*Client, *testMocks) {
-	t.Helper()
+func TestNATSClient_Unsubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	mocks := &testMocks{
-		connManager:   NewMockConnectionManagerInterface(ctrl),
-		jetStream:     NewMockJetStream(ctrl),
-		consumer1:     NewMockConsumer(ctrl),
-		messageBatch1: NewMockMessageBatch(ctrl),
-		msg1:          NewMockMsg(ctrl),
-		consumer2:     NewMockConsumer(ctrl),
-		messageBatch2: NewMockMessageBatch(ctrl),
-		msg2:          NewMockMsg(ctrl),
-		subManager:    NewMockSubscriptionManagerInterface(ctrl),
-		messageChan1:  make(chan jetstream.Msg, 1),
-		messageChan2:  make(chan jetstream.Msg, 1),
-	}
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
 
 	client := &Client{
-		connManager:   mocks.connManager,
-		subManager:    mocks.subManager,
-		Config:        createTestConfig(),
+		connManager:   mockConnManager,
+		subManager:    mockSubManager,
+		subscriptions: make(map[string]context.CancelFunc),
+		Config:        &Config{Stream: StreamConfig{Stream: "test-stream", Subjects: []string{"test.subject"}}, Consumer: "test-consumer"},
 		logger:        logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	subCtx, cancel := context.WithCancel(ctx)
+	client.subscriptions["test.subject"] = cancel
+
+	mockSubManager.EXPECT().Unsubscribe("test.subject").Return(false)
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().DeleteConsumer(ctx, "test-stream", "test-consumer_test_subject").Return(nil)
+
+	require.NoError(t, client.Unsubscribe(ctx, "test.subject", true))
+	require.ErrorIs(t, subCtx.Err(), context.Canceled)
+	assert.NotContains(t, client.subscriptions, "test.subject")
+
+	// Subscribe subscriptions are kept by the subscription manager
+	mockSubManager.EXPECT().Unsubscribe("test.subject").Return(true)
+
+	require.NoError(t, client.Unsubscribe(ctx, "test.subject", false))
+
+	mockSubManager.EXPECT().Unsubscribe("test.subject").Return(false)
+
+	require.ErrorIs(t, client.Unsubscribe(ctx, "test.subject", true), errSubscriptionNotFound)
+}
+
+func TestClient_ActiveSubscriptionsGauge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	active := newSubscriptionCount(mockMetrics)
+	subManager := newSubscriptionManager(1)
+	subManager.active = active
+
+	client := &Client{
+		connManager:   mockConnManager,
+		subManager:    subManager,
 		subscriptions: make(map[string]context.CancelFunc),
+		Config:        &Config{Stream: StreamConfig{Stream: "orders"}, Consumer: "orders-consumer"},
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		metrics:       mockMetrics,
+		active:        active,
 	}
 
-	setupCommonExpectations(mocks)
+	ctx := context.Background()
+	stop := make(chan struct{})
 
-	return client, mocks
+	var gauge float64
+
+	mockMetrics.EXPECT().SetGauge("app_pubsub_active_subscriptions", gomock.Any()).
+		Do(func(_ string, value float64, _ ...string) { gauge = value }).AnyTimes()
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(2)
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "orders", gomock.Any()).Return(mockConsumer, nil).Times(2)
+	// fetches block until the test ends
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+			<-stop
+
+			return nil, context.Canceled
+		}).AnyTimes()
+	mockConnManager.EXPECT().Close(ctx).Return(nil)
+
+	defer close(stop)
+
+	handler := func(context.Context, jetstream.Msg) error { return nil }
+
+	require.NoError(t, client.SubscribeWithHandler(ctx, "orders.created", handler))
+	require.NoError(t, client.SubscribeWithHandler(ctx, "orders.updated", handler))
+	assert.InDelta(t, 2, gauge, 0)
+
+	require.NoError(t, client.Unsubscribe(ctx, "orders.created", false))
+	assert.InDelta(t, 1, gauge, 0)
+
+	require.NoError(t, client.Close(ctx))
+	assert.InDelta(t, 0, gauge, 0)
 }
 
-func createTestConfig() *Config {
-	return &Config{
-		Consumer: "test-consumer",
-		Stream: StreamConfig{
-			Stream:     "test-stream",
-			MaxDeliver: 3,
-		},
-		MaxWait: time.Second,
+func TestClient_processFetchedMessages_Concurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const concurrency = 3
+
+	client := &Client{Config: &Config{Concurrency: concurrency}, logger: logging.NewMockLogger(logging.DEBUG)}
+	mockBatch := NewMockMessageBatch(ctrl)
+
+	msgChan := make(chan jetstream.Msg, 2*concurrency)
+
+	for range 2 * concurrency {
+		mockMsg := NewMockMsg(ctrl)
+		mockMsg.EXPECT().Ack().Return(nil)
+
+		msgChan <- mockMsg
+	}
+
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+
+	var active, peak atomic.Int32
+
+	release := make(chan struct{})
+
+	handler := func(context.Context, jetstream.Msg) error {
+		current := active.Add(1)
+		defer active.Add(-1)
+
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+
+		<-release
+
+		return nil
 	}
+
+	done := make(chan error)
+
+	go func() {
+		done <- client.processFetchedMessages(context.Background(), mockBatch, handler, "test-subject")
+	}()
+
+	// the pool fills up to the limit and no further handler starts until one returns
+	assert.Eventually(t, func() bool { return active.Load() == concurrency }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(concurrency), active.Load())
+
+	close(release)
+
+	require.NoError(t, <-done)
+	assert.Equal(t, int32(concurrency), peak.Load())
+	assert.Zero(t, active.Load())
 }
 
-func setupCommonExpectations(mocks *testMocks) {
-	mocks.connManager.EXPECT().JetStream().Return(mocks.jetStream, nil).AnyTimes()
-	mocks.subManager.EXPECT().Close().Times(1)
-	mocks.connManager.EXPECT().Close(gomock.Any()).AnyTimes()
+func TestClient_processFetchedMessages_ContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := &Client{Config: &Config{Concurrency: 2}, logger: logging.NewMockLogger(logging.DEBUG)}
+	mockBatch := NewMockMessageBatch(ctrl)
+
+	msgChan := make(chan jetstream.Msg, 3)
+
+	for i := 0; i < 2; i++ {
+		msg := NewMockMsg(ctrl)
+		msg.EXPECT().Ack().Return(nil)
+		msgChan <- msg
+	}
+
+	msgChan <- NewMockMsg(ctrl)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var started sync.WaitGroup
+
+	var finished atomic.Int32
+
+	started.Add(2)
+
+	handler := func(context.Context, jetstream.Msg) error {
+		started.Done()
+		time.Sleep(20 * time.Millisecond)
+
+		finished.Add(1)
+
+		return nil
+	}
+
+	go func() {
+		started.Wait()
+		cancel()
+	}()
+
+	// the handlers in flight complete before the pool returns; the third message is not dispatched
+	err := client.processFetchedMessages(ctx, mockBatch, handler, "test-subject")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(2), finished.Load())
 }
 
-func testFirstSubscription(t *testing.T, client *Client, mocks *testMocks, wg *sync.WaitGroup) {
-	t.Helper()
+func TestClient_handleMessage_SlowHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	setupFirstSubscriptionExpectations(mocks)
+	mockMetrics := NewMockMetrics(ctrl)
+	ctx := context.Background()
 
-	firstHandlerCalled := make(chan bool, 1)
-	firstHandler := createFirstHandler(t, firstHandlerCalled, wg)
+	slowMsg := NewMockMsg(ctrl)
+	slowMsg.EXPECT().Subject().Return("orders.created")
+	slowMsg.EXPECT().Ack().Return(nil)
 
-	err := client.SubscribeWithHandler(context.Background(), "test-subject", firstHandler)
-	require.NoError(t, err)
+	fastMsg := NewMockMsg(ctrl)
+	fastMsg.EXPECT().Ack().Return(nil)
 
-	mocks.messageChan1 <- mocks.msg1
-	close(mocks.messageChan1)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_slow_handler_count", "subject", "orders.created")
 
-	assertHandlerCalled(t, firstHandlerCalled, "First handler")
-}
+	handler := func(_ context.Context, msg jetstream.Msg) error {
+		if msg == slowMsg {
+			time.Sleep(30 * time.Millisecond)
+		}
 
-func testSecondSubscription(t *testing.T, client *Client, mocks *testMocks, wg *sync.WaitGroup) {
-	t.Helper()
+		return nil
+	}
 
-	setupSecondSubscriptionExpectations(mocks)
+	out := testutil.StdoutOutputForFunc(func() {
+		client := &Client{Config: &Config{SlowHandlerThreshold: 10 * time.Millisecond}, metrics: mockMetrics,
+			logger: logging.NewMockLogger(logging.DEBUG)}
 
-	errorHandlerCalled := make(chan bool, 1)
-	errorHandler := createErrorHandler(t, errorHandlerCalled, wg)
+		require.NoError(t, client.handleMessage(ctx, slowMsg, handler))
+		require.NoError(t, client.handleMessage(ctx, fastMsg, handler))
+	})
 
-	err := client.SubscribeWithHandler(context.Background(), "test-subject", errorHandler)
-	require.NoError(t, err)
+	assert.Contains(t, out, "WARN: handler for subject orders.created took")
+	assert.Contains(t, out, "longer than 10ms")
+}
 
-	mocks.messageChan2 <- mocks.msg2
-	close(mocks.messageChan2)
+func TestClient_handleMessage_DoubleAck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	assertHandlerCalled(t, errorHandlerCalled, "Error handler")
-}
+	ctx := context.Background()
 
-func setupFirstSubscriptionExpectations(mocks *testMocks) {
-	mocks.jetStream.EXPECT().
-		CreateOrUpdateConsumer(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(mocks.consumer1, nil).
-		Times(1)
+	mockMsg := NewMockMsg(ctrl)
+	mockMsg.EXPECT().Ack().Times(0)
+	mockMsg.EXPECT().DoubleAck(ctx).Return(nil)
 
-	mocks.consumer1.EXPECT().
-		Fetch(gomock.Any(), gomock.Any()).
-		Return(mocks.messageBatch1, nil).
-		Times(2)
+	client := &Client{Config: &Config{DoubleAck: true}, logger: logging.NewMockLogger(logging.DEBUG)}
 
-	gomock.InOrder(
-		mocks.messageBatch1.EXPECT().
-			Messages().
-			Return(mocks.messageChan1).
-			Times(1),
+	require.NoError(t, client.handleMessage(ctx, mockMsg, func(context.Context, jetstream.Msg) error { return nil }))
+}
 
-		mocks.messageBatch1.EXPECT().
-			Messages().
-			Return(nil).
-			Times(1),
-	)
+func TestClient_SubscribeWithMessageHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	mocks.messageBatch1.EXPECT().
-		Error().
-		Return(nil).
-		Times(1)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMsg := NewMockMsg(ctrl)
 
-	mocks.msg1.EXPECT().
-		Ack().
-		Return(nil).
-		Times(1)
-}
+	client := &Client{
+		connManager:   mockConnManager,
+		Config:        createTestConfig(),
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		subscriptions: make(map[string]context.CancelFunc),
+	}
 
-func setupSecondSubscriptionExpectations(mocks *testMocks) {
-	mocks.jetStream.EXPECT().
-		CreateOrUpdateConsumer(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(mocks.consumer2, nil).
-		Times(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	mocks.consumer2.EXPECT().
-		Fetch(gomock.Any(), gomock.Any()).
-		Return(mocks.messageBatch2, nil).
-		Times(2)
+	msgChan := make(chan jetstream.Msg, 1)
+	msgChan <- mockMsg
+	close(msgChan)
 
-	gomock.InOrder(
-		mocks.messageBatch2.EXPECT().
-			Messages().
-			Return(mocks.messageChan2).
-			Times(1),
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+	mockMsg.EXPECT().Headers().Return(nil)
+	mockMsg.EXPECT().Subject().Return("test-subject")
+	mockMsg.EXPECT().Data().Return([]byte("test message"))
+	mockMsg.EXPECT().Ack().Return(nil)
 
-		mocks.messageBatch2.EXPECT().
-			Messages().
-			Return(nil).
-			Times(1),
-	)
+	received := make(chan *pubsub.Message, 1)
 
-	mocks.messageBatch2.EXPECT().
-		Error().
-		Return(nil).
-		Times(1)
+	err := client.SubscribeWithMessageHandler(ctx, "test-subject", func(msg *pubsub.Message) error {
+		received <- msg
 
-	mocks.msg2.EXPECT().
-		Nak().
-		Return(nil).
-		Times(1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "test-subject", msg.Topic)
+		assert.Equal(t, []byte("test message"), msg.Value)
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
 }
 
-func createFirstHandler(t *testing.T, handlerCalled chan<- bool, wg *sync.WaitGroup) func(context.Context, jetstream.Msg) error {
-	t.Helper()
+func TestClient_SubscribeAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	return func(context.Context, jetstream.Msg) error {
-		t.Log("First handler called")
-		handlerCalled <- true
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
 
-		wg.Done()
+	client := &Client{
+		connManager:   mockConnManager,
+		Config:        createTestConfig(),
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		subscriptions: make(map[string]context.CancelFunc),
+	}
 
-		return nil
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subjects := []string{"orders.created", "orders.shipped"}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(len(subjects))
+
+	// every subject gets its own consumer delivering one message
+	for _, subject := range subjects {
+		mockConsumer := NewMockConsumer(ctrl)
+		mockBatch := NewMockMessageBatch(ctrl)
+		mockMsg := NewMockMsg(ctrl)
+
+		msgChan := make(chan jetstream.Msg, 1)
+		msgChan <- mockMsg
+		close(msgChan)
+
+		mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+				assert.Equal(t, subject, cfg.FilterSubject)
+
+				return mockConsumer, nil
+			})
+		mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil)
+		mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+		mockBatch.EXPECT().Messages().Return(msgChan)
+		mockBatch.EXPECT().Error().Return(nil)
+		mockMsg.EXPECT().Headers().Return(nil)
+		mockMsg.EXPECT().Subject().Return(subject)
+		mockMsg.EXPECT().Data().Return([]byte(subject))
+		mockMsg.EXPECT().Ack().Return(nil)
 	}
-}
 
-func createErrorHandler(t *testing.T, handlerCalled chan<- bool, wg *sync.WaitGroup) func(context.Context, jetstream.Msg) error {
-	t.Helper()
+	received := make(chan *pubsub.Message, len(subjects))
 
-	return func(context.Context, jetstream.Msg) error {
-		t.Log("Error handler called")
-		handlerCalled <- true
+	err := client.SubscribeAll(ctx, subjects, func(msg *pubsub.Message) error {
+		received <- msg
 
-		t.Logf("Error handling message: %v", errHandlerError)
-		t.Logf("Error processing message: %v", errHandlerError)
+		return nil
+	})
+	require.NoError(t, err)
 
-		wg.Done()
+	topics := make([]string, 0, len(subjects))
 
-		return errHandlerError
+	for range subjects {
+		select {
+		case msg := <-received:
+			assert.Equal(t, msg.Topic, string(msg.Value))
+
+			topics = append(topics, msg.Topic)
+		case <-time.After(time.Second):
+			t.Fatal("handler was not called")
+		}
 	}
-}
 
-func assertHandlerCalled(t *testing.T, handlerCalled <-chan bool, handlerName string) {
-	t.Helper()
+	assert.ElementsMatch(t, subjects, topics)
 
-	select {
-	case <-handlerCalled:
-		t.Logf("%s was called successfully", handlerName)
-	case <-time.After(time.Second * 5):
-		t.Fatalf("%s was not called within the expected time", handlerName)
-	}
+	client.subMutex.Lock()
+	assert.Len(t, client.subscriptions, len(subjects))
+	client.subMutex.Unlock()
 }
 
-func waitForHandlersToComplete(t *testing.T, wg *sync.WaitGroup) {
-	t.Helper()
+func TestClient_SubscribeAll_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
 
-	select {
-	case <-done:
-		// All handlers completed
-	case <-time.After(5 * time.Second):
-		t.Fatal("Handlers did not complete within the expected time")
+	client := &Client{
+		connManager:   mockConnManager,
+		subManager:    newSubscriptionManager(1),
+		Config:        createTestConfig(),
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		subscriptions: make(map[string]context.CancelFunc),
 	}
-}
 
-type testMocks struct {
-	connManager   *MockConnectionManagerInterface
-	jetStream     *MockJetStream
-	consumer1     *MockConsumer
-	messageBatch1 *MockMessageBatch
-	messageChan1  chan jetstream.Msg
-	msg1          *MockMsg
-	consumer2     *MockConsumer
-	messageBatch2 *MockMessageBatch
-	messageChan2  chan jetstream.Msg
-	msg2          *MockMsg
-	subManager    *MockSubscriptionManagerInterface
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := func(*pubsub.Message) error { return nil }
+
+	err := client.SubscribeAll(ctx, nil, handler)
+	require.ErrorIs(t, err, errNoSubjects)
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(2)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(nil, errConsumerCreationError)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+
+	// the subjects subscribed before the failing one are unsubscribed again
+	err = client.SubscribeAll(ctx, []string{"orders.created", "orders.shipped"}, handler)
+	require.Error(t, err)
+
+	client.subMutex.Lock()
+	assert.Empty(t, client.subscriptions)
+	client.subMutex.Unlock()
+
+	// subjects listed twice are rejected before anything is subscribed
+	err = client.SubscribeAll(ctx, []string{"orders.created", "orders.shipped", "orders.created"}, handler)
+	require.ErrorIs(t, err, errDuplicateSubject)
 }
 
-func TestClient_CreateStream(t *testing.T) {
+func TestClient_SubscribeAll_KeepsExistingSubscriptions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
 	client := &Client{
-		streamManager: mockStreamManager,
+		connManager:   mockConnManager,
+		subManager:    newSubscriptionManager(1),
+		Config:        createTestConfig(),
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		subscriptions: make(map[string]context.CancelFunc),
 	}
 
-	cfg := StreamConfig{
-		Stream:   "test-stream",
-		Subjects: []string{"test-subject"},
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	mockStreamManager.EXPECT().CreateStream(gomock.Any(), cfg).Return(nil)
+	handler := func(*pubsub.Message) error { return nil }
 
-	err := client.CreateStream(context.Background(), cfg)
-	require.NoError(t, err)
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(4)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil).Times(3)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(nil, errConsumerCreationError)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+
+	require.NoError(t, client.SubscribeWithMessageHandler(ctx, "orders.created", handler))
+
+	// only orders.shipped is rolled back, orders.created was subscribed before the call
+	err := client.SubscribeAll(ctx, []string{"orders.created", "orders.shipped", "orders.cancelled"}, handler)
+	require.ErrorIs(t, err, errConsumerCreationError)
+
+	client.subMutex.Lock()
+	assert.Contains(t, client.subscriptions, "orders.created")
+	assert.NotContains(t, client.subscriptions, "orders.shipped")
+	client.subMutex.Unlock()
 }
 
-func TestClient_DeleteStream(t *testing.T) {
+func TestClient_Shutdown(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMsg := NewMockMsg(ctrl)
+
 	client := &Client{
-		streamManager: mockStreamManager,
+		connManager:   mockConnManager,
+		subManager:    newSubscriptionManager(1),
+		Config:        createTestConfig(),
+		logger:        logging.NewMockLogger(logging.DEBUG),
+		subscriptions: make(map[string]context.CancelFunc),
 	}
 
-	mockStreamManager.EXPECT().DeleteStream(gomock.Any(), "test-stream").Return(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	err := client.DeleteStream(context.Background(), "test-stream")
+	msgChan := make(chan jetstream.Msg, 1)
+	msgChan <- mockMsg
+	close(msgChan)
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil)
+	// a single fetch is expected: none may start once the shutdown has paused the subscriptions
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil).Times(1)
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+	mockMsg.EXPECT().Ack().Return(nil)
+
+	handling, release := make(chan struct{}), make(chan struct{})
+
+	err := client.SubscribeWithHandler(ctx, "test-subject", func(context.Context, jetstream.Msg) error {
+		close(handling)
+		<-release
+
+		return nil
+	})
 	require.NoError(t, err)
+
+	<-handling
+
+	drained := make(chan struct{})
+
+	mockConnManager.EXPECT().Drain(gomock.Any()).DoAndReturn(func(context.Context) error {
+		close(drained)
+
+		return nil
+	})
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- client.Shutdown(context.Background())
+	}()
+
+	// the connection is not drained while a handler is in flight
+	select {
+	case <-drained:
+		t.Fatal("connection drained before the in-flight handler returned")
+	case <-time.After(2 * pausedPollInterval):
+	}
+
+	close(release)
+
+	select {
+	case shutdownErr := <-done:
+		require.NoError(t, shutdownErr)
+	case <-time.After(time.Second):
+		t.Fatal("shutdown did not complete")
+	}
+
+	client.subMutex.Lock()
+	assert.Empty(t, client.subscriptions)
+	client.subMutex.Unlock()
 }
 
-func TestClient_CreateOrUpdateStream(t *testing.T) {
+func TestClient_Shutdown_Deadline(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockStreamManager := NewMockStreamManagerInterface(ctrl)
-	mockStream := NewMockStream(ctrl)
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
 	client := &Client{
-		streamManager: mockStreamManager,
+		connManager: mockConnManager,
+		subManager:  newSubscriptionManager(1),
+		Config:      createTestConfig(),
+		logger:      logging.NewMockLogger(logging.DEBUG),
 	}
 
-	cfg := jetstream.StreamConfig{
-		Name:     "test-stream",
-		Subjects: []string{"test-subject"},
-	}
+	// a fetch that never completes holds the shutdown until its deadline
+	require.NoError(t, client.pausedSubjects().acquire(context.Background(), "test-subject"))
 
-	mockStreamManager.EXPECT().CreateOrUpdateStream(gomock.Any(), &cfg).Return(mockStream, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
 
-	stream, err := client.CreateOrUpdateStream(context.Background(), &cfg)
-	require.NoError(t, err)
-	assert.Equal(t, mockStream, stream)
+	// the connection is drained anyway
+	mockConnManager.EXPECT().Drain(ctx).Return(nil)
+
+	require.ErrorIs(t, client.Shutdown(ctx), context.DeadlineExceeded)
 }
@@ -0,0 +1,53 @@
+package nats
+
+import "time"
+
+// clock is the source of time of the client's timeouts, backoffs and durations, so that tests can
+// replace it with a clock they advance themselves.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) timer
+}
+
+// timer is a single timer created by a clock.
+type timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock is the clock of the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// clockOrReal returns c, or the real clock when c is nil.
+func clockOrReal(c clock) clock {
+	if c == nil {
+		return realClock{}
+	}
+
+	return c
+}
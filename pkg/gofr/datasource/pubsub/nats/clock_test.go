@@ -0,0 +1,154 @@
+package nats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+// fakeClock is a clock that only moves when advanced, firing the timers that become due.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	ch    chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+// Advance moves the clock forward by d and fires the timers that are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+
+			continue
+		}
+
+		t.ch <- c.now
+	}
+
+	c.timers = pending
+}
+
+// pending returns the number of timers that have not fired yet.
+func (c *fakeClock) pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestClockOrReal(t *testing.T) {
+	assert.Equal(t, realClock{}, clockOrReal(nil))
+
+	clk := newFakeClock()
+	assert.Equal(t, clk, clockOrReal(clk))
+}
+
+func TestSubscriptionManager_fetchAndProcessMessages_EmptyFetchBackoffFakeClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	emptyBatch := NewMockMessageBatch(ctrl)
+	emptyMessages := make(chan jetstream.Msg)
+	close(emptyMessages)
+
+	emptyBatch.EXPECT().Messages().Return(emptyMessages)
+	emptyBatch.EXPECT().Error().Return(nil)
+
+	mockConsumer := NewMockConsumer(ctrl)
+	mockConsumer.EXPECT().Fetch(1, gomock.Any()).Return(emptyBatch, nil)
+
+	clk := newFakeClock()
+
+	sm := newSubscriptionManager(1)
+	sm.clock = clk
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- sm.fetchAndProcessMessages(context.Background(), mockConsumer, "test.topic", make(chan *pubsub.Message, 1),
+			&Config{EmptyFetchBackoff: time.Hour}, logging.NewMockLogger(logging.DEBUG), NewMockMetrics(ctrl), nil)
+	}()
+
+	// the empty fetch waits for the backoff, which only elapses once the clock is advanced
+	require.Eventually(t, func() bool { return clk.pending() == 1 }, time.Second, time.Millisecond)
+
+	select {
+	case <-done:
+		t.Fatal("returned before the backoff elapsed")
+	default:
+	}
+
+	clk.Advance(time.Hour)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("did not return after the backoff elapsed")
+	}
+}
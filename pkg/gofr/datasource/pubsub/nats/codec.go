@@ -0,0 +1,97 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+const contentTypeHeader = "Content-Type"
+
+// Codec encodes the values published with PublishObject and decodes the messages received with
+// SubscribeObject. ContentType is the value of the Content-Type header of the published messages;
+// an empty content type leaves the header unset.
+type Codec interface {
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+	ContentType() string
+}
+
+// JSONCodec encodes values as JSON. It is used when Config.Codec is not set.
+type JSONCodec struct{}
+
+// Encode returns the JSON encoding of v.
+func (JSONCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode parses the JSON-encoded data into v.
+func (JSONCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// ContentType returns "application/json".
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+func (c *Client) codec() Codec {
+	if c.Config != nil && c.Config.Codec != nil {
+		return c.Config.Codec
+	}
+
+	return JSONCodec{}
+}
+
+// PublishObject encodes v with the configured codec and publishes it to the subject, along with a
+// Content-Type header naming the content type of the codec.
+func (c *Client) PublishObject(ctx context.Context, subject string, v any) error {
+	codec := c.codec()
+
+	message, err := codec.Encode(v)
+	if err != nil {
+		return fmt.Errorf("%w for subject %s: %w", errEncodeFailed, subject, err)
+	}
+
+	if contentType := codec.ContentType(); contentType != "" {
+		return c.PublishWithHeaders(ctx, subject, message, nats.Header{contentTypeHeader: []string{contentType}})
+	}
+
+	return c.Publish(ctx, subject, message)
+}
+
+// SubscribeObject receives a message from the subject and decodes it into v with the configured
+// codec. Messages with a Content-Type header other than that of the codec are not decoded, while
+// messages without one are. The message is returned even when it cannot be decoded, so that it can
+// be committed or redelivered.
+func (c *Client) SubscribeObject(ctx context.Context, subject string, v any) (*pubsub.Message, error) {
+	msg, err := c.Subscribe(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	codec := c.codec()
+
+	if contentType := messageContentType(msg); contentType != "" && contentType != codec.ContentType() {
+		return msg, fmt.Errorf("%w from subject %s: %w %q", errDecodeFailed, subject, errUnexpectedContentType, contentType)
+	}
+
+	if err := codec.Decode(msg.Value, v); err != nil {
+		return msg, fmt.Errorf("%w from subject %s: %w", errDecodeFailed, subject, err)
+	}
+
+	return msg, nil
+}
+
+// messageContentType returns the Content-Type header of a message received from NATS, if any.
+func messageContentType(msg *pubsub.Message) string {
+	headers, ok := msg.MetaData.(nats.Header)
+	if !ok {
+		return ""
+	}
+
+	return headers.Get(contentTypeHeader)
+}
@@ -0,0 +1,115 @@
+package nats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+type testOrder struct {
+	ID     int      `json:"id"`
+	Status string   `json:"status"`
+	Items  []string `json:"items"`
+}
+
+func TestNATSClient_PublishSubscribeObject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      &Config{Stream: StreamConfig{Stream: "orders", Subjects: []string{"orders.*"}}, Consumer: "billing"},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	order := testOrder{ID: 1, Status: "created", Items: []string{"book", "pen"}}
+
+	var (
+		published []byte
+		headers   nats.Header
+	)
+
+	mockConnManager.EXPECT().PublishWithHeaders(ctx, "orders.created", gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, _ string, message []byte, h nats.Header, _ Metrics) error {
+			published, headers = message, h
+
+			return nil
+		})
+
+	require.NoError(t, client.PublishObject(ctx, "orders.created", order))
+	assert.JSONEq(t, `{"id":1,"status":"created","items":["book","pen"]}`, string(published))
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+
+	msg := pubsub.NewMessage(ctx)
+	msg.Value = published
+	msg.MetaData = headers
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockSubManager.EXPECT().Subscribe(ctx, "orders.created", mockJS, client.Config, client.logger, nil).Return(msg, nil)
+
+	var received testOrder
+
+	got, err := client.SubscribeObject(ctx, "orders.created", &received)
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+	assert.Equal(t, order, received)
+}
+
+func TestNATSClient_PublishSubscribeObject_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      &Config{Stream: StreamConfig{Stream: "orders", Subjects: []string{"orders.*"}}, Consumer: "billing"},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	err := client.PublishObject(ctx, "orders.created", make(chan int))
+	require.ErrorIs(t, err, errEncodeFailed)
+
+	msg := pubsub.NewMessage(ctx)
+	msg.Value = []byte("not json")
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockSubManager.EXPECT().Subscribe(ctx, "orders.created", mockJS, gomock.Any(), gomock.Any(), gomock.Any()).Return(msg, nil)
+
+	var received testOrder
+
+	// the message is returned so that it can still be committed
+	got, err := client.SubscribeObject(ctx, "orders.created", &received)
+	require.ErrorIs(t, err, errDecodeFailed)
+	assert.Equal(t, msg, got)
+
+	msg = pubsub.NewMessage(ctx)
+	msg.Value = []byte(`{"id":1}`)
+	msg.MetaData = nats.Header{"Content-Type": []string{"application/protobuf"}}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockSubManager.EXPECT().Subscribe(ctx, "orders.created", mockJS, gomock.Any(), gomock.Any(), gomock.Any()).Return(msg, nil)
+
+	// messages of another content type are not decoded
+	got, err = client.SubscribeObject(ctx, "orders.created", &received)
+	require.ErrorIs(t, err, errUnexpectedContentType)
+	assert.Equal(t, msg, got)
+	assert.Zero(t, received.ID)
+}
@@ -1,19 +1,66 @@
 package nats
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
 )
 
+// Metadata is the JetStream delivery information of a message, which handlers can use for
+// idempotency checks or to detect messages that keep failing.
+type Metadata struct {
+	Stream       string
+	Consumer     string
+	StreamSeq    uint64
+	ConsumerSeq  uint64
+	NumDelivered uint64
+	NumPending   uint64
+	Timestamp    time.Time
+}
+
+// MessageMetadata returns the JetStream metadata of a message received by the Client.
+func MessageMetadata(msg *pubsub.Message) (*Metadata, error) {
+	committer, ok := msg.Committer.(*natsCommitter)
+	if !ok {
+		return nil, errNotNATSMessage
+	}
+
+	return committer.Metadata()
+}
+
 // natsCommitter implements the pubsub.Committer interface for Client messages.
+// Ack, Nak and Term can be used to control acknowledgement directly, which is
+// required when Config.ManualAck is set.
 type natsCommitter struct {
-	msg jetstream.Msg
+	msg       jetstream.Msg
+	manualAck bool
+	// doubleAck waits for the server to confirm acknowledgements, see Config.DoubleAck.
+	doubleAck bool
+
+	mu    sync.Mutex
+	acked bool
 }
 
-// Commit commits the message.
+// Commit commits the message. It is a no-op when manual acknowledgement is enabled or the message
+// has already been acknowledged.
 func (c *natsCommitter) Commit() {
-	if err := c.msg.Ack(); err != nil {
+	if c.manualAck {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.acked {
+		return
+	}
+
+	if err := ackMessage(context.Background(), c.msg, c.doubleAck); err != nil {
 		log.Println("Error committing message:", err)
 
 		// nak the message
@@ -23,6 +70,47 @@ func (c *natsCommitter) Commit() {
 
 		return
 	}
+
+	c.acked = true
+}
+
+// Ack acknowledges the message, waiting for the server to confirm it when Config.DoubleAck is set.
+// Calling Ack on an already acknowledged message is a no-op.
+func (c *natsCommitter) Ack() error {
+	return c.ack(context.Background(), c.doubleAck)
+}
+
+// DoubleAck acknowledges the message and waits until the server confirms it or ctx is done, whether
+// or not Config.DoubleAck is set.
+func (c *natsCommitter) DoubleAck(ctx context.Context) error {
+	return c.ack(ctx, true)
+}
+
+func (c *natsCommitter) ack(ctx context.Context, doubleAck bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.acked {
+		return nil
+	}
+
+	if err := ackMessage(ctx, c.msg, doubleAck); err != nil {
+		return err
+	}
+
+	c.acked = true
+
+	return nil
+}
+
+// ackMessage acknowledges msg. With doubleAck, it waits for the server to confirm the acknowledgement
+// so that a message is not redelivered after the caller moved on.
+func ackMessage(ctx context.Context, msg jetstream.Msg, doubleAck bool) error {
+	if doubleAck {
+		return msg.DoubleAck(ctx)
+	}
+
+	return msg.Ack()
 }
 
 // Nak naks the message.
@@ -30,7 +118,30 @@ func (c *natsCommitter) Nak() error {
 	return c.msg.Nak()
 }
 
+// Term terminates the message so that it is never redelivered.
+func (c *natsCommitter) Term() error {
+	return c.msg.Term()
+}
+
 // Rollback rolls back the message.
 func (c *natsCommitter) Rollback() error {
 	return c.msg.Nak()
 }
+
+// Metadata returns the stream and consumer sequences, delivery count and timestamp of the message.
+func (c *natsCommitter) Metadata() (*Metadata, error) {
+	meta, err := c.msg.Metadata()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message metadata: %w", err)
+	}
+
+	return &Metadata{
+		Stream:       meta.Stream,
+		Consumer:     meta.Consumer,
+		StreamSeq:    meta.Sequence.Stream,
+		ConsumerSeq:  meta.Sequence.Consumer,
+		NumDelivered: meta.NumDelivered,
+		NumPending:   meta.NumPending,
+		Timestamp:    meta.Timestamp,
+	}, nil
+}
@@ -1,11 +1,15 @@
 package nats
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
 )
 
 // createTestCommitter is a helper function for tests to create a natsCommitter.
@@ -18,25 +22,35 @@ func TestNATSCommitter_Commit(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockMsg := NewMockMsg(ctrl)
-	committer := createTestCommitter(mockMsg)
 
 	t.Run("Successful Commit", func(_ *testing.T) {
 		mockMsg.EXPECT().Ack().Return(nil)
 
-		committer.Commit()
+		createTestCommitter(mockMsg).Commit()
 	})
 
 	t.Run("Failed Commit with Successful Nak", func(_ *testing.T) {
 		mockMsg.EXPECT().Ack().Return(assert.AnError)
 		mockMsg.EXPECT().Nak().Return(nil)
 
-		committer.Commit()
+		createTestCommitter(mockMsg).Commit()
 	})
 
 	t.Run("Failed Commit with Failed Nak", func(_ *testing.T) {
 		mockMsg.EXPECT().Ack().Return(assert.AnError)
 		mockMsg.EXPECT().Nak().Return(assert.AnError)
 
+		createTestCommitter(mockMsg).Commit()
+	})
+
+	t.Run("Commit after Ack", func(t *testing.T) {
+		mockMsg.EXPECT().Ack().Return(nil).Times(1)
+
+		committer := createTestCommitter(mockMsg)
+		require.NoError(t, committer.Ack())
+
+		// the message is not acknowledged a second time
+		committer.Commit()
 		committer.Commit()
 	})
 }
@@ -84,3 +98,140 @@ func TestNATSCommitter_Rollback(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestNATSCommitter_Ack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsg := NewMockMsg(ctrl)
+	committer := createTestCommitter(mockMsg)
+
+	mockMsg.EXPECT().Ack().Return(assert.AnError)
+
+	err := committer.Ack()
+	require.Error(t, err)
+
+	mockMsg.EXPECT().Ack().Return(nil).Times(1)
+
+	err = committer.Ack()
+	require.NoError(t, err)
+
+	// Acknowledging an already acknowledged message is a no-op.
+	err = committer.Ack()
+	assert.NoError(t, err)
+}
+
+func TestNATSCommitter_DoubleAck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	// with Config.DoubleAck, Ack and Commit wait for the server to confirm the acknowledgement
+	acked := NewMockMsg(ctrl)
+	acked.EXPECT().Ack().Times(0)
+	acked.EXPECT().DoubleAck(gomock.Any()).Return(nil)
+
+	require.NoError(t, (&natsCommitter{msg: acked, doubleAck: true}).Ack())
+
+	committed := NewMockMsg(ctrl)
+	committed.EXPECT().DoubleAck(gomock.Any()).Return(nil)
+
+	committer := &natsCommitter{msg: committed, doubleAck: true}
+	committer.Commit()
+	assert.True(t, committer.acked)
+
+	// DoubleAck confirms the acknowledgement regardless of the configuration
+	explicit := NewMockMsg(ctrl)
+	explicit.EXPECT().DoubleAck(ctx).Return(assert.AnError)
+	explicit.EXPECT().DoubleAck(ctx).Return(nil)
+
+	committer = createTestCommitter(explicit)
+	require.ErrorIs(t, committer.DoubleAck(ctx), assert.AnError)
+	require.NoError(t, committer.DoubleAck(ctx))
+	require.NoError(t, committer.DoubleAck(ctx))
+}
+
+func TestNATSCommitter_Term(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsg := NewMockMsg(ctrl)
+	committer := createTestCommitter(mockMsg)
+
+	mockMsg.EXPECT().Term().Return(nil)
+
+	err := committer.Term()
+	assert.NoError(t, err)
+}
+
+func TestNATSCommitter_ManualAck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsg := NewMockMsg(ctrl)
+	committer := &natsCommitter{msg: mockMsg, manualAck: true}
+
+	// Commit must not acknowledge in manual mode; the Nak below leaves the
+	// message unacknowledged so the server redelivers it.
+	mockMsg.EXPECT().Ack().Times(0)
+	mockMsg.EXPECT().Nak().Return(nil)
+
+	committer.Commit()
+
+	err := committer.Nak()
+	require.NoError(t, err)
+	assert.False(t, committer.acked)
+}
+
+func TestMessageMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timestamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mockMsg := NewMockMsg(ctrl)
+	mockMsg.EXPECT().Headers().Return(nil)
+	mockMsg.EXPECT().Data().Return([]byte("order"))
+	mockMsg.EXPECT().Subject().Return("orders.created")
+	mockMsg.EXPECT().Metadata().Return(&jetstream.MsgMetadata{
+		Sequence:     jetstream.SequencePair{Stream: 42, Consumer: 7},
+		NumDelivered: 3,
+		NumPending:   10,
+		Stream:       "orders",
+		Consumer:     "orders-consumer",
+		Timestamp:    timestamp,
+	}, nil)
+
+	msg, err := newPubSubMessage(mockMsg, &Config{})
+	require.NoError(t, err)
+
+	meta, err := MessageMetadata(msg)
+	require.NoError(t, err)
+	assert.Equal(t, &Metadata{
+		Stream:       "orders",
+		Consumer:     "orders-consumer",
+		StreamSeq:    42,
+		ConsumerSeq:  7,
+		NumDelivered: 3,
+		NumPending:   10,
+		Timestamp:    timestamp,
+	}, meta)
+}
+
+func TestMessageMetadata_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := MessageMetadata(pubsub.NewMessage(context.Background()))
+	require.ErrorIs(t, err, errNotNATSMessage)
+
+	mockMsg := NewMockMsg(ctrl)
+	mockMsg.EXPECT().Metadata().Return(nil, jetstream.ErrNotJSMessage)
+
+	msg := pubsub.NewMessage(context.Background())
+	msg.Committer = createTestCommitter(mockMsg)
+
+	_, err = MessageMetadata(msg)
+	require.ErrorIs(t, err, jetstream.ErrNotJSMessage)
+}
@@ -0,0 +1,78 @@
+package nats
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	contentEncodingHeader = "Content-Encoding"
+	gzipEncoding          = "gzip"
+
+	defaultDecompressMaxBytes = 64 << 20
+)
+
+// compressPayload gzips message when compression is enabled and the message is at least
+// Config.CompressMinBytes long, returning the headers marking the encoding. Messages that already
+// carry a content encoding are published as they are.
+func compressPayload(conf *Config, message []byte, headers nats.Header) ([]byte, nats.Header, error) {
+	if !conf.Compress || len(message) < conf.CompressMinBytes || headers.Get(contentEncodingHeader) != "" {
+		return message, headers, nil
+	}
+
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	if _, err := writer.Write(message); err != nil {
+		return nil, nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	if headers == nil {
+		headers = nats.Header{}
+	}
+
+	headers.Set(contentEncodingHeader, gzipEncoding)
+
+	return buf.Bytes(), headers, nil
+}
+
+// decompressPayload returns the uncompressed message when headers mark it as gzip encoded. It fails
+// with errDecompressedTooLarge rather than reading more than Config.DecompressMaxBytes.
+func decompressPayload(conf *Config, message []byte, headers nats.Header) ([]byte, error) {
+	if headers.Get(contentEncodingHeader) != gzipEncoding {
+		return message, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(message))
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	limit := conf.DecompressMaxBytes
+	if limit <= 0 {
+		limit = defaultDecompressMaxBytes
+	}
+
+	// reading one byte past the limit tells a payload of exactly limit bytes from a larger one
+	value, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(value)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", errDecompressedTooLarge, limit)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,145 @@
+package nats
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestCompressPayload(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"id":1,"status":"created"}`, 100))
+
+	testCases := []struct {
+		desc       string
+		config     *Config
+		headers    nats.Header
+		compressed bool
+	}{
+		{desc: "compression disabled", config: &Config{}},
+		{desc: "compressed", config: &Config{Compress: true, CompressMinBytes: 1024}, compressed: true},
+		{desc: "below threshold", config: &Config{Compress: true, CompressMinBytes: len(payload) + 1}},
+		{desc: "already encoded", config: &Config{Compress: true}, headers: nats.Header{"Content-Encoding": []string{"br"}}},
+	}
+
+	for i, tc := range testCases {
+		message, headers, err := compressPayload(tc.config, payload, tc.headers)
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		if !tc.compressed {
+			assert.Equal(t, payload, message, "TEST[%d], Failed.\n%s", i, tc.desc)
+			assert.Equal(t, tc.headers, headers, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+			continue
+		}
+
+		assert.Equal(t, "gzip", headers.Get("Content-Encoding"), "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.Less(t, len(message), len(payload), "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		decompressed, err := decompressPayload(tc.config, message, headers)
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.Equal(t, payload, decompressed, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestDecompressPayload_Invalid(t *testing.T) {
+	_, err := decompressPayload(&Config{}, []byte("not gzip"), nats.Header{"Content-Encoding": []string{"gzip"}})
+	require.Error(t, err)
+
+	message, err := decompressPayload(&Config{}, []byte("plain"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plain"), message)
+}
+
+func TestDecompressPayload_TooLarge(t *testing.T) {
+	payload := []byte(strings.Repeat("a", 1024))
+
+	message, headers, err := compressPayload(&Config{Compress: true}, payload, nil)
+	require.NoError(t, err)
+
+	_, err = decompressPayload(&Config{DecompressMaxBytes: int64(len(payload)) - 1}, message, headers)
+	require.ErrorIs(t, err, errDecompressedTooLarge)
+
+	decompressed, err := decompressPayload(&Config{DecompressMaxBytes: int64(len(payload))}, message, headers)
+	require.NoError(t, err)
+	assert.Equal(t, payload, decompressed)
+}
+
+func TestNATSClient_Publish_Compressed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{Compress: true, CompressMinBytes: 16},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	payload := []byte(strings.Repeat("a", 512))
+
+	mockConnManager.EXPECT().PublishWithHeaders(ctx, "orders.created", gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, _ string, message []byte, headers nats.Header, _ Metrics) error {
+			assert.Equal(t, "gzip", headers.Get("Content-Encoding"))
+
+			decompressed, err := decompressPayload(client.Config, message, headers)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decompressed)
+
+			return nil
+		})
+	// payloads below the threshold are published as they are
+	mockConnManager.EXPECT().Publish(ctx, "orders.created", []byte("small"), nil).Return(nil)
+
+	require.NoError(t, client.Publish(ctx, "orders.created", payload))
+	require.NoError(t, client.Publish(ctx, "orders.created", []byte("small")))
+}
+
+func TestSubscriptionManager_processFetchedMessages_Compressed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := []byte(strings.Repeat(`{"id":1}`, 64))
+
+	compressed, headers, err := compressPayload(&Config{Compress: true}, payload, nil)
+	require.NoError(t, err)
+
+	mockBatch := NewMockMessageBatch(ctrl)
+	validMsg := NewMockMsg(ctrl)
+	corruptMsg := NewMockMsg(ctrl)
+
+	msgChan := make(chan jetstream.Msg, 2)
+	msgChan <- validMsg
+	msgChan <- corruptMsg
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+
+	validMsg.EXPECT().Headers().Return(headers)
+	validMsg.EXPECT().Data().Return(compressed)
+	validMsg.EXPECT().Subject().Return("orders.created")
+
+	// a payload that cannot be decompressed is terminated instead of being delivered
+	corruptMsg.EXPECT().Headers().Return(headers)
+	corruptMsg.EXPECT().Data().Return([]byte("corrupt"))
+	corruptMsg.EXPECT().Subject().Return("orders.created")
+	corruptMsg.EXPECT().Term().Return(nil)
+
+	buffer := make(chan *pubsub.Message, 2)
+
+	_, err = newSubscriptionManager(2).processFetchedMessages(context.Background(), mockBatch, "orders.created", buffer,
+		&Config{}, logging.NewMockLogger(logging.DEBUG), nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, buffer, 1)
+	assert.Equal(t, payload, (<-buffer).Value)
+}
@@ -1,21 +1,293 @@
 package nats
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"gofr.dev/pkg/gofr/datasource/pubsub"
 )
 
-const batchSize = 100
+const (
+	batchSize                  = 100
+	defaultAckWait             = 30 * time.Second
+	defaultPublishRetryBackoff = 100 * time.Millisecond
+	defaultEmptyFetchBackoff   = 50 * time.Millisecond
+)
 
 // Config defines the Client configuration.
 type Config struct {
-	Server      string
-	CredsFile   string
+	// Server is the URL of the server, or a comma separated list of URLs for a cluster. Every URL
+	// needs the nats:// or tls:// scheme.
+	Server string
+	// Servers lists the URLs of the servers to fail over between, e.g. the members of a cluster or
+	// leafnodes. It takes precedence over Server.
+	Servers []string
+	// RandomizeServers lets the client pick the servers in random order, spreading the connections of
+	// many clients across them. Otherwise they are tried in the order given.
+	RandomizeServers bool
+
 	Stream      StreamConfig
+	Streams     []StreamConfig // additional streams; Stream, when set, is treated as the first entry
 	Consumer    string
 	MaxWait     time.Duration
 	MaxPullWait int
+	// BatchSize is the number of messages pulled from the server per fetch. Defaults to 1.
+	BatchSize int
+	// EmptyFetchBackoff is how long Subscribe waits before fetching again after a fetch returned no
+	// messages. Defaults to 50 milliseconds.
+	EmptyFetchBackoff time.Duration
+	// MaxBufferedMessages caps the number of fetched messages a subscription holds until Subscribe
+	// returns them. Once reached, fetching pauses until the application consumes a message, and the
+	// depth of the buffer is reported as the app_pubsub_buffer_depth gauge.
+	MaxBufferedMessages int
+	// Concurrency is the number of messages SubscribeWithHandler handles at once. Defaults to 1.
+	Concurrency int
+	// ManualAck disables acknowledgement on Commit, leaving the application to call
+	// Ack, Nak or Term on the message's Committer.
+	ManualAck bool
+	// DoubleAck waits for the server to confirm every acknowledgement before the handler or Commit
+	// returns, for workflows that cannot tolerate a lost acknowledgement leading to a redelivery.
+	DoubleAck bool
+	// AckWait is how long the server waits for an acknowledgement before redelivering a message.
+	// Defaults to 30 seconds.
+	AckWait time.Duration
+	// MaxDeliver caps the number of delivery attempts of a message; -1 means unlimited.
+	// When zero, Stream.MaxDeliver is used.
+	MaxDeliver int
+	// BackoffSchedule delays the redeliveries of a message that was not acknowledged, e.g. 1s, 5s,
+	// 30s; the last delay applies to all further redeliveries. It replaces AckWait for messages
+	// awaiting redelivery, and a limited MaxDeliver must be greater than its length.
+	BackoffSchedule []time.Duration
+	// SlowHandlerThreshold, when set, logs a warning and increments app_pubsub_slow_handler_count for
+	// every SubscribeWithHandler handler call that takes longer.
+	SlowHandlerThreshold time.Duration
+	// StreamInfoCacheTTL, when set, is how long the stream capturing a subject is remembered after it
+	// was looked up on the server. Setting it also labels the metrics of publishes to subjects outside
+	// the configured streams with the stream found on the server. The cache is cleared whenever the
+	// client creates or deletes a stream.
+	StreamInfoCacheTTL time.Duration
+	// FilterSubject narrows the messages a consumer receives to the given subject, which must be
+	// covered by the subjects of a configured stream. When empty, the subscribed subject is used.
+	FilterSubject string
+	// DLQSubject, when set together with a positive MaxDeliver, is the subject messages are
+	// republished to once they reach the maximum number of deliveries.
+	DLQSubject string
+	// PoisonAttemptThreshold, when positive, logs an error with the stream sequence of a message and
+	// increments app_pubsub_poison_suspect_count once the message is delivered that many times.
+	PoisonAttemptThreshold int
+	// Ephemeral creates consumers without a durable name so that the server removes
+	// them once the client goes away. Consumer is required unless Ephemeral is set.
+	Ephemeral bool
+	// OrderedConsumer delivers the messages of the stream in strict sequence order, starting from
+	// the first message. Ordered consumers are ephemeral and are recreated from the last received
+	// sequence whenever a gap is detected, so they need no acknowledgement; Commit is a no-op.
+	// It cannot be combined with Consumer or QueueGroup.
+	OrderedConsumer bool
+	// FlowControl enables flow control for slow consumers. Consumers of this client pull messages,
+	// so the server never sends more than a fetch requested and no further flow control messages
+	// are needed; FlowControl enables IdleHeartbeat on the fetch requests.
+	FlowControl bool
+	// IdleHeartbeat is the interval at which the server sends heartbeats while a fetch waits for
+	// messages, so that a stalled connection is reported instead of waiting out MaxWait. It must be
+	// less than half of MaxWait and requires FlowControl or OrderedConsumer.
+	IdleHeartbeat time.Duration
+	// LagReportInterval, when positive, is how often subscriptions log the number of messages
+	// pending for their consumer and report it as the app_pubsub_consumer_pending gauge.
+	LagReportInterval time.Duration
+	// OnSubscribed, when set, is called with the subject and consumer name once the consumer of a
+	// subscription is created, for instance to report readiness. A panic in it is recovered and logged.
+	OnSubscribed func(subject, consumer string)
+	// StartFrom sets the position in the stream new consumers start delivering from.
+	StartFrom StartFrom
+	// QueueGroup load-balances messages across all clients in the group. Members of a group
+	// share a single durable consumer named after the group, so only one of them receives
+	// each message. It cannot be combined with Ephemeral, and makes Consumer optional.
+	// All consumers of the client are pull consumers, so QueueGroup takes the place of the
+	// deliver group of push consumers.
+	QueueGroup string
+	// AttachOnly attaches to the existing durable consumer named Consumer, e.g. one provisioned by
+	// infrastructure tooling, instead of creating or updating a consumer per subject. Subscribing fails
+	// when the consumer does not exist. It cannot be combined with Ephemeral or OrderedConsumer.
+	AttachOnly bool
+	// RecreateConsumerOnDelete creates the consumer of a subscription again when it is deleted on the
+	// server while subscribed, e.g. by an operator, and resumes fetching from it. Otherwise the
+	// subscription stops and Subscribe returns errConsumerDeleted.
+	RecreateConsumerOnDelete bool
+
+	// JetStreamDomain is the JetStream domain jStream requests are sent to, e.g. to reach the
+	// JetStream of a hub from a leaf node.
+	JetStreamDomain string
+	// APIPrefix is a custom prefix for the jStream API subjects, for accounts that import the API of
+	// another account. It cannot be combined with JetStreamDomain.
+	APIPrefix string
+	// SubjectPrefix namespaces every subject of the client, e.g. "prod." in a cluster shared between
+	// environments. It is prepended to the subjects published, requested, responded to and subscribed
+	// to, to DLQSubject, to the subjects of configured streams and of streams created by the client, and
+	// stripped from Message.Topic, so that application code stays unaware of it. It cannot contain
+	// wildcards or whitespace.
+	SubjectPrefix string
+
+	// ConnectionName identifies the connection on the server, e.g. in the /connz monitoring output.
+	// Defaults to the APP_NAME environment variable.
+	ConnectionName string
+
+	// Authentication; at most one method can be configured.
+	CredsFile string
+	Token     string
+	Username  string
+	Password  string
+	// NKeyFile is the path to an NKey user seed.
+	NKeyFile string
+
+	// TLS configures a secure connection to the server.
+	TLS *TLSConfig
+
+	// Options are passed to the NATS connection after the options derived from the other fields, so
+	// they override those where both set the same setting, e.g. a custom dialer or error handler.
+	Options []nats.Option
+
+	// MaxReconnects and ReconnectWait are passed to the NATS connection when set.
+	MaxReconnects int
+	ReconnectWait time.Duration
+	// RetryOnInitialConnect retries the first connection with exponential backoff
+	// instead of failing immediately when the server is unreachable.
+	RetryOnInitialConnect bool
+
+	// DrainTimeout bounds how long Close waits for the connection to drain. When zero,
+	// Close closes the connection without draining.
+	DrainTimeout time.Duration
+	// DeleteStreamOnClose deletes all configured streams on Close. Keep it disabled for shared streams.
+	DeleteStreamOnClose bool
+	// AutoCreateStream set to false makes CreateStream and CreateTopic only verify that the stream
+	// exists, for credentials that are not allowed to create streams. Defaults to true.
+	AutoCreateStream *bool
+	// UpdateStreamIfExists makes CreateStream update a stream that already exists with a different
+	// configuration instead of failing, e.g. to change its retention or limits.
+	UpdateStreamIfExists bool
+	// MetricStreamLabel set to false leaves the stream label out of all metrics, for deployments with
+	// many dynamically named streams. Defaults to true.
+	MetricStreamLabel *bool
+	// MetricStreamAllowlist, when set, limits the stream label to the named streams. Metrics of other
+	// streams are recorded without it.
+	MetricStreamAllowlist []string
+	// DryRun validates and logs the streams CreateStream and DeleteStream would create or delete
+	// without changing them on the server, e.g. to check stream configurations in CI.
+	DryRun bool
+
+	// Codec encodes and decodes the values of PublishObject and SubscribeObject. Defaults to JSONCodec.
+	Codec Codec
+
+	// PublishRetries is the number of times Publish retries a message after a transient failure,
+	// such as a timeout or no server responding. Other failures are returned immediately.
+	PublishRetries int
+	// PublishRetryBackoff is the wait before the first retry, doubled on every further retry.
+	// Defaults to 100 milliseconds.
+	PublishRetryBackoff time.Duration
+	// DedupCacheSize, when positive, is the number of message IDs PublishWithID remembers. Publishing an
+	// ID that is still remembered is skipped without reaching the server. The oldest IDs are evicted first.
+	DedupCacheSize int
+	// PublishRateLimit, when positive, is the number of messages per second Publish and its variants
+	// publish to a single subject, allowing bursts of up to one second worth of messages.
+	PublishRateLimit float64
+	// RateLimitBlocking makes publishes over PublishRateLimit wait for their turn instead of failing
+	// with errRateLimited.
+	RateLimitBlocking bool
+	// CircuitBreaker, when set, fails publishes fast with errCircuitOpen once a number of publishes in
+	// a row failed, instead of letting each of them wait for the server.
+	CircuitBreaker *CircuitBreakerConfig
+	// FallbackToCoreNATS publishes messages over core NATS, without persistence or acknowledgement, when
+	// the server or account does not have JetStream enabled. Subscribing still requires JetStream.
+	FallbackToCoreNATS bool
+
+	// Compress gzips published payloads and marks them with a "Content-Encoding: gzip" header.
+	// Subscribe decompresses such messages regardless of this setting.
+	Compress bool
+	// CompressMinBytes is the size below which payloads are published uncompressed.
+	CompressMinBytes int
+	// DecompressMaxBytes is the largest size a received gzip payload may decompress to. Larger messages
+	// are terminated instead of being read into memory. Defaults to 64 MiB.
+	DecompressMaxBytes int64
+
+	// LogMessageBody includes message bodies in the DEBUG logs of published and received messages,
+	// which otherwise show only their size. Keep it disabled where messages carry sensitive data.
+	LogMessageBody bool
+	// LogBodyMaxBytes truncates logged message bodies to the given size. Defaults to 256 bytes.
+	LogBodyMaxBytes int
+	// LoggerName is included in every log line of the client, so that they can be filtered in
+	// applications with several pubsub clients. Defaults to "nats".
+	LoggerName string
+
+	// MetricBuckets are the bucket boundaries, in bytes, of the app_pubsub_message_bytes histogram.
+	MetricBuckets []float64
+
+	// firstMessageDeadline is set from SubscribeOptions.FirstMessageDeadline for a single subscription.
+	firstMessageDeadline time.Duration
+}
+
+// CircuitBreakerConfig configures the circuit breaker around Publish and its variants. After
+// FailureThreshold consecutive failures the circuit opens and publishes fail with errCircuitOpen. Once
+// OpenDuration elapsed, the circuit half-opens and lets a single publish through, closing again when
+// it succeeds. The state is reported as the app_pubsub_circuit_state gauge: 0 closed, 1 open, 2 half-open.
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+// StartFrom selects the first message delivered to a new consumer. At most one field can be set;
+// when both are empty, durable consumers receive only new messages and ordered consumers the whole stream.
+type StartFrom struct {
+	// StartSequence is the stream sequence of the first message.
+	StartSequence uint64
+	// StartTime is the time from which messages are delivered.
+	StartTime time.Time
+}
+
+// TLSConfig holds the TLS settings used to connect to NATS.
+type TLSConfig struct {
+	CertFile           string
+	KeyFile            string
+	CAFile             string
+	InsecureSkipVerify bool
+}
+
+// SubscribeOptions overrides the Config for a single subscription. Zero values keep the Config setting.
+type SubscribeOptions struct {
+	// MaxWait is how long a fetch waits for messages.
+	MaxWait time.Duration
+	// BatchSize is the number of messages pulled per fetch.
+	BatchSize int
+	// Durable replaces Config.Consumer and Config.QueueGroup as the name of the durable consumer.
+	Durable string
+	// FirstMessageDeadline, when set, makes the call that creates the subscription fail with
+	// errNoFirstMessage if no message arrives in time, e.g. for readiness probes. Unlike MaxWait it
+	// bounds the wait for the first message rather than a single fetch. The subscription keeps running.
+	FirstMessageDeadline time.Duration
+}
+
+// apply returns a copy of conf with the options set.
+func (o SubscribeOptions) apply(conf *Config) *Config {
+	cfg := *conf
+
+	if o.MaxWait > 0 {
+		cfg.MaxWait = o.MaxWait
+	}
+
+	if o.BatchSize > 0 {
+		cfg.BatchSize = o.BatchSize
+	}
+
+	if o.Durable != "" {
+		cfg.Consumer = o.Durable
+		cfg.QueueGroup = ""
+	}
+
+	cfg.firstMessageDeadline = o.FirstMessageDeadline
+
+	return &cfg
 }
 
 // StreamConfig holds stream settings for NATS jStream.
@@ -25,6 +297,78 @@ type StreamConfig struct {
 	MaxDeliver int
 	MaxWait    time.Duration
 	MaxBytes   int64
+	MaxAge     time.Duration
+	MaxMsgs    int64
+	// Replicas is the number of stream replicas in a cluster. Defaults to 1.
+	Replicas  int
+	Retention jetstream.RetentionPolicy
+	// Storage is where the stream keeps its messages, jetstream.FileStorage by default. Use
+	// jetstream.MemoryStorage for ephemeral streams that need not survive a server restart.
+	Storage jetstream.StorageType
+	// Discard decides which messages are dropped once a limit is reached: the oldest ones by default,
+	// or, with jetstream.DiscardNew, the ones being published.
+	Discard jetstream.DiscardPolicy
+	// DuplicateWindow is how long the server remembers Nats-Msg-Id headers to discard duplicates, two
+	// minutes when unset. PublishWithID only deduplicates messages published within this window.
+	DuplicateWindow time.Duration
+	// Mirror makes the stream a read-only copy of another stream. A mirror has no subjects of its own.
+	Mirror *StreamSource
+	// Sources are streams whose messages are copied into the stream, in addition to the messages
+	// published to its subjects.
+	Sources []StreamSource
+	// RePublish republishes the messages stored in the stream to another subject, e.g. to fan them
+	// out to an audit log.
+	RePublish *RePublish
+}
+
+// streamFromTemplate returns a copy of tmpl for the stream name, with "{name}" in its subjects replaced by name.
+func streamFromTemplate(name string, tmpl StreamConfig) StreamConfig {
+	cfg := tmpl
+	cfg.Stream = name
+
+	// a mirror has no subjects of its own
+	if len(tmpl.Subjects) == 0 && tmpl.Mirror == nil {
+		cfg.Subjects = []string{name}
+	}
+
+	if len(tmpl.Subjects) > 0 {
+		cfg.Subjects = make([]string, len(tmpl.Subjects))
+
+		for i, subject := range tmpl.Subjects {
+			cfg.Subjects[i] = strings.ReplaceAll(subject, "{name}", name)
+		}
+	}
+
+	if tmpl.Mirror != nil {
+		mirror := *tmpl.Mirror
+		cfg.Mirror = &mirror
+	}
+
+	cfg.Sources = append([]StreamSource(nil), tmpl.Sources...)
+
+	return cfg
+}
+
+// StreamSource is a stream mirrored or sourced by another stream, possibly in another JetStream domain.
+type StreamSource struct {
+	Name string
+	// FilterSubject copies only the messages of the source matching the subject.
+	FilterSubject string
+	// StartSequence and StartTime select the first message copied; by default the whole stream is copied.
+	StartSequence uint64
+	StartTime     time.Time
+	// Domain is the JetStream domain of the source, for sources in another region or leaf node.
+	Domain string
+}
+
+// RePublish republishes the messages of a stream matching Source to Destination once they are stored.
+type RePublish struct {
+	// Source is the subject filter of the republished messages, e.g. "orders.>".
+	Source string
+	// Destination is the subject transform of the republished messages, e.g. "audit.orders.>".
+	Destination string
+	// HeadersOnly republishes the headers of the messages without their payload.
+	HeadersOnly bool
 }
 
 // New creates a new Client.
@@ -36,24 +380,447 @@ func New(cfg *Config, logger pubsub.Logger) *PubSubWrapper {
 	client := &Client{
 		Config:     cfg,
 		subManager: newSubscriptionManager(batchSize),
-		logger:     logger,
+	}
+
+	if logger != nil {
+		client.logger = newNamedLogger(logger, cfg)
 	}
 
 	return &PubSubWrapper{Client: client}
 }
 
+// serverURLs returns the entries of Servers, or of the comma separated Server list when Servers is empty.
+func serverURLs(conf *Config) []string {
+	servers := conf.Servers
+	if len(servers) == 0 {
+		servers = strings.Split(conf.Server, ",")
+	}
+
+	urls := make([]string, 0, len(servers))
+
+	for _, server := range servers {
+		if server = strings.TrimSpace(server); server != "" {
+			urls = append(urls, server)
+		}
+	}
+
+	return urls
+}
+
+// serverList returns the servers of the configuration as a comma separated list, e.g. for logs.
+func serverList(conf *Config) string {
+	return strings.Join(serverURLs(conf), ",")
+}
+
+// validateServerURLs checks that every server URL has a host and the nats or tls scheme.
+func validateServerURLs(conf *Config) error {
+	urls := serverURLs(conf)
+	if len(urls) == 0 {
+		return errServerNotProvided
+	}
+
+	for _, server := range urls {
+		u, err := url.Parse(server)
+		if err != nil || u.Host == "" || (!strings.EqualFold(u.Scheme, "nats") && !strings.EqualFold(u.Scheme, "tls")) {
+			return fmt.Errorf("%w: %q", errInvalidServerURL, server)
+		}
+	}
+
+	return nil
+}
+
+// fetchBatchSize returns the configured fetch batch size, falling back to 1 when it is not positive.
+func fetchBatchSize(conf *Config) int {
+	if conf.BatchSize <= 0 {
+		return 1
+	}
+
+	return conf.BatchSize
+}
+
+// emptyFetchBackoff returns the configured wait after an empty fetch, or defaultEmptyFetchBackoff when it is not positive.
+func emptyFetchBackoff(conf *Config) time.Duration {
+	if conf.EmptyFetchBackoff <= 0 {
+		return defaultEmptyFetchBackoff
+	}
+
+	return conf.EmptyFetchBackoff
+}
+
+// fetchOptions returns the options of a fetch request, adding an idle heartbeat when configured.
+func fetchOptions(conf *Config) []jetstream.FetchOpt {
+	opts := []jetstream.FetchOpt{jetstream.FetchMaxWait(conf.MaxWait)}
+
+	if conf.IdleHeartbeat > 0 {
+		opts = append(opts, jetstream.FetchHeartbeat(conf.IdleHeartbeat))
+	}
+
+	return opts
+}
+
+// autoCreateStream reports whether streams are created by CreateStream, which is the default.
+func autoCreateStream(conf *Config) bool {
+	return conf.AutoCreateStream == nil || *conf.AutoCreateStream
+}
+
+// handlerConcurrency returns the configured handler concurrency, falling back to 1 when it is not positive.
+func handlerConcurrency(conf *Config) int {
+	if conf.Concurrency <= 0 {
+		return 1
+	}
+
+	return conf.Concurrency
+}
+
+// publishRetryBackoff returns the wait before the given retry, starting from the configured backoff,
+// or defaultPublishRetryBackoff when it is not positive, and doubling on every retry.
+func publishRetryBackoff(conf *Config, retry int) time.Duration {
+	backoff := conf.PublishRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultPublishRetryBackoff
+	}
+
+	return backoff << (retry - 1)
+}
+
+// consumerAckWait returns the configured ack wait, falling back to defaultAckWait when it is not positive.
+func consumerAckWait(conf *Config) time.Duration {
+	if conf.AckWait <= 0 {
+		return defaultAckWait
+	}
+
+	return conf.AckWait
+}
+
+// validateBackoffSchedule checks that the redelivery delays are positive and, as the server requires,
+// that a limited number of deliveries leaves room for every delay of the schedule.
+func validateBackoffSchedule(conf *Config) error {
+	for _, delay := range conf.BackoffSchedule {
+		if delay <= 0 {
+			return fmt.Errorf("%w: %v", errInvalidBackoff, delay)
+		}
+	}
+
+	if maxDeliver := consumerMaxDeliver(conf); maxDeliver > 0 && maxDeliver <= len(conf.BackoffSchedule) {
+		return fmt.Errorf("%w: max deliver %d, %d backoff delays", errBackoffExceedsMaxDeliver, maxDeliver, len(conf.BackoffSchedule))
+	}
+
+	return nil
+}
+
+// consumerMaxDeliver returns the configured maximum deliveries, falling back to Stream.MaxDeliver.
+func consumerMaxDeliver(conf *Config) int {
+	if conf.MaxDeliver != 0 {
+		return conf.MaxDeliver
+	}
+
+	return conf.Stream.MaxDeliver
+}
+
+// authMethodCount returns the number of authentication methods configured.
+func authMethodCount(conf *Config) int {
+	count := 0
+
+	for _, configured := range []bool{
+		conf.CredsFile != "",
+		conf.Token != "",
+		conf.Username != "" || conf.Password != "",
+		conf.NKeyFile != "",
+	} {
+		if configured {
+			count++
+		}
+	}
+
+	return count
+}
+
+// subjectNameReplacer maps subject tokens and wildcards to characters allowed in consumer names.
+var subjectNameReplacer = strings.NewReplacer(".", "_", "*", "any", ">", "all")
+
+// isDurable reports whether consumers are created with a durable name.
+func isDurable(conf *Config) bool {
+	return !conf.Ephemeral && !conf.OrderedConsumer
+}
+
+// deliverOptions returns the deliver policy and start position for a consumer, using fallback when
+// StartFrom is not set.
+func deliverOptions(conf *Config, fallback jetstream.DeliverPolicy) (jetstream.DeliverPolicy, uint64, *time.Time) {
+	switch {
+	case conf.StartFrom.StartSequence > 0:
+		return jetstream.DeliverByStartSequencePolicy, conf.StartFrom.StartSequence, nil
+	case !conf.StartFrom.StartTime.IsZero():
+		startTime := conf.StartFrom.StartTime
+
+		return jetstream.DeliverByStartTimePolicy, 0, &startTime
+	default:
+		return fallback, 0, nil
+	}
+}
+
+// orderedConsumerConfig returns the configuration of an ordered consumer for the subject.
+func orderedConsumerConfig(conf *Config, subject string) jetstream.OrderedConsumerConfig {
+	policy, startSeq, startTime := deliverOptions(conf, jetstream.DeliverAllPolicy)
+
+	return jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{consumerFilterSubject(conf, subject)},
+		DeliverPolicy:  policy,
+		OptStartSeq:    startSeq,
+		OptStartTime:   startTime,
+	}
+}
+
+// durableName returns the durable consumer name for a subject. Clients in the same queue group
+// share the durable name so that the server distributes messages between them. With AttachOnly, the
+// name is Consumer as it is.
+func durableName(conf *Config, subject string) string {
+	if conf.AttachOnly {
+		return conf.Consumer
+	}
+
+	name := conf.Consumer
+	if conf.QueueGroup != "" {
+		name = conf.QueueGroup
+	}
+
+	return fmt.Sprintf("%s_%s", name, subjectNameReplacer.Replace(subject))
+}
+
+// configuredStreams returns all streams of the configuration, starting with Stream when it is set.
+// Their subjects carry SubjectPrefix.
+func configuredStreams(conf *Config) []StreamConfig {
+	streams := conf.Streams
+	if conf.Stream.Stream != "" || len(conf.Stream.Subjects) > 0 {
+		streams = append([]StreamConfig{conf.Stream}, conf.Streams...)
+	}
+
+	if conf.SubjectPrefix == "" {
+		return streams
+	}
+
+	prefixed := make([]StreamConfig, len(streams))
+	for i := range streams {
+		prefixed[i] = prefixStreamSubjects(conf, streams[i])
+	}
+
+	return prefixed
+}
+
+// prefixSubject returns subject in the namespace of SubjectPrefix. A nil conf has no prefix.
+func prefixSubject(conf *Config, subject string) string {
+	if conf == nil {
+		return subject
+	}
+
+	return conf.SubjectPrefix + subject
+}
+
+// trimSubjectPrefix returns subject without SubjectPrefix, as the application knows it.
+func trimSubjectPrefix(conf *Config, subject string) string {
+	if conf == nil {
+		return subject
+	}
+
+	return strings.TrimPrefix(subject, conf.SubjectPrefix)
+}
+
+// prefixStreamSubjects returns cfg with its subjects in the namespace of SubjectPrefix.
+func prefixStreamSubjects(conf *Config, cfg StreamConfig) StreamConfig {
+	if conf == nil || conf.SubjectPrefix == "" || len(cfg.Subjects) == 0 {
+		return cfg
+	}
+
+	subjects := make([]string, len(cfg.Subjects))
+	for i, subject := range cfg.Subjects {
+		subjects[i] = prefixSubject(conf, subject)
+	}
+
+	cfg.Subjects = subjects
+
+	if cfg.RePublish != nil {
+		rePublish := *cfg.RePublish
+		rePublish.Source = prefixSubject(conf, rePublish.Source)
+		rePublish.Destination = prefixSubject(conf, rePublish.Destination)
+		cfg.RePublish = &rePublish
+	}
+
+	return cfg
+}
+
+func hasSubjects(conf *Config) bool {
+	for _, stream := range configuredStreams(conf) {
+		if len(stream.Subjects) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// streamForSubject returns the name of the first configured stream with a subject matching the given one.
+func streamForSubject(conf *Config, subject string) (string, bool) {
+	for _, stream := range configuredStreams(conf) {
+		for _, pattern := range stream.Subjects {
+			if subjectMatches(pattern, subject) {
+				return stream.Stream, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// consumerStream returns the stream a consumer for the subject is created on, defaulting to Stream.
+func consumerStream(conf *Config, subject string) string {
+	if stream, ok := streamForSubject(conf, subject); ok {
+		return stream
+	}
+
+	return conf.Stream.Stream
+}
+
+// consumerFilterSubject returns the filter subject of a consumer for the subject, preferring FilterSubject.
+func consumerFilterSubject(conf *Config, subject string) string {
+	if conf.FilterSubject != "" {
+		return prefixSubject(conf, conf.FilterSubject)
+	}
+
+	return subject
+}
+
+// streamsCover reports whether every subject matched by filter is captured by a configured stream.
+func streamsCover(conf *Config, filter string) bool {
+	for _, stream := range configuredStreams(conf) {
+		for _, pattern := range stream.Subjects {
+			if subjectSubsetOf(pattern, filter) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// subjectSubsetOf reports whether every subject matched by filter is also matched by pattern.
+func subjectSubsetOf(pattern, filter string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	filterTokens := strings.Split(filter, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(filterTokens) > i
+		}
+
+		if i >= len(filterTokens) || filterTokens[i] == ">" {
+			return false
+		}
+
+		if token != "*" && token != filterTokens[i] {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(filterTokens)
+}
+
+// subjectMatches reports whether subject matches pattern, honouring the NATS '*' and '>' wildcards.
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(subjectTokens)
+}
+
 // validateConfigs validates the configuration for NATS jStream.
 func validateConfigs(conf *Config) error {
-	if conf.Server == "" {
-		return errServerNotProvided
+	if err := validateServerURLs(conf); err != nil {
+		return err
 	}
 
-	if len(conf.Stream.Subjects) == 0 {
+	if !hasSubjects(conf) {
 		return errSubjectsNotProvided
 	}
 
-	if conf.Consumer == "" {
-		return errConsumerNotProvided
+	if conf.FilterSubject != "" && !streamsCover(conf, prefixSubject(conf, conf.FilterSubject)) {
+		return errFilterSubjectNotInStream
+	}
+
+	if strings.ContainsAny(conf.SubjectPrefix, "*> \t") {
+		return fmt.Errorf("%w: %q", errInvalidSubjectPrefix, conf.SubjectPrefix)
+	}
+
+	if authMethodCount(conf) > 1 {
+		return errMultipleAuthMethods
+	}
+
+	if conf.JetStreamDomain != "" && conf.APIPrefix != "" {
+		return errDomainWithAPIPrefix
+	}
+
+	if conf.TLS != nil && (conf.TLS.CertFile == "") != (conf.TLS.KeyFile == "") {
+		return errCertAndKeyRequired
+	}
+
+	if conf.MaxDeliver < -1 {
+		return errInvalidMaxDeliver
+	}
+
+	if err := validateBackoffSchedule(conf); err != nil {
+		return err
+	}
+
+	if cb := conf.CircuitBreaker; cb != nil && (cb.FailureThreshold <= 0 || cb.OpenDuration <= 0) {
+		return errInvalidCircuitBreaker
+	}
+
+	if conf.StartFrom.StartSequence > 0 && !conf.StartFrom.StartTime.IsZero() {
+		return errStartFromConflict
+	}
+
+	if err := validateHeartbeat(conf); err != nil {
+		return err
+	}
+
+	if conf.Ephemeral && conf.QueueGroup != "" {
+		return errQueueGroupWithEphemeral
+	}
+
+	if conf.OrderedConsumer && (conf.Consumer != "" || conf.QueueGroup != "") {
+		return errOrderedConsumerConflict
+	}
+
+	if conf.AttachOnly && (conf.Consumer == "" || !isDurable(conf)) {
+		return errAttachOnlyRequiresConsumer
+	}
+
+	if isDurable(conf) && conf.Consumer == "" && conf.QueueGroup == "" {
+		return errConsumerRequiredForDurable
+	}
+
+	return nil
+}
+
+func validateHeartbeat(conf *Config) error {
+	if conf.IdleHeartbeat <= 0 {
+		return nil
+	}
+
+	if !conf.FlowControl && !conf.OrderedConsumer {
+		return errHeartbeatWithoutFlowControl
+	}
+
+	if conf.MaxWait > 0 && conf.IdleHeartbeat > conf.MaxWait/2 {
+		return errHeartbeatTooLong
 	}
 
 	return nil
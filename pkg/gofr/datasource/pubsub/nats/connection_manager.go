@@ -2,6 +2,12 @@ package nats
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -11,19 +17,35 @@ import (
 	"gofr.dev/pkg/gofr/datasource/pubsub"
 )
 
-//go:generate mockgen -destination=mock_jetstream.go -package=nats github.com/nats-io/nats.go/jetstream jStream,Stream,Consumer,Msg,MessageBatch
+//go:generate mockgen -destination=mock_jetstream.go -package=nats github.com/nats-io/nats.go/jetstream JetStream,Stream,Consumer,Msg,MessageBatch,PubAckFuture,KeyValue,KeyWatcher,KeyValueEntry,ObjectStore,ObjectResult
 
 const (
-	ctxCloseTimeout = 5 * time.Second
+	ctxCloseTimeout       = 5 * time.Second
+	asyncStallWait        = 200 * time.Millisecond
+	defaultReconnectWait  = time.Second
+	maxReconnectBackoff   = 30 * time.Second
+	initialConnectTimeout = 5 * time.Minute
+	drainPollInterval     = 10 * time.Millisecond
+	defaultFlushTimeout   = 5 * time.Second
+	defaultConnectionName = "GoFr NATS JetStreamClient"
 )
 
 type ConnectionManager struct {
 	conn             ConnInterface
 	jStream          jetstream.JetStream
+	maxPayload       int64
 	config           *Config
 	logger           pubsub.Logger
+	metrics          Metrics
 	natsConnector    Connector
 	jetStreamCreator JetStreamCreator
+	streamNames      *streamNameCache
+	// clock defaults to the real clock when nil.
+	clock clock
+
+	// stopped is closed once the connection is closed or drained, see stoppedChan.
+	stopMu  sync.Mutex
+	stopped chan struct{}
 }
 
 func (cm *ConnectionManager) jetStream() (jetstream.JetStream, error) {
@@ -47,6 +69,27 @@ func (w *natsConnWrapper) Close() {
 	w.conn.Close()
 }
 
+func (w *natsConnWrapper) ConnectedClusterName() string {
+	return w.conn.ConnectedClusterName()
+}
+
+// Drain drains the connection and blocks until it has been closed.
+func (w *natsConnWrapper) Drain() error {
+	if err := w.conn.Drain(); err != nil {
+		return err
+	}
+
+	for !w.conn.IsClosed() {
+		time.Sleep(drainPollInterval)
+	}
+
+	return nil
+}
+
+func (w *natsConnWrapper) MaxPayload() int64 {
+	return w.conn.MaxPayload()
+}
+
 func (w *natsConnWrapper) NATSConn() *nats.Conn {
 	return w.conn
 }
@@ -55,6 +98,30 @@ func (w *natsConnWrapper) JetStream() (jetstream.JetStream, error) {
 	return jetstream.New(w.conn)
 }
 
+func (w *natsConnWrapper) FlushWithContext(ctx context.Context) error {
+	return w.conn.FlushWithContext(ctx)
+}
+
+func (w *natsConnWrapper) RTT() (time.Duration, error) {
+	return w.conn.RTT()
+}
+
+func (w *natsConnWrapper) Subscribe(subject string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return w.conn.Subscribe(subject, cb)
+}
+
+func (w *natsConnWrapper) PublishMsg(msg *nats.Msg) error {
+	return w.conn.PublishMsg(msg)
+}
+
+func (w *natsConnWrapper) RequestWithContext(ctx context.Context, subject string, data []byte) (*nats.Msg, error) {
+	return w.conn.RequestWithContext(ctx, subject, data)
+}
+
+func (w *natsConnWrapper) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	return w.conn.RequestMsgWithContext(ctx, msg)
+}
+
 // NewConnectionManager creates a new ConnectionManager.
 func NewConnectionManager(
 	cfg *Config,
@@ -71,7 +138,14 @@ func NewConnectionManager(
 	}
 
 	if jetStreamCreator == nil {
-		jetStreamCreator = &DefaultJetStreamCreator{}
+		creator := &DefaultJetStreamCreator{}
+
+		if cfg != nil {
+			creator.Domain = cfg.JetStreamDomain
+			creator.APIPrefix = cfg.APIPrefix
+		}
+
+		jetStreamCreator = creator
 	}
 
 	return &ConnectionManager{
@@ -84,13 +158,14 @@ func NewConnectionManager(
 
 // Connect establishes a connection to NATS and sets up JetStream.
 func (cm *ConnectionManager) Connect() error {
-	opts := []nats.Option{nats.Name("GoFr NATS JetStreamClient")}
+	opts, err := cm.connectionOptions()
+	if err != nil {
+		cm.logger.Errorf("invalid NATS connection options: %v", err)
 
-	if cm.config.CredsFile != "" {
-		opts = append(opts, nats.UserCredentials(cm.config.CredsFile))
+		return err
 	}
 
-	connInterface, err := cm.natsConnector.Connect(cm.config.Server, opts...)
+	connInterface, err := cm.connect(opts)
 	if err != nil {
 		return err
 	}
@@ -105,13 +180,289 @@ func (cm *ConnectionManager) Connect() error {
 
 	cm.conn = connInterface
 	cm.jStream = js
+	cm.maxPayload = connInterface.MaxPayload()
+
+	return nil
+}
+
+func (cm *ConnectionManager) connectionOptions() ([]nats.Option, error) {
+	opts := []nats.Option{nats.Name(connectionName(cm.config))}
+
+	authOpts, err := authOptions(cm.config)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, authOpts...)
+	opts = append(opts, tlsOptions(cm.config.TLS)...)
+
+	if cm.config.MaxReconnects != 0 {
+		opts = append(opts, nats.MaxReconnects(cm.config.MaxReconnects))
+	}
+
+	if cm.config.ReconnectWait > 0 {
+		opts = append(opts, nats.ReconnectWait(cm.config.ReconnectWait))
+	}
+
+	if !cm.config.RandomizeServers {
+		opts = append(opts, nats.DontRandomize())
+	}
+
+	opts = append(opts, cm.eventHandlerOptions()...)
+
+	// custom options come last so that they take precedence
+	opts = append(opts, cm.config.Options...)
+
+	return opts, nil
+}
+
+// eventHandlerOptions returns the options logging and counting disconnects, reconnects and the
+// closing of the connection.
+func (cm *ConnectionManager) eventHandlerOptions() []nats.Option {
+	return []nats.Option{
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			cm.logger.Logf("WARN: disconnected from NATS server at %v: %v", serverList(cm.config), err)
+			cm.incrementCounter(disconnectCountMetric)
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			cm.logger.Logf("WARN: reconnected to NATS server at %v", nc.ConnectedUrlRedacted())
+			cm.incrementCounter(reconnectCountMetric)
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			cm.logger.Logf("WARN: connection to NATS server at %v closed", serverList(cm.config))
+		}),
+	}
+}
+
+func (cm *ConnectionManager) incrementCounter(name string) {
+	if cm.metrics == nil {
+		return
+	}
+
+	cm.metrics.IncrementCounter(context.Background(), name, "server", serverList(cm.config))
+}
+
+// RTT measures the round trip time to the server and reports it as the app_nats_rtt_seconds gauge.
+// It returns early with the error of ctx when ctx is done before the server responds.
+func (cm *ConnectionManager) RTT(ctx context.Context) (time.Duration, error) {
+	if cm.conn == nil {
+		return 0, errConnectionNotEstablished
+	}
+
+	type result struct {
+		rtt time.Duration
+		err error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		rtt, err := cm.conn.RTT()
+		done <- result{rtt: rtt, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			cm.logger.Errorf("failed to measure round trip time to NATS server: %v", res.err)
+
+			return 0, res.err
+		}
+
+		if cm.metrics != nil {
+			cm.metrics.SetGauge(rttMetric, res.rtt.Seconds(), "server", serverList(cm.config))
+		}
+
+		return res.rtt, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// Status returns the status of the connection, DISCONNECTED if it was never established.
+func (cm *ConnectionManager) Status() nats.Status {
+	if cm.conn == nil {
+		return nats.DISCONNECTED
+	}
+
+	return cm.conn.Status()
+}
+
+// Flush blocks until everything buffered on the connection has been sent and processed by the
+// server, or ctx is done. Contexts without a deadline are given one of defaultFlushTimeout, as the
+// NATS client requires it.
+func (cm *ConnectionManager) Flush(ctx context.Context) error {
+	if cm.conn == nil {
+		return errConnectionNotEstablished
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, defaultFlushTimeout)
+		defer cancel()
+	}
+
+	if err := cm.conn.FlushWithContext(ctx); err != nil {
+		cm.logger.Errorf("failed to flush NATS connection: %v", err)
+
+		return err
+	}
 
 	return nil
 }
 
-func (cm *ConnectionManager) Close(ctx context.Context) {
-	if cm.conn != nil {
+// connectionName returns the name the connection is reported with by the server, falling back to
+// the APP_NAME of the GoFr application, which the container does not pass to datasources.
+func connectionName(cfg *Config) string {
+	if cfg.ConnectionName != "" {
+		return cfg.ConnectionName
+	}
+
+	if appName := os.Getenv("APP_NAME"); appName != "" {
+		return appName
+	}
+
+	return defaultConnectionName
+}
+
+// authOptions returns the option for the configured authentication method. validateConfigs
+// ensures that at most one method is set.
+func authOptions(cfg *Config) ([]nats.Option, error) {
+	switch {
+	case cfg.CredsFile != "":
+		return []nats.Option{nats.UserCredentials(cfg.CredsFile)}, nil
+	case cfg.Token != "":
+		return []nats.Option{nats.Token(cfg.Token)}, nil
+	case cfg.Username != "" || cfg.Password != "":
+		return []nats.Option{nats.UserInfo(cfg.Username, cfg.Password)}, nil
+	case cfg.NKeyFile != "":
+		opt, err := nats.NkeyOptionFromSeed(cfg.NKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w from %s: %w", errInvalidNKeySeed, cfg.NKeyFile, err)
+		}
+
+		return []nats.Option{opt}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func tlsOptions(cfg *TLSConfig) []nats.Option {
+	if cfg == nil {
+		return nil
+	}
+
+	var opts []nats.Option
+
+	if cfg.CertFile != "" {
+		opts = append(opts, nats.ClientCert(cfg.CertFile, cfg.KeyFile))
+	}
+
+	if cfg.CAFile != "" {
+		opts = append(opts, nats.RootCAs(cfg.CAFile))
+	}
+
+	if cfg.InsecureSkipVerify {
+		opts = append(opts, nats.Secure(&tls.Config{InsecureSkipVerify: true})) //nolint:gosec // explicitly requested via config
+	}
+
+	return opts
+}
+
+// connect dials the NATS server. When RetryOnInitialConnect is set, failed attempts are retried with
+// exponential backoff until MaxReconnects attempts (if positive) or initialConnectTimeout is exhausted.
+func (cm *ConnectionManager) connect(opts []nats.Option) (ConnInterface, error) {
+	servers := serverList(cm.config)
+
+	if !cm.config.RetryOnInitialConnect {
+		return cm.natsConnector.Connect(servers, opts...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), initialConnectTimeout)
+	defer cancel()
+
+	wait := cm.config.ReconnectWait
+	if wait <= 0 {
+		wait = defaultReconnectWait
+	}
+
+	for attempt := 1; ; attempt++ {
+		conn, err := cm.natsConnector.Connect(servers, opts...)
+		if err == nil {
+			return conn, nil
+		}
+
+		if cm.config.MaxReconnects > 0 && attempt > cm.config.MaxReconnects {
+			return nil, err
+		}
+
+		cm.logger.Logf("WARN: failed to connect to NATS server at %v (attempt %d), retrying in %v: %v",
+			serverList(cm.config), attempt, wait, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-clockOrReal(cm.clock).After(wait):
+		}
+
+		wait = min(wait*2, maxReconnectBackoff)
+	}
+}
+
+// Close closes the connection. When Config.DrainTimeout is set, the connection is drained first and
+// forcefully closed if draining does not complete in time.
+func (cm *ConnectionManager) Close(ctx context.Context) error {
+	defer cm.releasePubAckTrackers()
+
+	if cm.conn == nil {
+		return nil
+	}
+
+	if cm.config == nil || cm.config.DrainTimeout <= 0 {
+		cm.conn.Close()
+
+		return nil
+	}
+
+	timer := clockOrReal(cm.clock).NewTimer(cm.config.DrainTimeout)
+	defer timer.Stop()
+
+	return cm.drain(ctx, timer.C())
+}
+
+// Drain drains the connection regardless of Config.DrainTimeout, closing it forcefully when ctx is
+// done before draining completes.
+func (cm *ConnectionManager) Drain(ctx context.Context) error {
+	defer cm.releasePubAckTrackers()
+
+	if cm.conn == nil {
+		return nil
+	}
+
+	return cm.drain(ctx, nil)
+}
+
+// drain drains the connection, closing it forcefully when ctx is done or timeout fires first. A nil
+// timeout never fires.
+func (cm *ConnectionManager) drain(ctx context.Context, timeout <-chan time.Time) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cm.conn.Drain()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timeout:
+		cm.conn.Close()
+
+		return fmt.Errorf("%w after %v", errDrainTimeout, cm.config.DrainTimeout)
+	case <-ctx.Done():
 		cm.conn.Close()
+
+		return ctx.Err()
 	}
 }
 
@@ -122,17 +473,476 @@ func (cm *ConnectionManager) Publish(ctx context.Context, subject string, messag
 		return err
 	}
 
-	_, err := cm.jStream.Publish(ctx, subject, message)
+	if err := cm.validatePayload(message); err != nil {
+		return err
+	}
+
+	start := clockOrReal(cm.clock).Now()
+
+	err := cm.publishWithRetry(ctx, subject, metrics, func() error {
+		_, err := cm.jStream.Publish(ctx, subject, message, cm.publishOptions(subject)...)
+
+		return err
+	})
+	if cm.fallbackToCore(err) {
+		return cm.publishCore(ctx, &nats.Msg{Subject: subject, Data: message}, err, metrics)
+	}
+
 	if err != nil {
 		cm.logger.Errorf("failed to publish message to NATS jStream: %v", err)
+
+		return err
+	}
+
+	cm.recordPublishMetrics(ctx, subject, message, start, metrics)
+	metrics.IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
+
+	return nil
+}
+
+// PublishAsync publishes a message without waiting for the PubAck. The success counter is incremented
+// once the returned future resolves without error. If the async pending buffer is full, the call keeps
+// retrying until there is room or ctx is done. The headers, if any, are published along with the message.
+func (cm *ConnectionManager) PublishAsync(ctx context.Context, subject string, message []byte, headers nats.Header,
+	metrics Metrics) (jetstream.PubAckFuture, error) {
+	future, err := cm.publishAsync(ctx, subject, message, headers, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	go cm.trackPubAck(ctx, subject, future, metrics)
+
+	return future, nil
+}
+
+// PublishBatch publishes the messages asynchronously and waits for all of them to be acknowledged.
+// Every message is attempted; the returned error joins the failures along with the index of the
+// message each one belongs to.
+func (cm *ConnectionManager) PublishBatch(ctx context.Context, subject string, msgs [][]byte, metrics Metrics) error {
+	futures := make([]jetstream.PubAckFuture, len(msgs))
+	errs := make([]error, 0)
+
+	for i, msg := range msgs {
+		future, err := cm.publishAsync(ctx, subject, msg, nil, metrics)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w at index %d: %w", errBatchPublishFailed, i, err))
+
+			continue
+		}
+
+		futures[i] = future
+	}
+
+	for i, future := range futures {
+		if future == nil {
+			continue
+		}
+
+		select {
+		case <-future.Ok():
+			metrics.IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
+		case err := <-future.Err():
+			errs = append(errs, fmt.Errorf("%w at index %d: %w", errBatchPublishFailed, i, err))
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("%w at index %d: %w", errBatchPublishFailed, i, ctx.Err()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// publishAsync publishes a message without waiting for the PubAck, retrying while the async pending
+// buffer is full.
+func (cm *ConnectionManager) publishAsync(ctx context.Context, subject string, message []byte, headers nats.Header,
+	metrics Metrics) (jetstream.PubAckFuture, error) {
+	metrics.IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
+
+	if err := cm.validateJetStream(subject); err != nil {
+		return nil, err
+	}
+
+	if err := cm.validatePayload(message); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		opts := append(cm.publishOptions(subject), jetstream.WithStallWait(asyncStallWait))
+
+		var (
+			future jetstream.PubAckFuture
+			err    error
+		)
+
+		if len(headers) == 0 {
+			future, err = cm.jStream.PublishAsync(subject, message, opts...)
+		} else {
+			future, err = cm.jStream.PublishMsgAsync(&nats.Msg{Subject: subject, Data: message, Header: headers}, opts...)
+		}
+
+		if err == nil {
+			return future, nil
+		}
+
+		if !errors.Is(err, jetstream.ErrTooManyStalledMsgs) {
+			cm.logger.Errorf("failed to publish message to NATS jStream: %v", err)
+
+			return nil, err
+		}
+
+		cm.logger.Debugf("async publish buffer full for subject %s, retrying", subject)
+	}
+}
+
+func (cm *ConnectionManager) trackPubAck(ctx context.Context, subject string, future jetstream.PubAckFuture, metrics Metrics) {
+	// the context of the publish is not waited on, as it usually ends before the acknowledgement arrives
+	select {
+	case <-future.Ok():
+		metrics.IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
+	case err := <-future.Err():
+		cm.logger.Errorf("failed to publish message to NATS jStream: %v", err)
+	case <-cm.stoppedChan():
+	}
+}
+
+// stoppedChan returns the channel that is closed once the connection is closed or drained.
+func (cm *ConnectionManager) stoppedChan() <-chan struct{} {
+	cm.stopMu.Lock()
+	defer cm.stopMu.Unlock()
+
+	if cm.stopped == nil {
+		cm.stopped = make(chan struct{})
+	}
+
+	return cm.stopped
+}
+
+// releasePubAckTrackers stops the goroutines waiting for the acknowledgement of async publishes, whose
+// futures may never resolve once the connection is gone.
+func (cm *ConnectionManager) releasePubAckTrackers() {
+	cm.stopMu.Lock()
+	defer cm.stopMu.Unlock()
+
+	if cm.stopped == nil {
+		cm.stopped = make(chan struct{})
+	}
+
+	select {
+	case <-cm.stopped:
+	default:
+		close(cm.stopped)
+	}
+}
+
+// PublishAsyncComplete blocks until all outstanding async publishes are acknowledged or ctx is done.
+func (cm *ConnectionManager) PublishAsyncComplete(ctx context.Context) error {
+	if cm.jStream == nil {
+		return errJetStreamNotConfigured
+	}
+
+	select {
+	case <-cm.jStream.PublishAsyncComplete():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PublishWithHeaders publishes a message along with the given headers. Publishing with empty headers
+// is equivalent to Publish.
+func (cm *ConnectionManager) PublishWithHeaders(
+	ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) error {
+	if len(headers) == 0 {
+		return cm.Publish(ctx, subject, message, metrics)
+	}
+
+	_, err := cm.PublishAck(ctx, subject, message, headers, metrics)
+
+	return err
+}
+
+// PublishAck publishes a message along with the given headers, which may be empty, and returns the
+// acknowledgement of the server. Messages published over core NATS are not acknowledged, so their
+// acknowledgement is nil.
+func (cm *ConnectionManager) PublishAck(
+	ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) (*jetstream.PubAck, error) {
+	metrics.IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
+
+	if err := cm.validateJetStream(subject); err != nil {
+		return nil, err
+	}
+
+	if err := cm.validatePayload(message); err != nil {
+		return nil, err
+	}
+
+	if len(headers) > 0 {
+		cm.logger.Debugf("publishing message to subject %s with headers %v", subject, headerKeys(headers))
+	}
+
+	start := clockOrReal(cm.clock).Now()
+
+	var ack *jetstream.PubAck
+
+	err := cm.publishWithRetry(ctx, subject, metrics, func() error {
+		var err error
+
+		ack, err = cm.jStream.PublishMsg(ctx, &nats.Msg{Subject: subject, Data: message, Header: headers}, cm.publishOptions(subject)...)
+
 		return err
+	})
+	if cm.fallbackToCore(err) {
+		return nil, cm.publishCore(ctx, &nats.Msg{Subject: subject, Data: message, Header: headers}, err, metrics)
 	}
 
+	if err != nil {
+		cm.logger.Errorf("failed to publish message to NATS jStream: %v", err)
+
+		return nil, err
+	}
+
+	if ack != nil && ack.Duplicate {
+		cm.logger.Debugf("message to subject %s was a duplicate of stream %s sequence %d", subject, ack.Stream, ack.Sequence)
+	}
+
+	cm.recordPublishMetrics(ctx, subject, message, start, metrics)
 	metrics.IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
 
+	return ack, nil
+}
+
+// publishWithRetry calls publish, retrying it up to Config.PublishRetries times with exponential
+// backoff while it fails with a transient error. Every retry increments the publish retry counter.
+func (cm *ConnectionManager) publishWithRetry(ctx context.Context, subject string, metrics Metrics, publish func() error) error {
+	var retries int
+
+	if cm.config != nil {
+		retries = cm.config.PublishRetries
+	}
+
+	for retry := 1; ; retry++ {
+		err := publish()
+		if err == nil {
+			return nil
+		}
+
+		err = cm.publishError(ctx, subject, err)
+		if retry > retries || !isRetryablePublishError(err) {
+			return err
+		}
+
+		backoff := publishRetryBackoff(cm.config, retry)
+
+		cm.logger.Logf("WARN: retrying publish to subject %s in %v (attempt %d of %d): %v", subject, backoff, retry, retries, err)
+		metrics.IncrementCounter(ctx, publishRetryCountMetric, "subject", subject)
+
+		select {
+		case <-clockOrReal(cm.clock).After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// isRetryablePublishError reports whether a publish failed for a transient reason, i.e. it timed out
+// or no server responded, so that publishing the message again may succeed.
+func isRetryablePublishError(err error) bool {
+	return errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, nats.ErrNoResponders) || errors.Is(err, jetstream.ErrNoStreamResponse)
+}
+
+// publishError replaces the error of a publish no stream responded to with errNoStreamForSubject
+// when the server confirms that no stream captures the subject.
+func (cm *ConnectionManager) publishError(ctx context.Context, subject string, err error) error {
+	if !errors.Is(err, jetstream.ErrNoStreamResponse) {
+		return err
+	}
+
+	_, lookupErr := cm.streamNameBySubject(ctx, subject)
+
+	switch {
+	case errors.Is(lookupErr, jetstream.ErrStreamNotFound):
+		return fmt.Errorf("%w: %s", errNoStreamForSubject, subject)
+	case isJetStreamUnavailable(lookupErr):
+		return lookupErr
+	default:
+		// a lookup that failed for another reason, e.g. a timeout, confirms nothing
+		return err
+	}
+}
+
+// streamNameBySubject returns the stream capturing subject, answering from the stream name cache while
+// its entry is fresh. That no stream captures the subject is cached as well.
+func (cm *ConnectionManager) streamNameBySubject(ctx context.Context, subject string) (string, error) {
+	if stream, found, ok := cm.streamNames.get(subject); ok {
+		if !found {
+			return "", jetstream.ErrStreamNotFound
+		}
+
+		return stream, nil
+	}
+
+	stream, err := cm.jStream.StreamNameBySubject(ctx, subject)
+
+	switch {
+	case err == nil:
+		cm.streamNames.set(subject, stream, true)
+	case errors.Is(err, jetstream.ErrStreamNotFound):
+		cm.streamNames.set(subject, "", false)
+	}
+
+	return stream, err
+}
+
+// isJetStreamUnavailable reports whether err means that JetStream is not enabled on the server or account.
+func isJetStreamUnavailable(err error) bool {
+	return errors.Is(err, jetstream.ErrJetStreamNotEnabled) || errors.Is(err, jetstream.ErrJetStreamNotEnabledForAccount) ||
+		errors.Is(err, nats.ErrJetStreamNotEnabled) || errors.Is(err, nats.ErrJetStreamNotEnabledForAccount)
+}
+
+// fallbackToCore reports whether a publish that failed with err is sent over core NATS instead.
+func (cm *ConnectionManager) fallbackToCore(err error) bool {
+	return err != nil && cm.config != nil && cm.config.FallbackToCoreNATS && cm.conn != nil && isJetStreamUnavailable(err)
+}
+
+// publishCore publishes msg over core NATS after JetStream failed with jsErr. The message is not
+// persisted and its delivery is not acknowledged.
+func (cm *ConnectionManager) publishCore(ctx context.Context, msg *nats.Msg, jsErr error, metrics Metrics) error {
+	cm.logger.Logf("WARN: JetStream is unavailable, publishing to subject %s over core NATS: %v", msg.Subject, jsErr)
+	metrics.IncrementCounter(ctx, coreFallbackCountMetric, "subject", msg.Subject)
+
+	if err := cm.conn.PublishMsg(msg); err != nil {
+		cm.logger.Errorf("failed to publish message to NATS: %v", err)
+
+		return err
+	}
+
+	metrics.IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", msg.Subject)
+
+	return nil
+}
+
+// recordPublishMetrics records the size of a published message and the time taken, since start, for it to be acknowledged.
+func (cm *ConnectionManager) recordPublishMetrics(ctx context.Context, subject string, message []byte, start time.Time, metrics Metrics) {
+	stream := cm.streamLabel(ctx, subject)
+
+	metrics.RecordHistogram(ctx, publishDurationMetric, clockOrReal(cm.clock).Now().Sub(start).Seconds(), streamLabels(cm.config, stream)...)
+	metrics.RecordHistogram(ctx, messageBytesMetric, float64(len(message)), streamLabels(cm.config, stream, "direction", "publish")...)
+}
+
+// streamLabel returns the stream capturing subject, for metric labels. Subjects outside the configured
+// streams are looked up on the server when the stream name cache is enabled, so that only the first
+// publish to such a subject within Config.StreamInfoCacheTTL costs a round trip.
+func (cm *ConnectionManager) streamLabel(ctx context.Context, subject string) string {
+	if cm.config == nil {
+		return ""
+	}
+
+	if stream, ok := streamForSubject(cm.config, subject); ok {
+		return stream
+	}
+
+	if cm.streamNames == nil || cm.jStream == nil {
+		return ""
+	}
+
+	stream, err := cm.streamNameBySubject(ctx, subject)
+	if err != nil {
+		return ""
+	}
+
+	return stream
+}
+
+// publishOptions pins the publish to the configured stream owning the subject, if any.
+func (cm *ConnectionManager) publishOptions(subject string) []jetstream.PublishOpt {
+	if cm.config == nil {
+		return nil
+	}
+
+	if stream, ok := streamForSubject(cm.config, subject); ok {
+		return []jetstream.PublishOpt{jetstream.WithExpectStream(stream)}
+	}
+
 	return nil
 }
 
+func headerKeys(headers nats.Header) []string {
+	keys := make([]string, 0, len(headers))
+
+	for key := range headers {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Request sends a request over core NATS and waits for the reply until ctx is done.
+func (cm *ConnectionManager) Request(ctx context.Context, subject string, data []byte, metrics Metrics) (*pubsub.Message, error) {
+	return cm.RequestWithHeaders(ctx, subject, data, nil, metrics)
+}
+
+// RequestWithHeaders sends a request with headers, e.g. an auth token, like Request.
+func (cm *ConnectionManager) RequestWithHeaders(ctx context.Context, subject string, data []byte, headers nats.Header,
+	metrics Metrics) (*pubsub.Message, error) {
+	metrics.IncrementCounter(ctx, requestTotalCountMetric, "subject", subject)
+
+	if cm.conn == nil {
+		cm.logger.Error(errConnectionNotEstablished.Error())
+
+		return nil, errConnectionNotEstablished
+	}
+
+	var (
+		reply *nats.Msg
+		err   error
+	)
+
+	if len(headers) == 0 {
+		reply, err = cm.conn.RequestWithContext(ctx, subject, data)
+	} else {
+		reply, err = cm.conn.RequestMsgWithContext(ctx, &nats.Msg{Subject: subject, Data: data, Header: headers})
+	}
+
+	if err != nil {
+		cm.logger.Errorf("failed to send request to subject %s: %v", subject, err)
+		return nil, err
+	}
+
+	metrics.IncrementCounter(ctx, requestSuccessCountMetric, "subject", subject)
+
+	msg := pubsub.NewMessage(ctx)
+	msg.Topic = subject
+	msg.Value = reply.Data
+	msg.MetaData = reply.Header
+
+	return msg, nil
+}
+
+// SubscribeRequests subscribes handler to the core NATS messages of subject, which include the
+// requests sent with Request along with their reply subject.
+func (cm *ConnectionManager) SubscribeRequests(subject string, handler nats.MsgHandler) (*nats.Subscription, error) {
+	if cm.conn == nil {
+		return nil, errConnectionNotEstablished
+	}
+
+	return cm.conn.Subscribe(subject, handler)
+}
+
+// PublishReply publishes the reply to a request on core NATS.
+func (cm *ConnectionManager) PublishReply(reply *nats.Msg) error {
+	if cm.conn == nil {
+		return errConnectionNotEstablished
+	}
+
+	return cm.conn.PublishMsg(reply)
+}
+
 func (cm *ConnectionManager) validateJetStream(subject string) error {
 	if cm.jStream == nil || subject == "" {
 		err := errJetStreamNotConfigured
@@ -144,6 +954,15 @@ func (cm *ConnectionManager) validateJetStream(subject string) error {
 	return nil
 }
 
+// validatePayload rejects messages exceeding the maximum payload announced by the server.
+func (cm *ConnectionManager) validatePayload(message []byte) error {
+	if cm.maxPayload > 0 && int64(len(message)) > cm.maxPayload {
+		return fmt.Errorf("%w: %d bytes exceeds the server limit of %d bytes", errPayloadTooLarge, len(message), cm.maxPayload)
+	}
+
+	return nil
+}
+
 func (cm *ConnectionManager) Health() datasource.Health {
 	if cm.conn == nil {
 		return datasource.Health{
@@ -156,7 +975,9 @@ func (cm *ConnectionManager) Health() datasource.Health {
 		return datasource.Health{
 			Status: datasource.StatusUp,
 			Details: map[string]interface{}{
-				"server": cm.config.Server,
+				"server":            serverList(cm.config),
+				"connection_status": status.String(),
+				"cluster":           cm.conn.ConnectedClusterName(),
 			},
 		}
 	}
@@ -164,7 +985,8 @@ func (cm *ConnectionManager) Health() datasource.Health {
 	return datasource.Health{
 		Status: datasource.StatusDown,
 		Details: map[string]interface{}{
-			"server": cm.config.Server,
+			"server":            serverList(cm.config),
+			"connection_status": status.String(),
 		},
 	}
 }
@@ -2,6 +2,8 @@ package nats
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -13,75 +15,1098 @@ import (
 	"go.uber.org/mock/gomock"
 	"gofr.dev/pkg/gofr/datasource"
 	"gofr.dev/pkg/gofr/logging"
+	"gofr.dev/pkg/gofr/testutil"
 )
 
+// testNKeySeed is a throwaway NKey user seed used only by tests.
+const testNKeySeed = "SUAOXKHJXCP35GEC4GBHMYAANF22CA6EW4KKLOQAKQI6GACOWI3HX3BYXY"
+
 func TestNewConnectionManager(t *testing.T) {
 	cfg := &Config{Server: "nats://localhost:4222"}
 	logger := logging.NewMockLogger(logging.DEBUG)
 	natsConnector := &MockNATSConnector{}
 	jsCreator := &MockJetStreamCreator{}
 
-	cm := NewConnectionManager(cfg, logger, natsConnector, jsCreator)
+	cm := NewConnectionManager(cfg, logger, natsConnector, jsCreator)
+
+	assert.NotNil(t, cm)
+	assert.Equal(t, cfg, cm.config)
+	assert.Equal(t, logger, cm.logger)
+	assert.Equal(t, natsConnector, cm.natsConnector)
+	assert.Equal(t, jsCreator, cm.jetStreamCreator)
+}
+
+func TestConnectionManager_Connect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+
+	cm := NewConnectionManager(
+		&Config{Server: "nats://localhost:4222"},
+		logging.NewMockLogger(logging.DEBUG),
+		mockNATSConnector,
+		mockJSCreator,
+	)
+
+	mockNATSConnector.EXPECT().
+		Connect(gomock.Any(), gomock.Any()).
+		Return(mockConn, nil)
+
+	// We don't need to expect NATSConn() call anymore, as we're passing mockConn directly to New()
+	mockJSCreator.EXPECT().
+		New(mockConn).
+		Return(mockJS, nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	err := cm.Connect()
+	require.NoError(t, err)
+	assert.Equal(t, mockConn, cm.conn)
+	assert.Equal(t, mockJS, cm.jStream)
+	assert.Equal(t, int64(1048576), cm.maxPayload)
+}
+
+func TestConnectionManager_Connect_ServerList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+
+	cm := NewConnectionManager(
+		&Config{Server: "nats://n1:4222, nats://n2:4222 ,tls://n3:4222"},
+		logging.NewMockLogger(logging.DEBUG),
+		mockNATSConnector,
+		mockJSCreator,
+	)
+
+	// every server of the cluster is passed on, so the client can fail over between them
+	mockNATSConnector.EXPECT().Connect("nats://n1:4222,nats://n2:4222,tls://n3:4222", gomock.Any()).Return(mockConn, nil)
+	mockJSCreator.EXPECT().New(mockConn).Return(NewMockJetStream(ctrl), nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	require.NoError(t, cm.Connect())
+}
+
+func TestConnectionManager_Connect_Servers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+
+	cm := NewConnectionManager(
+		&Config{Server: "nats://ignored:4222", Servers: []string{"nats://n1:4222", "nats://leaf:7422"}},
+		logging.NewMockLogger(logging.DEBUG),
+		mockNATSConnector,
+		mockJSCreator,
+	)
+
+	// Servers takes precedence over Server, and the servers are tried in order unless randomized
+	mockNATSConnector.EXPECT().Connect("nats://n1:4222,nats://leaf:7422", gomock.Any()).
+		DoAndReturn(func(_ string, opts ...nats.Option) (ConnInterface, error) {
+			natsOpts := nats.GetDefaultOptions()
+			for _, opt := range opts {
+				require.NoError(t, opt(&natsOpts))
+			}
+
+			assert.True(t, natsOpts.NoRandomize)
+
+			return mockConn, nil
+		})
+	mockJSCreator.EXPECT().New(mockConn).Return(NewMockJetStream(ctrl), nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	require.NoError(t, cm.Connect())
+
+	opts, err := NewConnectionManager(&Config{Servers: []string{"nats://n1:4222"}, RandomizeServers: true},
+		logging.NewMockLogger(logging.DEBUG), mockNATSConnector, mockJSCreator).connectionOptions()
+	require.NoError(t, err)
+
+	natsOpts := nats.GetDefaultOptions()
+	for _, opt := range opts {
+		require.NoError(t, opt(&natsOpts))
+	}
+
+	assert.False(t, natsOpts.NoRandomize)
+}
+
+func TestConnectionManager_Connect_CustomOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+
+	cm := NewConnectionManager(
+		&Config{
+			Server:         "nats://localhost:4222",
+			ConnectionName: "orders-service",
+			Options:        []nats.Option{nats.PingInterval(5 * time.Second), nats.Name("custom-name")},
+		},
+		logging.NewMockLogger(logging.DEBUG),
+		mockNATSConnector,
+		mockJSCreator,
+	)
+
+	mockNATSConnector.EXPECT().
+		Connect("nats://localhost:4222", gomock.Any()).
+		DoAndReturn(func(_ string, opts ...nats.Option) (ConnInterface, error) {
+			natsOpts := nats.GetDefaultOptions()
+			for _, opt := range opts {
+				require.NoError(t, opt(&natsOpts))
+			}
+
+			assert.Equal(t, 5*time.Second, natsOpts.PingInterval)
+			// custom options override the ones derived from the config
+			assert.Equal(t, "custom-name", natsOpts.Name)
+
+			return mockConn, nil
+		})
+	mockJSCreator.EXPECT().New(mockConn).Return(NewMockJetStream(ctrl), nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	require.NoError(t, cm.Connect())
+}
+
+func TestConnectionManager_Connect_RetryOnInitialConnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+
+	cm := NewConnectionManager(
+		&Config{
+			Server:                "nats://localhost:4222",
+			RetryOnInitialConnect: true,
+			ReconnectWait:         time.Millisecond,
+		},
+		logging.NewMockLogger(logging.DEBUG),
+		mockNATSConnector,
+		mockJSCreator,
+	)
+
+	gomock.InOrder(
+		mockNATSConnector.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(nil, errConnectionError).Times(2),
+		mockNATSConnector.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(mockConn, nil),
+	)
+
+	mockJSCreator.EXPECT().New(mockConn).Return(mockJS, nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	out := testutil.StdoutOutputForFunc(func() {
+		cm.logger = logging.NewMockLogger(logging.DEBUG)
+
+		err := cm.Connect()
+		require.NoError(t, err)
+	})
+
+	assert.Equal(t, mockConn, cm.conn)
+	assert.Contains(t, out, "attempt 1")
+	assert.Contains(t, out, "attempt 2")
+}
+
+func TestConnectionManager_Connect_RetryExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+
+	cm := NewConnectionManager(
+		&Config{
+			Server:                "nats://localhost:4222",
+			RetryOnInitialConnect: true,
+			ReconnectWait:         time.Millisecond,
+			MaxReconnects:         2,
+		},
+		logging.NewMockLogger(logging.DEBUG),
+		mockNATSConnector,
+		NewMockJetStreamCreator(ctrl),
+	)
+
+	mockNATSConnector.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(nil, errConnectionError).Times(3)
+
+	err := cm.Connect()
+	require.ErrorIs(t, err, errConnectionError)
+	assert.Nil(t, cm.conn)
+}
+
+func TestAuthOptions(t *testing.T) {
+	credsFile := filepath.Join(t.TempDir(), "user.creds")
+	credsContent := "-----BEGIN NATS USER JWT-----\neyJ0eXAiOiJKV1QifQ.e30.c2ln\n------END NATS USER JWT------\n"
+
+	require.NoError(t, os.WriteFile(credsFile, []byte(credsContent), 0o600))
+
+	testCases := []struct {
+		desc   string
+		config *Config
+		check  func(t *testing.T, opts *nats.Options)
+	}{
+		{
+			desc:   "creds file",
+			config: &Config{CredsFile: credsFile},
+			check: func(t *testing.T, opts *nats.Options) {
+				t.Helper()
+				assert.NotNil(t, opts.UserJWT)
+				assert.NotNil(t, opts.SignatureCB)
+			},
+		},
+		{
+			desc:   "token",
+			config: &Config{Token: "secret-token"},
+			check: func(t *testing.T, opts *nats.Options) {
+				t.Helper()
+				assert.Equal(t, "secret-token", opts.Token)
+			},
+		},
+		{
+			desc:   "username and password",
+			config: &Config{Username: "user", Password: "pass"},
+			check: func(t *testing.T, opts *nats.Options) {
+				t.Helper()
+				assert.Equal(t, "user", opts.User)
+				assert.Equal(t, "pass", opts.Password)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			authOpts, err := authOptions(tc.config)
+			require.NoError(t, err)
+			require.Len(t, authOpts, 1)
+
+			natsOpts := nats.GetDefaultOptions()
+			require.NoError(t, authOpts[0](&natsOpts))
+
+			tc.check(t, &natsOpts)
+		})
+	}
+
+	authOpts, err := authOptions(&Config{})
+	require.NoError(t, err)
+	assert.Empty(t, authOpts)
+}
+
+func TestAuthOptions_NKeyFile(t *testing.T) {
+	dir := t.TempDir()
+
+	seedFile := filepath.Join(dir, "user.nk")
+	require.NoError(t, os.WriteFile(seedFile, []byte(testNKeySeed), 0o600))
+
+	authOpts, err := authOptions(&Config{NKeyFile: seedFile})
+	require.NoError(t, err)
+	require.Len(t, authOpts, 1)
+
+	natsOpts := nats.GetDefaultOptions()
+	require.NoError(t, authOpts[0](&natsOpts))
+	assert.NotEmpty(t, natsOpts.Nkey)
+	assert.NotNil(t, natsOpts.SignatureCB)
+
+	invalidFile := filepath.Join(dir, "invalid.nk")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a seed"), 0o600))
+
+	authOpts, err = authOptions(&Config{NKeyFile: invalidFile})
+	require.ErrorIs(t, err, errInvalidNKeySeed)
+	assert.Nil(t, authOpts)
+
+	_, err = authOptions(&Config{NKeyFile: filepath.Join(dir, "missing.nk")})
+	require.ErrorIs(t, err, errInvalidNKeySeed)
+	assert.Contains(t, err.Error(), "missing.nk")
+}
+
+func TestConnectionManager_connectionOptions_TLS(t *testing.T) {
+	cm := &ConnectionManager{config: &Config{Server: "nats://localhost:4222"}}
+
+	baseOpts, err := cm.connectionOptions()
+	require.NoError(t, err)
+
+	cm.config.TLS = &TLSConfig{
+		CertFile:           "client.crt",
+		KeyFile:            "client.key",
+		CAFile:             "ca.crt",
+		InsecureSkipVerify: true,
+	}
+
+	opts, err := cm.connectionOptions()
+	require.NoError(t, err)
+	assert.Len(t, opts, len(baseOpts)+3)
+
+	cm.config.TLS = &TLSConfig{InsecureSkipVerify: true}
+
+	opts, err = cm.connectionOptions()
+	require.NoError(t, err)
+	require.Len(t, opts, len(baseOpts)+1)
+
+	natsOpts := nats.GetDefaultOptions()
+	for _, opt := range opts {
+		require.NoError(t, opt(&natsOpts))
+	}
+
+	assert.True(t, natsOpts.Secure)
+	assert.True(t, natsOpts.TLSConfig.InsecureSkipVerify)
+}
+
+func TestConnectionManager_connectionOptions_Name(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *Config
+		appName  string
+		expected string
+	}{
+		{desc: "connection name", config: &Config{ConnectionName: "orders-service"}, appName: "gofr-app", expected: "orders-service"},
+		{desc: "app name", config: &Config{}, appName: "billing-service", expected: "billing-service"},
+		{desc: "default", config: &Config{}, expected: "GoFr NATS JetStreamClient"},
+	}
+
+	for i, tc := range testCases {
+		t.Setenv("APP_NAME", tc.appName)
+
+		cm := &ConnectionManager{config: tc.config}
+
+		opts, err := cm.connectionOptions()
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		natsOpts := nats.GetDefaultOptions()
+		for _, opt := range opts {
+			require.NoError(t, opt(&natsOpts), "TEST[%d], Failed.\n%s", i, tc.desc)
+		}
+
+		assert.Equal(t, tc.expected, natsOpts.Name, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestConnectionManager_eventHandlerOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	cm := &ConnectionManager{
+		config:  &Config{Server: "nats://localhost:4222"},
+		logger:  logging.NewMockLogger(logging.DEBUG),
+		metrics: mockMetrics,
+	}
+
+	opts, err := cm.connectionOptions()
+	require.NoError(t, err)
+
+	natsOpts := nats.GetDefaultOptions()
+	for _, opt := range opts {
+		require.NoError(t, opt(&natsOpts))
+	}
+
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_nats_disconnect_total_count", "server", "nats://localhost:4222")
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_nats_reconnect_total_count", "server", "nats://localhost:4222")
+
+	logs := testutil.StdoutOutputForFunc(func() {
+		cm.logger = logging.NewMockLogger(logging.DEBUG)
+
+		natsOpts.DisconnectedErrCB(&nats.Conn{}, errConnectionError)
+		natsOpts.ReconnectedCB(&nats.Conn{})
+		natsOpts.ClosedCB(&nats.Conn{})
+	})
+
+	assert.Contains(t, logs, "WARN: disconnected from NATS server at nats://localhost:4222: connection error")
+	assert.Contains(t, logs, "WARN: reconnected to NATS server")
+	assert.Contains(t, logs, "WARN: connection to NATS server at nats://localhost:4222 closed")
+}
+
+func TestConnectionManager_Status(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := &ConnectionManager{}
+	assert.Equal(t, nats.DISCONNECTED, cm.Status())
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockConn.EXPECT().Status().Return(nats.RECONNECTING)
+
+	cm.conn = mockConn
+	assert.Equal(t, nats.RECONNECTING, cm.Status())
+}
+
+func TestConnectionManager_Flush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := &ConnectionManager{logger: logging.NewMockLogger(logging.DEBUG)}
+	require.ErrorIs(t, cm.Flush(context.Background()), errConnectionNotEstablished)
+
+	mockConn := NewMockConnInterface(ctrl)
+	cm.conn = mockConn
+
+	// contexts without a deadline are given the default flush timeout
+	mockConn.EXPECT().FlushWithContext(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(defaultFlushTimeout), deadline, time.Second)
+
+		return nil
+	})
+
+	require.NoError(t, cm.Flush(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	mockConn.EXPECT().FlushWithContext(ctx).Return(nats.ErrTimeout)
+
+	require.ErrorIs(t, cm.Flush(ctx), nats.ErrTimeout)
+}
+
+func TestConnectionManager_RTT(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	cm := &ConnectionManager{
+		conn:    mockConn,
+		config:  &Config{Server: "nats://localhost:4222"},
+		logger:  logging.NewMockLogger(logging.DEBUG),
+		metrics: mockMetrics,
+	}
+
+	mockConn.EXPECT().RTT().Return(25*time.Millisecond, nil)
+	mockMetrics.EXPECT().SetGauge("app_nats_rtt_seconds", 0.025, "server", "nats://localhost:4222")
+
+	rtt, err := cm.RTT(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 25*time.Millisecond, rtt)
+
+	mockConn.EXPECT().RTT().Return(time.Duration(0), nats.ErrConnectionClosed)
+
+	_, err = cm.RTT(context.Background())
+	require.ErrorIs(t, err, nats.ErrConnectionClosed)
+}
+
+func TestConnectionManager_RTT_ContextDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	cm := &ConnectionManager{conn: mockConn, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	_, err := (&ConnectionManager{}).RTT(context.Background())
+	require.ErrorIs(t, err, errConnectionNotEstablished)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mockConn.EXPECT().RTT().DoAndReturn(func() (time.Duration, error) {
+		<-release
+
+		return time.Second, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = cm.RTT(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestConnectionManager_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	cm := &ConnectionManager{
+		conn: mockConn,
+	}
+
+	mockConn.EXPECT().Close()
+
+	ctx := context.Background()
+	err := cm.Close(ctx)
+	require.NoError(t, err)
+}
+
+func TestConnectionManager_Close_Drain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		config: &Config{DrainTimeout: time.Second},
+	}
+
+	mockConn.EXPECT().Drain().Return(nil)
+
+	err := cm.Close(context.Background())
+	require.NoError(t, err)
+}
+
+func TestConnectionManager_Close_DrainTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		config: &Config{DrainTimeout: 10 * time.Millisecond},
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mockConn.EXPECT().Drain().DoAndReturn(func() error {
+		<-release
+		return nil
+	})
+	mockConn.EXPECT().Close()
+
+	err := cm.Close(context.Background())
+	require.ErrorIs(t, err, errDrainTimeout)
+}
+
+func TestConnectionManager_Drain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+
+	// the connection is drained even without a drain timeout
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		config: &Config{},
+	}
+
+	mockConn.EXPECT().Drain().Return(nil)
+
+	require.NoError(t, cm.Drain(context.Background()))
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mockConn.EXPECT().Drain().DoAndReturn(func() error {
+		<-release
+		return nil
+	})
+	mockConn.EXPECT().Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, cm.Drain(ctx), context.DeadlineExceeded)
+}
+
+func TestConnectionManager_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	subject := "test.subject"
+	message := []byte("test message")
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
+	mockJS.EXPECT().Publish(ctx, subject, message).Return(&jetstream.PubAck{}, nil)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any(), "stream", "").Times(1)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", float64(len(message)), "stream", "", "direction", "publish")
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
+
+	err := cm.Publish(ctx, subject, message, mockMetrics)
+	require.NoError(t, err)
+}
+
+func TestConnectionManager_Publish_RoutesToStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+		config: &Config{
+			Streams: []StreamConfig{
+				{Stream: "orders", Subjects: []string{"orders.*"}},
+				{Stream: "shipments", Subjects: []string{"shipments.*"}},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	message := []byte("test message")
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, gomock.Any(), "subject", gomock.Any()).AnyTimes()
+
+	// a subject owned by a configured stream is published with the expected stream option
+	mockJS.EXPECT().Publish(ctx, "shipments.created", message, gomock.Len(1)).Return(&jetstream.PubAck{Stream: "shipments"}, nil)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any(), "stream", "shipments")
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", gomock.Any(), "stream", "shipments", "direction", "publish")
+
+	err := cm.Publish(ctx, "shipments.created", message, mockMetrics)
+	require.NoError(t, err)
+
+	// subjects outside every configured stream are published as is
+	mockJS.EXPECT().Publish(ctx, "other.created", message).Return(&jetstream.PubAck{}, nil)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any(), "stream", "")
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", gomock.Any(), "stream", "", "direction", "publish")
+
+	err = cm.Publish(ctx, "other.created", message, mockMetrics)
+	require.NoError(t, err)
+}
+
+func TestConnectionManager_Publish_NoStreamForSubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "unknown.subject").Times(2)
+	mockJS.EXPECT().Publish(ctx, "unknown.subject", []byte("message")).Return(nil, jetstream.ErrNoStreamResponse).Times(2)
+
+	gomock.InOrder(
+		mockJS.EXPECT().StreamNameBySubject(ctx, "unknown.subject").Return("", jetstream.ErrStreamNotFound),
+		// a stream that exists but did not respond keeps the original error
+		mockJS.EXPECT().StreamNameBySubject(ctx, "unknown.subject").Return("orders", nil),
+	)
+
+	err := cm.Publish(ctx, "unknown.subject", []byte("message"), mockMetrics)
+	require.ErrorIs(t, err, errNoStreamForSubject)
+	assert.Equal(t, "no stream captures subject: unknown.subject", err.Error())
+
+	err = cm.Publish(ctx, "unknown.subject", []byte("message"), mockMetrics)
+	require.ErrorIs(t, err, jetstream.ErrNoStreamResponse)
+}
+
+func TestConnectionManager_Publish_StreamLookupTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "orders.created")
+	mockJS.EXPECT().Publish(ctx, "orders.created", []byte("message")).Return(nil, jetstream.ErrNoStreamResponse)
+	// a lookup that times out does not confirm that no stream captures the subject
+	mockJS.EXPECT().StreamNameBySubject(ctx, "orders.created").Return("", context.DeadlineExceeded)
+
+	err := cm.Publish(ctx, "orders.created", []byte("message"), mockMetrics)
+	require.ErrorIs(t, err, jetstream.ErrNoStreamResponse)
+	require.NotErrorIs(t, err, errNoStreamForSubject)
+}
+
+func TestConnectionManager_Publish_StreamNameCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream:     mockJS,
+		logger:      logging.NewMockLogger(logging.DEBUG),
+		streamNames: newStreamNameCache(time.Minute, nil),
+	}
+
+	ctx := context.Background()
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "unknown.subject").Times(2)
+	mockJS.EXPECT().Publish(ctx, "unknown.subject", []byte("message")).Return(nil, jetstream.ErrNoStreamResponse).Times(2)
+	// the second publish within the TTL is answered from the cache
+	mockJS.EXPECT().StreamNameBySubject(ctx, "unknown.subject").Return("", jetstream.ErrStreamNotFound)
+
+	for range 2 {
+		err := cm.Publish(ctx, "unknown.subject", []byte("message"), mockMetrics)
+		require.ErrorIs(t, err, errNoStreamForSubject)
+	}
+}
+
+func TestConnectionManager_Publish_StreamLabelCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream:     mockJS,
+		config:      &Config{},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+		streamNames: newStreamNameCache(time.Minute, nil),
+	}
+
+	ctx := context.Background()
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "orders.created").Times(2)
+	mockJS.EXPECT().Publish(ctx, "orders.created", []byte("message")).Return(&jetstream.PubAck{}, nil).Times(2)
+	// the stream of a subject outside the configured streams is looked up once within the TTL
+	mockJS.EXPECT().StreamNameBySubject(ctx, "orders.created").Return("orders", nil)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any(), "stream", "orders").Times(2)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", gomock.Any(), "stream", "orders",
+		"direction", "publish").Times(2)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", "orders.created").Times(2)
+
+	for range 2 {
+		require.NoError(t, cm.Publish(ctx, "orders.created", []byte("message"), mockMetrics))
+	}
+}
+
+func TestConnectionManager_Publish_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		config:  &Config{PublishRetries: 3, PublishRetryBackoff: time.Millisecond},
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	message := []byte("message")
+
+	gomock.InOrder(
+		mockJS.EXPECT().Publish(ctx, "test.subject", message).Return(nil, nats.ErrNoResponders),
+		mockJS.EXPECT().Publish(ctx, "test.subject", message).Return(nil, nats.ErrTimeout),
+		mockJS.EXPECT().Publish(ctx, "test.subject", message).Return(&jetstream.PubAck{}, nil),
+	)
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "test.subject")
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_retry_count", "subject", "test.subject").Times(2)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", "test.subject")
+	mockMetrics.EXPECT().RecordHistogram(ctx, gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	err := cm.Publish(ctx, "test.subject", message, mockMetrics)
+	require.NoError(t, err)
+}
+
+func TestConnectionManager_Publish_RetryExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		config:  &Config{PublishRetries: 1, PublishRetryBackoff: time.Millisecond},
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "test.subject").Times(2)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_retry_count", "subject", "test.subject")
+	mockJS.EXPECT().Publish(ctx, "test.subject", []byte("message")).Return(nil, nats.ErrTimeout).Times(2)
+
+	err := cm.Publish(ctx, "test.subject", []byte("message"), mockMetrics)
+	require.ErrorIs(t, err, nats.ErrTimeout)
+
+	// errors other than transient ones are not retried
+	mockJS.EXPECT().Publish(ctx, "test.subject", []byte("message")).Return(nil, errPublishError)
+
+	err = cm.Publish(ctx, "test.subject", []byte("message"), mockMetrics)
+	require.ErrorIs(t, err, errPublishError)
+}
+
+func TestConnectionManager_Publish_CoreFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConn := NewMockConnInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		conn:    mockConn,
+		jStream: mockJS,
+		config:  &Config{FallbackToCoreNATS: true},
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	message := []byte("message")
+
+	// without a stream responding, the lookup of the subject reveals that JetStream is disabled
+	mockJS.EXPECT().Publish(ctx, "test.subject", message).Return(nil, jetstream.ErrNoStreamResponse)
+	mockJS.EXPECT().StreamNameBySubject(ctx, "test.subject").Return("", jetstream.ErrJetStreamNotEnabled)
+	mockJS.EXPECT().PublishMsg(ctx, gomock.Any()).Return(nil, jetstream.ErrJetStreamNotEnabledForAccount)
+
+	mockConn.EXPECT().PublishMsg(&nats.Msg{Subject: "test.subject", Data: message}).Return(nil)
+	mockConn.EXPECT().PublishMsg(&nats.Msg{Subject: "test.subject", Data: message,
+		Header: nats.Header{"Content-Type": []string{"text/plain"}}}).Return(nil)
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "test.subject").Times(2)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_core_fallback_count", "subject", "test.subject").Times(2)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", "test.subject").Times(2)
+
+	var err error
+
+	out := testutil.StdoutOutputForFunc(func() {
+		cm.logger = logging.NewMockLogger(logging.DEBUG)
+
+		err = cm.Publish(ctx, "test.subject", message, mockMetrics)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "WARN: JetStream is unavailable, publishing to subject test.subject over core NATS")
+
+	err = cm.PublishWithHeaders(ctx, "test.subject", message, nats.Header{"Content-Type": []string{"text/plain"}}, mockMetrics)
+	require.NoError(t, err)
+}
+
+func TestConnectionManager_Publish_CoreFallbackDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		conn:    NewMockConnInterface(ctrl),
+		jStream: mockJS,
+		config:  &Config{},
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().Publish(ctx, "test.subject", []byte("message")).Return(nil, jetstream.ErrJetStreamNotEnabled)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "test.subject")
+
+	err := cm.Publish(ctx, "test.subject", []byte("message"), mockMetrics)
+	require.ErrorIs(t, err, jetstream.ErrJetStreamNotEnabled)
+}
 
-	assert.NotNil(t, cm)
-	assert.Equal(t, cfg, cm.config)
-	assert.Equal(t, logger, cm.logger)
-	assert.Equal(t, natsConnector, cm.natsConnector)
-	assert.Equal(t, jsCreator, cm.jetStreamCreator)
+func TestConnectionManager_Publish_PayloadTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream:    mockJS,
+		maxPayload: 16,
+		logger:     logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	message := make([]byte, 17)
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", "test.subject").Times(3)
+
+	// the message is rejected without reaching the server
+	err := cm.Publish(ctx, "test.subject", message, mockMetrics)
+	require.ErrorIs(t, err, errPayloadTooLarge)
+	assert.Equal(t, "message payload too large: 17 bytes exceeds the server limit of 16 bytes", err.Error())
+
+	err = cm.PublishWithHeaders(ctx, "test.subject", message, nats.Header{"key": []string{"value"}}, mockMetrics)
+	require.ErrorIs(t, err, errPayloadTooLarge)
+
+	_, err = cm.PublishAsync(ctx, "test.subject", message, nil, mockMetrics)
+	require.ErrorIs(t, err, errPayloadTooLarge)
 }
 
-func TestConnectionManager_Connect(t *testing.T) {
+func TestConnectionManager_PublishWithHeaders(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockConn := NewMockConnInterface(ctrl)
 	mockJS := NewMockJetStream(ctrl)
-	mockNATSConnector := NewMockNATSConnector(ctrl)
-	mockJSCreator := NewMockJetStreamCreator(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
 
-	cm := NewConnectionManager(
-		&Config{Server: "nats://localhost:4222"},
-		logging.NewMockLogger(logging.DEBUG),
-		mockNATSConnector,
-		mockJSCreator,
-	)
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
 
-	mockNATSConnector.EXPECT().
-		Connect(gomock.Any(), gomock.Any()).
-		Return(mockConn, nil)
+	ctx := context.Background()
+	subject := "test.subject"
+	message := []byte("test message")
+	headers := nats.Header{"Content-Type": []string{"application/json"}}
 
-	// We don't need to expect NATSConn() call anymore, as we're passing mockConn directly to New()
-	mockJSCreator.EXPECT().
-		New(mockConn).
-		Return(mockJS, nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject).Times(2)
+	mockJS.EXPECT().PublishMsg(ctx, &nats.Msg{Subject: subject, Data: message, Header: headers}).Return(&jetstream.PubAck{}, nil)
+	mockJS.EXPECT().Publish(ctx, subject, message).Return(&jetstream.PubAck{}, nil)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any(), "stream", "").Times(2)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", float64(len(message)), "stream", "", "direction", "publish").
+		Times(2)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject).Times(2)
 
-	err := cm.Connect()
+	out := testutil.StdoutOutputForFunc(func() {
+		cm.logger = logging.NewMockLogger(logging.DEBUG)
+
+		err := cm.PublishWithHeaders(ctx, subject, message, headers, mockMetrics)
+		require.NoError(t, err)
+	})
+
+	assert.Contains(t, out, "[Content-Type]")
+
+	// empty headers fall back to a plain publish
+	err := cm.PublishWithHeaders(ctx, subject, message, nats.Header{}, mockMetrics)
 	require.NoError(t, err)
-	assert.Equal(t, mockConn, cm.conn)
-	assert.Equal(t, mockJS, cm.jStream)
 }
 
-func TestConnectionManager_Close(t *testing.T) {
+func TestConnectionManager_PublishAck(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockConn := NewMockConnInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
 	cm := &ConnectionManager{
-		conn: mockConn,
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
 	}
 
-	mockConn.EXPECT().Close()
+	ctx := context.Background()
+	subject := "test.subject"
+	message := []byte("test message")
+	expected := &jetstream.PubAck{Stream: "test-stream", Sequence: 7, Duplicate: true}
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
+	mockJS.EXPECT().PublishMsg(ctx, &nats.Msg{Subject: subject, Data: message}).Return(expected, nil)
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any(), "stream", "")
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", float64(len(message)), "stream", "", "direction", "publish")
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
+
+	ack, err := cm.PublishAck(ctx, subject, message, nil, mockMetrics)
+	require.NoError(t, err)
+	assert.Equal(t, expected, ack)
+}
+
+func TestConnectionManager_PublishAsync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockFuture := NewMockPubAckFuture(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
 
 	ctx := context.Background()
-	cm.Close(ctx)
+	subject := "test.subject"
+	message := []byte("test message")
+
+	okChan := make(chan *jetstream.PubAck, 1)
+	okChan <- &jetstream.PubAck{}
+
+	acked := make(chan struct{})
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
+	mockJS.EXPECT().PublishAsync(subject, message, gomock.Any()).Return(mockFuture, nil)
+	mockFuture.EXPECT().Ok().Return(okChan)
+	mockFuture.EXPECT().Err().Return(make(chan error))
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject).
+		Do(func(context.Context, string, ...string) { close(acked) })
+
+	future, err := cm.PublishAsync(ctx, subject, message, nil, mockMetrics)
+	require.NoError(t, err)
+	assert.Equal(t, mockFuture, future)
+
+	select {
+	case <-acked:
+	case <-time.After(time.Second):
+		t.Fatal("success counter was not incremented after ack")
+	}
 }
 
-func TestConnectionManager_Publish(t *testing.T) {
+func TestConnectionManager_PublishBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	subject := "test.subject"
+	msgs := [][]byte{[]byte("first"), []byte("second"), []byte("third")}
+
+	for i, msg := range msgs {
+		mockFuture := NewMockPubAckFuture(ctrl)
+		okChan := make(chan *jetstream.PubAck, 1)
+		errChan := make(chan error, 1)
+
+		// the second message is rejected by the server
+		if i == 1 {
+			errChan <- errPublishError
+		} else {
+			okChan <- &jetstream.PubAck{}
+		}
+
+		mockJS.EXPECT().PublishAsync(subject, msg, gomock.Any()).Return(mockFuture, nil)
+		mockFuture.EXPECT().Ok().Return(okChan)
+		mockFuture.EXPECT().Err().Return(errChan)
+	}
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject).Times(3)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject).Times(2)
+
+	err := cm.PublishBatch(ctx, subject, msgs, mockMetrics)
+	require.ErrorIs(t, err, errBatchPublishFailed)
+	require.ErrorIs(t, err, errPublishError)
+	assert.Equal(t, "failed to publish message at index 1: publish error", err.Error())
+}
+
+func TestConnectionManager_PublishBatch_NotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	cm := &ConnectionManager{logger: logging.NewMockLogger(logging.DEBUG)}
+
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_publish_total_count", "subject", "test.subject").Times(2)
+
+	err := cm.PublishBatch(context.Background(), "test.subject", [][]byte{[]byte("first"), []byte("second")}, mockMetrics)
+	require.ErrorIs(t, err, errJetStreamNotConfigured)
+	assert.Contains(t, err.Error(), "at index 0")
+	assert.Contains(t, err.Error(), "at index 1")
+}
+
+func TestConnectionManager_PublishAsync_AckError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockJS := NewMockJetStream(ctrl)
 	mockMetrics := NewMockMetrics(ctrl)
+	mockFuture := NewMockPubAckFuture(ctrl)
 
 	cm := &ConnectionManager{
 		jStream: mockJS,
@@ -92,12 +1117,200 @@ func TestConnectionManager_Publish(t *testing.T) {
 	subject := "test.subject"
 	message := []byte("test message")
 
+	errChan := make(chan error, 1)
+	errChan <- errPublishError
+
+	done := make(chan struct{})
+
 	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
-	mockJS.EXPECT().Publish(ctx, subject, message).Return(&jetstream.PubAck{}, nil)
-	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_success_count", "subject", subject)
+	mockJS.EXPECT().PublishAsync(subject, message, gomock.Any()).Return(mockFuture, nil)
+	mockFuture.EXPECT().Ok().Return(make(chan *jetstream.PubAck))
+	mockFuture.EXPECT().Err().DoAndReturn(func() <-chan error {
+		defer close(done)
+		return errChan
+	})
 
-	err := cm.Publish(ctx, subject, message, mockMetrics)
+	_, err := cm.PublishAsync(ctx, subject, message, nil, mockMetrics)
+	require.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ack error was not observed")
+	}
+}
+
+func TestConnectionManager_PublishAsync_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockFuture := NewMockPubAckFuture(ctrl)
+
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		logger: logging.NewMockLogger(logging.DEBUG),
+	}
+
+	// the acknowledgement never arrives
+	mockFuture.EXPECT().Ok().Return(make(chan *jetstream.PubAck))
+	mockFuture.EXPECT().Err().Return(make(chan error))
+	mockConn.EXPECT().Close()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		cm.trackPubAck(context.Background(), "test.subject", mockFuture, NewMockMetrics(ctrl))
+	}()
+
+	require.NoError(t, cm.Close(context.Background()))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the pending acknowledgement was still tracked after Close")
+	}
+}
+
+func TestConnectionManager_PublishAsync_StalledContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	subject := "test.subject"
+	message := []byte("test message")
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_publish_total_count", "subject", subject)
+	mockJS.EXPECT().PublishAsync(subject, message, gomock.Any()).
+		DoAndReturn(func(string, []byte, ...jetstream.PublishOpt) (jetstream.PubAckFuture, error) {
+			cancel()
+			return nil, jetstream.ErrTooManyStalledMsgs
+		})
+
+	future, err := cm.PublishAsync(ctx, subject, message, nil, mockMetrics)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, future)
+}
+
+func TestConnectionManager_PublishAsyncComplete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	cm := &ConnectionManager{
+		jStream: mockJS,
+		logger:  logging.NewMockLogger(logging.DEBUG),
+	}
+
+	doneChan := make(chan struct{})
+	close(doneChan)
+
+	mockJS.EXPECT().PublishAsyncComplete().Return(doneChan)
+
+	err := cm.PublishAsyncComplete(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	mockJS.EXPECT().PublishAsyncComplete().Return(make(chan struct{}))
+
+	err = cm.PublishAsyncComplete(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	cm.jStream = nil
+	err = cm.PublishAsyncComplete(context.Background())
+	assert.Equal(t, errJetStreamNotConfigured, err)
+}
+
+func TestConnectionManager_Request(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		logger: logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	subject := "test.rpc"
+	data := []byte("ping")
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_request_total_count", "subject", subject)
+	mockConn.EXPECT().RequestWithContext(ctx, subject, data).Return(&nats.Msg{Data: []byte("pong")}, nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_request_success_count", "subject", subject)
+
+	msg, err := cm.Request(ctx, subject, data, mockMetrics)
+	require.NoError(t, err)
+	assert.Equal(t, subject, msg.Topic)
+	assert.Equal(t, []byte("pong"), msg.Value)
+}
+
+func TestConnectionManager_RequestWithHeaders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		logger: logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	headers := nats.Header{"Authorization": []string{"Bearer secret"}}
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_request_total_count", "subject", "test.rpc")
+	mockConn.EXPECT().RequestMsgWithContext(ctx, &nats.Msg{Subject: "test.rpc", Data: []byte("ping"), Header: headers}).
+		Return(&nats.Msg{Data: []byte("pong"), Header: nats.Header{"Reply-Id": []string{"1"}}}, nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_request_success_count", "subject", "test.rpc")
+
+	msg, err := cm.RequestWithHeaders(ctx, "test.rpc", []byte("ping"), headers, mockMetrics)
 	require.NoError(t, err)
+	assert.Equal(t, []byte("pong"), msg.Value)
+	assert.Equal(t, nats.Header{"Reply-Id": []string{"1"}}, msg.MetaData)
+}
+
+func TestConnectionManager_Request_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockConnInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	cm := &ConnectionManager{
+		conn:   mockConn,
+		logger: logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	subject := "test.rpc"
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_request_total_count", "subject", subject).Times(2)
+	mockConn.EXPECT().RequestWithContext(ctx, subject, nil).Return(nil, context.DeadlineExceeded)
+
+	msg, err := cm.Request(ctx, subject, nil, mockMetrics)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, msg)
+
+	cm.conn = nil
+	msg, err = cm.Request(ctx, subject, nil, mockMetrics)
+	assert.Equal(t, errConnectionNotEstablished, err)
+	assert.Nil(t, msg)
 }
 
 func TestConnectionManager_validateJetStream(t *testing.T) {
@@ -131,16 +1344,19 @@ func TestConnectionManager_Health(t *testing.T) {
 	}
 
 	mockConn.EXPECT().Status().Return(nats.CONNECTED)
+	mockConn.EXPECT().ConnectedClusterName().Return("test-cluster")
 
 	health := cm.Health()
 	assert.Equal(t, datasource.StatusUp, health.Status)
 	assert.Equal(t, "nats://localhost:4222", health.Details["server"])
+	assert.Equal(t, "test-cluster", health.Details["cluster"])
 
 	mockConn.EXPECT().Status().Return(nats.CLOSED)
 
 	health = cm.Health()
 	assert.Equal(t, datasource.StatusDown, health.Status)
 	assert.Equal(t, "nats://localhost:4222", health.Details["server"])
+	assert.Equal(t, nats.CLOSED.String(), health.Details["connection_status"])
 
 	cm.conn = nil
 	health = cm.Health()
@@ -201,6 +1417,20 @@ func TestNatsConnWrapper_Close(t *testing.T) {
 	assert.Equal(t, nats.CLOSED, wrapper.Status(), "Final status should be CLOSED")
 }
 
+func TestNatsConnWrapper_Drain(t *testing.T) {
+	ns, url := startNATSServer(t)
+	defer ns.Shutdown()
+
+	nc, err := nats.Connect(url)
+	require.NoError(t, err, "Failed to connect to NATS")
+
+	wrapper := &natsConnWrapper{conn: nc}
+
+	err = wrapper.Drain()
+	require.NoError(t, err)
+	assert.Equal(t, nats.CLOSED, wrapper.Status())
+}
+
 // startNATSServer starts a NATS server and returns the server instance and the client URL.
 func startNATSServer(t *testing.T) (s *server.Server, u string) {
 	t.Helper()
@@ -230,3 +1460,27 @@ func TestNatsConnWrapper_NatsConn(t *testing.T) {
 
 	assert.Equal(t, mockConn, wrapper.NATSConn())
 }
+
+func TestNatsConnWrapper_RequestWithContext(t *testing.T) {
+	ns, url := startNATSServer(t)
+	defer ns.Shutdown()
+
+	nc, err := nats.Connect(url)
+	require.NoError(t, err, "Failed to connect to NATS")
+
+	defer nc.Close()
+
+	_, err = nc.Subscribe("test.rpc", func(m *nats.Msg) {
+		_ = m.Respond([]byte("pong"))
+	})
+	require.NoError(t, err)
+
+	wrapper := &natsConnWrapper{conn: nc}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	reply, err := wrapper.RequestWithContext(ctx, "test.rpc", []byte("ping"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("pong"), reply.Data)
+}
@@ -17,8 +17,20 @@ func (*defaultConnector) Connect(serverURL string, opts ...nats.Option) (ConnInt
 	return &natsConnWrapper{nc}, nil
 }
 
-type DefaultJetStreamCreator struct{}
+// DefaultJetStreamCreator creates the jStream context of a connection. Domain or APIPrefix, when set,
+// select the JetStream domain or the API prefix jStream requests are sent to.
+type DefaultJetStreamCreator struct {
+	Domain    string
+	APIPrefix string
+}
 
-func (*DefaultJetStreamCreator) New(conn ConnInterface) (jetstream.JetStream, error) {
-	return conn.JetStream()
+func (d *DefaultJetStreamCreator) New(conn ConnInterface) (jetstream.JetStream, error) {
+	switch {
+	case d.Domain != "":
+		return jetstream.NewWithDomain(conn.NATSConn(), d.Domain)
+	case d.APIPrefix != "":
+		return jetstream.NewWithAPIPrefix(conn.NATSConn(), d.APIPrefix)
+	default:
+		return conn.JetStream()
+	}
 }
@@ -1,9 +1,13 @@
 package nats
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/nats-io/nats-server/v2/server"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -72,3 +76,47 @@ func TestDefaultJetStreamCreator_New(t *testing.T) {
 		assert.Equal(t, expectedError, err)
 	})
 }
+
+func TestDefaultJetStreamCreator_New_Domain(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{
+		Host:            "127.0.0.1",
+		Port:            -1,
+		JetStream:       true,
+		JetStreamDomain: "hub",
+		StoreDir:        t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	go ns.Start()
+	defer ns.Shutdown()
+
+	require.True(t, ns.ReadyForConnections(10*time.Second))
+
+	nc, err := nats.Connect(ns.ClientURL())
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	testCases := []struct {
+		desc    string
+		creator *DefaultJetStreamCreator
+		err     error
+	}{
+		{desc: "matching domain", creator: &DefaultJetStreamCreator{Domain: "hub"}},
+		{desc: "matching API prefix", creator: &DefaultJetStreamCreator{APIPrefix: "$JS.hub.API"}},
+		{desc: "other domain", creator: &DefaultJetStreamCreator{Domain: "leaf"}, err: jetstream.ErrJetStreamNotEnabled},
+	}
+
+	for i, tc := range testCases {
+		js, err := tc.creator.New(&natsConnWrapper{conn: nc})
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		// requests only reach the server's JetStream through its domain
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err = js.AccountInfo(ctx)
+
+		cancel()
+
+		require.ErrorIs(t, err, tc.err, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,39 @@
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// reportConsumerLag logs the number of messages pending for the consumer and sets the
+// app_pubsub_consumer_pending gauge every interval, as measured by clk, until ctx is done.
+func reportConsumerLag(
+	ctx context.Context,
+	cons jetstream.Consumer,
+	clk clock,
+	interval time.Duration,
+	cfg *Config,
+	logger pubsub.Logger,
+	metrics Metrics) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-clk.After(interval):
+			info, err := cons.Info(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Errorf("failed to get consumer info for lag report: %v", err)
+				}
+
+				continue
+			}
+
+			logger.Logf("consumer %s on stream %s has %d pending messages", info.Name, info.Stream, info.NumPending)
+			metrics.SetGauge(consumerPendingMetric, float64(info.NumPending), streamLabels(cfg, info.Stream, "consumer", info.Name)...)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestReportConsumerLag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clk := newFakeClock()
+	done := make(chan struct{})
+	reported := make(chan struct{})
+
+	gomock.InOrder(
+		mockConsumer.EXPECT().Info(ctx).Return(nil, errJetStream),
+		mockConsumer.EXPECT().Info(ctx).Return(&jetstream.ConsumerInfo{Stream: "orders", Name: "billing", NumPending: 42}, nil),
+	)
+	mockMetrics.EXPECT().SetGauge("app_pubsub_consumer_pending", float64(42), "stream", "orders", "consumer", "billing").
+		Do(func(string, float64, ...string) { close(reported) })
+
+	go func() {
+		reportConsumerLag(ctx, mockConsumer, clk, time.Minute, &Config{}, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+		close(done)
+	}()
+
+	// a failed lookup is skipped until the next interval
+	for range 2 {
+		require.Eventually(t, func() bool { return clk.pending() == 1 }, time.Second, time.Millisecond)
+		clk.Advance(time.Minute)
+	}
+
+	select {
+	case <-reported:
+	case <-time.After(time.Second):
+		t.Fatal("pending gauge was not set")
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lag reporter did not stop after the context was canceled")
+	}
+}
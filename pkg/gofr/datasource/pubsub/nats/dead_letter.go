@@ -0,0 +1,88 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// originalSubjectHeader holds the subject a dead-lettered message was originally delivered on.
+const originalSubjectHeader = "X-Original-Subject"
+
+// deadLetterQueue republishes messages that reached the maximum number of deliveries to the
+// configured DLQ subject. As the server stops redelivering after the last attempt, a message is
+// moved on its MaxDeliver-th delivery, which leaves the handler MaxDeliver-1 attempts.
+type deadLetterQueue struct {
+	js         jetstream.JetStream
+	subject    string
+	maxDeliver int
+	logger     pubsub.Logger
+	metrics    Metrics
+}
+
+// newDeadLetterQueue returns nil unless both a DLQ subject and a positive MaxDeliver are configured.
+func newDeadLetterQueue(js jetstream.JetStream, cfg *Config, logger pubsub.Logger, metrics Metrics) *deadLetterQueue {
+	maxDeliver := consumerMaxDeliver(cfg)
+	if cfg.DLQSubject == "" || maxDeliver <= 0 {
+		return nil
+	}
+
+	return &deadLetterQueue{js: js, subject: prefixSubject(cfg, cfg.DLQSubject), maxDeliver: maxDeliver, logger: logger, metrics: metrics}
+}
+
+// handle moves msg to the DLQ if it has exhausted its deliveries and reports whether it did so.
+// Messages that fail to be republished are left for the regular delivery path.
+func (d *deadLetterQueue) handle(ctx context.Context, msg jetstream.Msg) bool {
+	if d == nil {
+		return false
+	}
+
+	meta, err := msg.Metadata()
+	if err != nil || meta.NumDelivered < uint64(d.maxDeliver) {
+		return false
+	}
+
+	headers := make(nats.Header, len(msg.Headers())+1)
+	for key, values := range msg.Headers() {
+		headers[key] = values
+	}
+
+	headers.Set(originalSubjectHeader, msg.Subject())
+
+	if _, err := d.js.PublishMsg(ctx, &nats.Msg{Subject: d.subject, Data: msg.Data(), Header: headers}); err != nil {
+		d.logger.Errorf("failed to move message from %s to dead letter queue %s: %v", msg.Subject(), d.subject, err)
+
+		return false
+	}
+
+	if err := msg.Term(); err != nil {
+		d.logger.Errorf("failed to terminate dead-lettered message on %s: %v", msg.Subject(), err)
+	}
+
+	d.metrics.IncrementCounter(ctx, dlqCountMetric, "subject", msg.Subject(), "dlq_subject", d.subject)
+	d.logger.Logf("moved message from %s to dead letter queue %s after %d deliveries", msg.Subject(), d.subject, meta.NumDelivered)
+
+	return true
+}
+
+// reportPoisonSuspect logs an error and increments app_pubsub_poison_suspect_count when msg is
+// delivered for the PoisonAttemptThreshold-th time, which happens once per message.
+func reportPoisonSuspect(ctx context.Context, msg jetstream.Msg, cfg *Config, logger pubsub.Logger, metrics Metrics) {
+	if cfg.PoisonAttemptThreshold <= 0 {
+		return
+	}
+
+	meta, err := msg.Metadata()
+	if err != nil || meta.NumDelivered != uint64(cfg.PoisonAttemptThreshold) {
+		return
+	}
+
+	logger.Errorf("message %d of stream %s on %s is likely poison: delivered %d times", meta.Sequence.Stream,
+		meta.Stream, msg.Subject(), meta.NumDelivered)
+
+	if metrics != nil {
+		metrics.IncrementCounter(ctx, poisonSuspectCountMetric, "subject", msg.Subject())
+	}
+}
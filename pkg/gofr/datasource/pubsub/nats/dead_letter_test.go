@@ -0,0 +1,149 @@
+package nats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+	"gofr.dev/pkg/gofr/testutil"
+)
+
+func TestNewDeadLetterQueue(t *testing.T) {
+	logger := logging.NewMockLogger(logging.DEBUG)
+
+	assert.Nil(t, newDeadLetterQueue(nil, &Config{MaxDeliver: 3}, logger, nil))
+	assert.Nil(t, newDeadLetterQueue(nil, &Config{DLQSubject: "orders.dlq"}, logger, nil))
+	assert.Nil(t, newDeadLetterQueue(nil, &Config{DLQSubject: "orders.dlq", MaxDeliver: -1}, logger, nil))
+
+	dlq := newDeadLetterQueue(nil, &Config{DLQSubject: "orders.dlq", Stream: StreamConfig{MaxDeliver: 3}}, logger, nil)
+	require.NotNil(t, dlq)
+	assert.Equal(t, 3, dlq.maxDeliver)
+	assert.Equal(t, "orders.dlq", dlq.subject)
+
+	dlq = newDeadLetterQueue(nil, &Config{DLQSubject: "orders.dlq", MaxDeliver: 3, SubjectPrefix: "prod."}, logger, nil)
+	require.NotNil(t, dlq)
+	assert.Equal(t, "prod.orders.dlq", dlq.subject)
+}
+
+func TestDeadLetterQueue_Exhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMsg := NewMockMsg(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+
+	cfg := &Config{DLQSubject: "orders.dlq", MaxDeliver: 3}
+	dlq := newDeadLetterQueue(mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+	ctx := context.Background()
+
+	msgChan := make(chan jetstream.Msg, 1)
+	msgChan <- mockMsg
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+
+	mockMsg.EXPECT().Metadata().Return(&jetstream.MsgMetadata{NumDelivered: 5}, nil)
+	mockMsg.EXPECT().Headers().Return(nats.Header{"Content-Type": []string{"application/json"}}).AnyTimes()
+	mockMsg.EXPECT().Subject().Return("orders.created").AnyTimes()
+	mockMsg.EXPECT().Data().Return([]byte("poison"))
+
+	mockJS.EXPECT().PublishMsg(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			assert.Equal(t, "orders.dlq", msg.Subject)
+			assert.Equal(t, []byte("poison"), msg.Data)
+			assert.Equal(t, "orders.created", msg.Header.Get("X-Original-Subject"))
+			assert.Equal(t, "application/json", msg.Header.Get("Content-Type"))
+
+			return &jetstream.PubAck{}, nil
+		})
+	mockMsg.EXPECT().Term().Return(nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_dlq_total_count", "subject", "orders.created", "dlq_subject", "orders.dlq")
+
+	buffer := make(chan *pubsub.Message, 1)
+
+	// the exhausted message is moved to the DLQ instead of being delivered
+	_, err := newSubscriptionManager(1).processFetchedMessages(ctx, mockBatch, "orders.created", buffer, cfg,
+		logging.NewMockLogger(logging.DEBUG), mockMetrics, dlq)
+	require.NoError(t, err)
+	assert.Empty(t, buffer)
+}
+
+func TestDeadLetterQueue_NotExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsg := NewMockMsg(ctrl)
+	dlq := newDeadLetterQueue(NewMockJetStream(ctrl), &Config{DLQSubject: "orders.dlq", MaxDeliver: 3},
+		logging.NewMockLogger(logging.DEBUG), NewMockMetrics(ctrl))
+
+	mockMsg.EXPECT().Metadata().Return(&jetstream.MsgMetadata{NumDelivered: 2}, nil)
+
+	assert.False(t, dlq.handle(context.Background(), mockMsg))
+
+	var disabled *deadLetterQueue
+
+	assert.False(t, disabled.handle(context.Background(), mockMsg))
+}
+
+func TestDeadLetterQueue_PublishError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockMsg := NewMockMsg(ctrl)
+	dlq := newDeadLetterQueue(mockJS, &Config{DLQSubject: "orders.dlq", MaxDeliver: 3},
+		logging.NewMockLogger(logging.DEBUG), NewMockMetrics(ctrl))
+
+	mockMsg.EXPECT().Metadata().Return(&jetstream.MsgMetadata{NumDelivered: 3}, nil)
+	mockMsg.EXPECT().Headers().Return(nil).AnyTimes()
+	mockMsg.EXPECT().Subject().Return("orders.created").AnyTimes()
+	mockMsg.EXPECT().Data().Return([]byte("poison"))
+	mockJS.EXPECT().PublishMsg(gomock.Any(), gomock.Any()).Return(nil, errPublishError)
+
+	// the message is not terminated when it could not be moved
+	assert.False(t, dlq.handle(context.Background(), mockMsg))
+}
+
+func TestReportPoisonSuspect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	cfg := &Config{PoisonAttemptThreshold: 5}
+	mockMetrics := NewMockMetrics(ctrl)
+
+	suspect := NewMockMsg(ctrl)
+	suspect.EXPECT().Metadata().Return(&jetstream.MsgMetadata{
+		NumDelivered: 5,
+		Stream:       "orders",
+		Sequence:     jetstream.SequencePair{Stream: 42},
+	}, nil)
+	suspect.EXPECT().Subject().Return("orders.created").AnyTimes()
+
+	// the message is reported on the delivery reaching the threshold only
+	redelivered := NewMockMsg(ctrl)
+	redelivered.EXPECT().Metadata().Return(&jetstream.MsgMetadata{NumDelivered: 6}, nil)
+
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_poison_suspect_count", "subject", "orders.created")
+
+	out := testutil.StderrOutputForFunc(func() {
+		logger := logging.NewMockLogger(logging.DEBUG)
+
+		reportPoisonSuspect(ctx, suspect, cfg, logger, mockMetrics)
+		reportPoisonSuspect(ctx, redelivered, cfg, logger, mockMetrics)
+
+		// no threshold disables the check without reading the metadata
+		reportPoisonSuspect(ctx, NewMockMsg(ctrl), &Config{}, logger, mockMetrics)
+	})
+
+	assert.Contains(t, out, "message 42 of stream orders on orders.created is likely poison: delivered 5 times")
+}
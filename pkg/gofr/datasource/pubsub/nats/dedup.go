@@ -0,0 +1,69 @@
+package nats
+
+import (
+	"container/list"
+	"sync"
+)
+
+// dedupCache is a fixed size LRU of recently published message IDs. A nil cache remembers nothing.
+type dedupCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List
+	ids   map[string]*list.Element
+}
+
+// newDedupCache returns a cache holding up to size IDs, or nil when size is not positive.
+func newDedupCache(size int) *dedupCache {
+	if size <= 0 {
+		return nil
+	}
+
+	return &dedupCache{
+		size:  size,
+		order: list.New(),
+		ids:   make(map[string]*list.Element, size),
+	}
+}
+
+// contains reports whether id was published recently, marking it as the most recently used.
+func (d *dedupCache) contains(id string) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	elem, ok := d.ids[id]
+	if ok {
+		d.order.MoveToFront(elem)
+	}
+
+	return ok
+}
+
+// add records id, evicting the least recently used ID once the cache is full.
+func (d *dedupCache) add(id string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if elem, ok := d.ids[id]; ok {
+		d.order.MoveToFront(elem)
+
+		return
+	}
+
+	d.ids[id] = d.order.PushFront(id)
+
+	if d.order.Len() > d.size {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.ids, oldest.Value.(string))
+	}
+}
@@ -0,0 +1,31 @@
+package nats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupCache(t *testing.T) {
+	assert.Nil(t, newDedupCache(0))
+
+	// a disabled cache remembers nothing
+	var disabled *dedupCache
+
+	disabled.add("msg-1")
+	assert.False(t, disabled.contains("msg-1"))
+
+	cache := newDedupCache(2)
+	cache.add("msg-1")
+	cache.add("msg-2")
+
+	assert.True(t, cache.contains("msg-1"))
+	assert.False(t, cache.contains("msg-3"))
+
+	// msg-1 was used last, so msg-2 is evicted
+	cache.add("msg-3")
+
+	assert.True(t, cache.contains("msg-1"))
+	assert.False(t, cache.contains("msg-2"))
+	assert.True(t, cache.contains("msg-3"))
+}
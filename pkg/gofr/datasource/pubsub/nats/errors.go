@@ -4,20 +4,141 @@ import "errors"
 
 var (
 	// Client Errors.
-	errServerNotProvided       = errors.New("client server address not provided")
-	errSubjectsNotProvided     = errors.New("subjects not provided")
-	errConsumerNotProvided     = errors.New("consumer name not provided")
-	errConsumerCreationError   = errors.New("consumer creation error")
-	errFailedToDeleteStream    = errors.New("failed to delete stream")
-	errPublishError            = errors.New("publish error")
-	errJetStreamNotConfigured  = errors.New("jStream is not configured")
-	errJetStreamCreationFailed = errors.New("jStream creation failed")
-	errJetStream               = errors.New("jStream error")
-	errCreateStream            = errors.New("create stream error")
-	errDeleteStream            = errors.New("delete stream error")
-	errGetStream               = errors.New("get stream error")
-	errCreateOrUpdateStream    = errors.New("create or update stream error")
-	errHandlerError            = errors.New("handler error")
-	errConnectionError         = errors.New("connection error")
-	errSubscriptionError       = errors.New("subscription error")
+	errServerNotProvided           = errors.New("client server address not provided")
+	errInvalidServerURL            = errors.New("server URL must have a nats:// or tls:// scheme and a host")
+	errSubjectsNotProvided         = errors.New("subjects not provided")
+	errFilterSubjectNotInStream    = errors.New("filter subject is not covered by the subjects of any configured stream")
+	errConsumerNotProvided         = errors.New("consumer name not provided")
+	errConsumerRequiredForDurable  = errors.New("consumer name is required for durable consumers")
+	errMultipleAuthMethods         = errors.New("only one of creds file, token, username/password or nkey file can be configured")
+	errDomainWithAPIPrefix         = errors.New("only one of jStream domain or API prefix can be configured")
+	errInvalidSubjectPrefix        = errors.New("subject prefix cannot contain wildcards or whitespace")
+	errInvalidNKeySeed             = errors.New("failed to load nkey seed")
+	errCertAndKeyRequired          = errors.New("TLS cert file and key file must be provided together")
+	errQueueGroupWithEphemeral     = errors.New("queue group cannot be used with ephemeral consumers")
+	errStartFromConflict           = errors.New("only one of start sequence or start time can be set")
+	errOrderedConsumerConflict     = errors.New("ordered consumers cannot be used with a consumer name or queue group")
+	errAttachOnlyRequiresConsumer  = errors.New("attaching to an existing consumer requires a durable consumer name")
+	errInvalidMaxDeliver           = errors.New("max deliver must be -1 or greater")
+	errInvalidBackoff              = errors.New("backoff delays must be positive")
+	errBackoffExceedsMaxDeliver    = errors.New("max deliver must be greater than the number of backoff delays")
+	errInvalidCircuitBreaker       = errors.New("circuit breaker failure threshold and open duration must be positive")
+	errHeartbeatWithoutFlowControl = errors.New("idle heartbeat requires flow control or an ordered consumer")
+	errHeartbeatTooLong            = errors.New("idle heartbeat must be less than half of max wait")
+	errConsumerCreationError       = errors.New("consumer creation error")
+	errConsumerNotFound            = errors.New("consumer does not exist")
+	errConsumerDeleted             = errors.New("consumer was deleted")
+	errFailedToDeleteStream        = errors.New("failed to delete stream")
+	errPublishError                = errors.New("publish error")
+	errCircuitOpen                 = errors.New("publish circuit breaker is open")
+	errRateLimited                 = errors.New("publish rate limit exceeded")
+	errNoStreamForSubject          = errors.New("no stream captures subject")
+	errStreamMissing               = errors.New("stream does not exist")
+	errStreamPrefixRequired        = errors.New("stream name prefix is required")
+	errCoreFallbackSubscribe       = errors.New("subscribing requires JetStream, the core NATS fallback only supports publishing")
+	errMirrorWithSubjects          = errors.New("a mirror stream cannot have subjects")
+	errInvalidRePublish            = errors.New("republish requires both a source and a destination")
+	errInvalidStorageType          = errors.New("invalid stream storage type")
+	errPayloadTooLarge             = errors.New("message payload too large")
+	errDecompressedTooLarge        = errors.New("decompressed message payload too large")
+	errJetStreamNotConfigured      = errors.New("jStream is not configured")
+	errJetStreamCreationFailed     = errors.New("jStream creation failed")
+	errJetStream                   = errors.New("jStream error")
+	errCreateStream                = errors.New("create stream error")
+	errDeleteStream                = errors.New("delete stream error")
+	errGetStream                   = errors.New("get stream error")
+	errCreateOrUpdateStream        = errors.New("create or update stream error")
+	errHandlerError                = errors.New("handler error")
+	errInvalidJSON                 = errors.New("invalid JSON")
+	errConnectionError             = errors.New("connection error")
+	errSubscriptionError           = errors.New("subscription error")
+	errSubscriptionNotFound        = errors.New("subscription not found")
+	errNoSubjects                  = errors.New("no subjects to subscribe to")
+	errDuplicateSubject            = errors.New("subject is listed more than once")
+	errNoFirstMessage              = errors.New("no message received before the first message deadline")
+	errDrainTimeout                = errors.New("timed out draining NATS connection")
+	errStreamOperationAborted      = errors.New("stream operation did not complete before the context was done")
+	errStatusDown                  = errors.New("status down")
+	errConnectionNotEstablished    = errors.New("NATS connection not established")
+	errAckFailed                   = errors.New("failed to acknowledge message")
+	errInvalidAckSequence          = errors.New("ack sequence must be positive")
+	errAckSequenceOutOfRange       = errors.New("ack sequence out of range")
+	errAckFloorNotReached          = errors.New("ack floor not reached")
+	errPublishValidation           = errors.New("message rejected by publish validator")
+	errEncodeFailed                = errors.New("failed to encode message")
+	errDecodeFailed                = errors.New("failed to decode message")
+	errUnexpectedContentType       = errors.New("unexpected content type")
+	errBatchPublishFailed          = errors.New("failed to publish message")
+	errNotNATSMessage              = errors.New("message was not received from NATS")
 )
+
+// ErrorKind is the category of a NatsError, letting callers tell configuration mistakes from
+// connection problems and failed operations without matching error strings.
+type ErrorKind int
+
+const (
+	// ConfigError reports an invalid Config.
+	ConfigError ErrorKind = iota + 1
+	// ConnectionError reports a failure to connect to NATS or to set up jStream.
+	ConnectionError
+	// PublishError reports a message that could not be published.
+	PublishError
+	// SubscribeError reports a subscription that could not be set up or a message that could not be received.
+	SubscribeError
+	// StreamError reports a stream that could not be created, updated, deleted or inspected.
+	StreamError
+	// RequestError reports a request that got no reply or a responder that could not be registered.
+	RequestError
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ConfigError:
+		return "config"
+	case ConnectionError:
+		return "connection"
+	case PublishError:
+		return "publish"
+	case SubscribeError:
+		return "subscribe"
+	case StreamError:
+		return "stream"
+	case RequestError:
+		return "request"
+	default:
+		return "unknown"
+	}
+}
+
+// NatsError is returned by the Client to classify the error it wraps. errors.Is still matches the
+// wrapped error, and errors.Is(err, &NatsError{Kind: kind}) matches any error of that kind.
+type NatsError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *NatsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NatsError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a NatsError of the same kind that wraps no error of its own.
+func (e *NatsError) Is(target error) bool {
+	t, ok := target.(*NatsError)
+
+	return ok && t.Err == nil && t.Kind == e.Kind
+}
+
+// newNatsError wraps err as a NatsError of the given kind. It returns nil for a nil err and leaves
+// errors that are already classified as they are.
+func newNatsError(kind ErrorKind, err error) error {
+	var natsErr *NatsError
+	if err == nil || errors.As(err, &natsErr) {
+		return err
+	}
+
+	return &NatsError{Kind: kind, Err: err}
+}
@@ -0,0 +1,173 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestNatsError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		sentinel error
+		kind     ErrorKind
+	}{
+		{desc: "config", err: newNatsError(ConfigError, errServerNotProvided), sentinel: errServerNotProvided, kind: ConfigError},
+		{desc: "connection", err: newNatsError(ConnectionError, errConnectionError), sentinel: errConnectionError, kind: ConnectionError},
+		{desc: "publish", err: newNatsError(PublishError, errPublishError), sentinel: errPublishError, kind: PublishError},
+		{
+			desc:     "wrapped subscribe",
+			err:      newNatsError(SubscribeError, fmt.Errorf("%w: orders", errSubscriptionNotFound)),
+			sentinel: errSubscriptionNotFound,
+			kind:     SubscribeError,
+		},
+		{
+			desc:     "already classified",
+			err:      newNatsError(SubscribeError, newNatsError(ConfigError, errSubjectsNotProvided)),
+			sentinel: errSubjectsNotProvided,
+			kind:     ConfigError,
+		},
+	}
+
+	for i, tc := range testCases {
+		require.ErrorIs(t, tc.err, tc.sentinel, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		var natsErr *NatsError
+
+		require.ErrorAs(t, tc.err, &natsErr, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.kind, natsErr.Kind, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.ErrorIs(t, tc.err, &NatsError{Kind: tc.kind}, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+
+	require.NoError(t, newNatsError(PublishError, nil))
+	assert.NotErrorIs(t, newNatsError(PublishError, errPublishError), &NatsError{Kind: ConfigError})
+	assert.Equal(t, "subscribe", SubscribeError.String())
+	assert.Equal(t, "unknown", ErrorKind(0).String())
+}
+
+func TestClient_Connect_ConfigError(t *testing.T) {
+	client := &Client{Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	err := client.Connect()
+	require.ErrorIs(t, err, errServerNotProvided)
+	assert.ErrorIs(t, err, &NatsError{Kind: ConfigError})
+}
+
+func TestClient_PublicErrorsAreNatsErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	client := &Client{
+		connManager:   mockConnManager,
+		streamManager: mockStreamManager,
+		subManager:    newSubscriptionManager(1),
+		Config:        &Config{},
+		metrics:       mockMetrics,
+		logger:        logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+	handler := func(*pubsub.Message) ([]byte, error) { return nil, nil }
+
+	mockConnManager.EXPECT().PublishAsync(ctx, "orders", []byte("m"), gomock.Any(), mockMetrics).Return(nil, errPublishError)
+	mockConnManager.EXPECT().PublishBatch(ctx, "orders", [][]byte{[]byte("m")}, mockMetrics).Return(errPublishError)
+	mockConnManager.EXPECT().Request(ctx, "orders", []byte("m"), mockMetrics).Return(nil, nats.ErrTimeout)
+	mockConnManager.EXPECT().RequestWithHeaders(ctx, "orders", []byte("m"), nats.Header{}, mockMetrics).Return(nil, nats.ErrTimeout)
+	mockConnManager.EXPECT().SubscribeRequests("orders", gomock.Any()).Return(nil, errConnectionError)
+	mockStreamManager.EXPECT().CreateStream(ctx, StreamConfig{Stream: "orders"}).Return(jetstream.ErrStreamNameAlreadyInUse)
+	mockStreamManager.EXPECT().DeleteStream(ctx, "orders").Return(errFailedToDeleteStream)
+
+	testCases := []struct {
+		desc     string
+		call     func() error
+		sentinel error
+		kind     ErrorKind
+	}{
+		{
+			desc: "PublishAsync",
+			call: func() error {
+				_, err := client.PublishAsync(ctx, "orders", []byte("m"))
+				return err
+			},
+			sentinel: errPublishError,
+			kind:     PublishError,
+		},
+		{
+			desc:     "PublishBatch",
+			call:     func() error { return client.PublishBatch(ctx, "orders", [][]byte{[]byte("m")}) },
+			sentinel: errPublishError,
+			kind:     PublishError,
+		},
+		{
+			desc: "Request",
+			call: func() error {
+				_, err := client.Request(ctx, "orders", []byte("m"))
+				return err
+			},
+			sentinel: nats.ErrTimeout,
+			kind:     RequestError,
+		},
+		{
+			desc: "RequestWithHeaders",
+			call: func() error {
+				_, err := client.RequestWithHeaders(ctx, "orders", []byte("m"), nats.Header{})
+				return err
+			},
+			sentinel: nats.ErrTimeout,
+			kind:     RequestError,
+		},
+		{
+			desc:     "RegisterResponder",
+			call:     func() error { return client.RegisterResponder(ctx, "orders", handler) },
+			sentinel: errConnectionError,
+			kind:     RequestError,
+		},
+		{
+			desc:     "Unsubscribe",
+			call:     func() error { return client.Unsubscribe(ctx, "orders", false) },
+			sentinel: errSubscriptionNotFound,
+			kind:     SubscribeError,
+		},
+		{
+			desc:     "AckUpTo",
+			call:     func() error { return client.AckUpTo(ctx, "orders", "billing", 0) },
+			sentinel: errInvalidAckSequence,
+			kind:     SubscribeError,
+		},
+		{
+			desc:     "CreateStream",
+			call:     func() error { return client.CreateStream(ctx, StreamConfig{Stream: "orders"}) },
+			sentinel: jetstream.ErrStreamNameAlreadyInUse,
+			kind:     StreamError,
+		},
+		{
+			desc:     "DeleteStream",
+			call:     func() error { return client.DeleteStream(ctx, "orders") },
+			sentinel: errFailedToDeleteStream,
+			kind:     StreamError,
+		},
+	}
+
+	for i, tc := range testCases {
+		err := tc.call()
+		require.ErrorIs(t, err, tc.sentinel, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+		var natsErr *NatsError
+
+		require.ErrorAs(t, err, &natsErr, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.kind, natsErr.Kind, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
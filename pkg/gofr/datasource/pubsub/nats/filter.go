@@ -0,0 +1,71 @@
+package nats
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// subscribeFilter holds the predicate set with SetSubscribeFilter, which sees every fetched message
+// before it is buffered for Subscribe or passed to a handler. A nil subscribeFilter keeps every
+// message.
+type subscribeFilter struct {
+	keepFn atomic.Pointer[func(*pubsub.Message) bool]
+}
+
+func (f *subscribeFilter) set(keep func(*pubsub.Message) bool) {
+	if keep == nil {
+		f.keepFn.Store(nil)
+
+		return
+	}
+
+	f.keepFn.Store(&keep)
+}
+
+// enabled reports whether a predicate is set.
+func (f *subscribeFilter) enabled() bool {
+	return f != nil && f.keepFn.Load() != nil
+}
+
+// drop reports whether msg is dropped by the predicate, in which case it is acknowledged so that it
+// is not redelivered, and app_pubsub_filtered_count is incremented.
+func (f *subscribeFilter) drop(ctx context.Context, msg jetstream.Msg, pubsubMsg *pubsub.Message, logger pubsub.Logger,
+	metrics Metrics) bool {
+	if f == nil {
+		return false
+	}
+
+	keep := f.keepFn.Load()
+	if keep == nil || (*keep)(pubsubMsg) {
+		return false
+	}
+
+	if err := msg.Ack(); err != nil {
+		logger.Errorf("failed to acknowledge filtered message on %s: %v", msg.Subject(), err)
+	}
+
+	if metrics != nil {
+		metrics.IncrementCounter(ctx, filteredCountMetric, "subject", msg.Subject())
+	}
+
+	return true
+}
+
+// SetSubscribeFilter registers a predicate that every received message must satisfy to be delivered,
+// e.g. to skip a deprecated event version. Messages it returns false for are acknowledged and dropped
+// before Subscribe returns them or a handler sees them. A nil predicate disables filtering.
+func (c *Client) SetSubscribeFilter(keep func(*pubsub.Message) bool) {
+	c.subscribeFilter().set(keep)
+}
+
+// subscribeFilter returns the filter of received messages, which survives a Reconnect.
+func (c *Client) subscribeFilter() *subscribeFilter {
+	c.filterOnce.Do(func() {
+		c.filter = &subscribeFilter{}
+	})
+
+	return c.filter
+}
@@ -0,0 +1,79 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestSubscriptionManager_processFetchedMessages_Filter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	msgChan := make(chan jetstream.Msg, 4)
+
+	for i := 0; i < 4; i++ {
+		msg := NewMockMsg(ctrl)
+		msg.EXPECT().Headers().Return(nats.Header{}).AnyTimes()
+		msg.EXPECT().Data().Return([]byte(fmt.Sprint(i))).AnyTimes()
+		msg.EXPECT().Subject().Return("orders.created").AnyTimes()
+
+		// every other message is dropped, and acknowledged so that it is not redelivered
+		if i%2 == 1 {
+			msg.EXPECT().Ack().Return(nil)
+		}
+
+		msgChan <- msg
+	}
+
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan)
+	mockBatch.EXPECT().Error().Return(nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_filtered_count", "subject", "orders.created").Times(2)
+
+	client := &Client{}
+	client.SetSubscribeFilter(func(msg *pubsub.Message) bool {
+		return string(msg.Value) == "0" || string(msg.Value) == "2"
+	})
+
+	sm := newSubscriptionManager(4)
+	sm.filter = client.subscribeFilter()
+
+	buffer := make(chan *pubsub.Message, 4)
+
+	_, err := sm.processFetchedMessages(ctx, mockBatch, "orders.created", buffer, &Config{},
+		logging.NewMockLogger(logging.DEBUG), mockMetrics, nil)
+	require.NoError(t, err)
+
+	require.Len(t, buffer, 2)
+	assert.Equal(t, []byte("0"), (<-buffer).Value)
+	assert.Equal(t, []byte("2"), (<-buffer).Value)
+}
+
+func TestClient_SetSubscribeFilter_Nil(t *testing.T) {
+	client := &Client{}
+	assert.False(t, client.subscribeFilter().enabled())
+
+	client.SetSubscribeFilter(func(*pubsub.Message) bool { return false })
+	assert.True(t, client.subscribeFilter().enabled())
+
+	// a nil filter keeps every message
+	client.SetSubscribeFilter(nil)
+	assert.False(t, client.subscribeFilter().enabled())
+
+	var filter *subscribeFilter
+	assert.False(t, filter.drop(context.Background(), nil, nil, nil, nil))
+}
@@ -44,3 +44,50 @@ func (c *Client) Health() datasource.Health {
 
 	return health
 }
+
+// HealthCheck reports the health of the NATS connection and verifies that the configured stream exists.
+// The connection is reported DOWN along with its status when it is draining or closed.
+func (c *Client) HealthCheck(ctx context.Context) (any, error) {
+	if c.connManager == nil {
+		return &datasource.Health{
+			Status:  datasource.StatusDown,
+			Details: map[string]interface{}{"error": errConnectionNotEstablished.Error()},
+		}, errConnectionNotEstablished
+	}
+
+	health := c.connManager.Health()
+	if health.Details == nil {
+		health.Details = make(map[string]any)
+	}
+
+	health.Details["backend"] = natsBackend
+
+	if health.Status != datasource.StatusUp {
+		return &health, errStatusDown
+	}
+
+	js, err := c.connManager.jetStream()
+	if err != nil {
+		health.Status = datasource.StatusDown
+		health.Details["error"] = err.Error()
+
+		return &health, err
+	}
+
+	streams := make([]string, 0, len(c.Config.Streams)+1)
+
+	for _, stream := range configuredStreams(c.Config) {
+		if _, err := js.Stream(ctx, stream.Stream); err != nil {
+			health.Status = datasource.StatusDown
+			health.Details["error"] = err.Error()
+
+			return &health, err
+		}
+
+		streams = append(streams, stream.Stream)
+	}
+
+	health.Details["streams"] = streams
+
+	return &health, nil
+}
@@ -1,10 +1,13 @@
 package nats
 
 import (
+	"context"
 	"testing"
 
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"gofr.dev/pkg/gofr/datasource"
 	"gofr.dev/pkg/gofr/logging"
@@ -123,3 +126,82 @@ type healthTestCase struct {
 	expectedStatus  string
 	expectedDetails map[string]interface{}
 }
+
+func TestNATSClient_HealthCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{Server: NATSServer, Stream: StreamConfig{Stream: "test-stream"}},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	ctx := context.Background()
+
+	upHealth := func() datasource.Health {
+		return datasource.Health{
+			Status:  datasource.StatusUp,
+			Details: map[string]interface{}{"server": NATSServer, "cluster": "test-cluster"},
+		}
+	}
+
+	t.Run("Up", func(t *testing.T) {
+		mockConnManager.EXPECT().Health().Return(upHealth())
+		mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+		mockJS.EXPECT().Stream(ctx, "test-stream").Return(NewMockStream(ctrl), nil)
+
+		res, err := client.HealthCheck(ctx)
+		require.NoError(t, err)
+
+		h, ok := res.(*datasource.Health)
+		require.True(t, ok)
+		assert.Equal(t, datasource.StatusUp, h.Status)
+		assert.Equal(t, "test-cluster", h.Details["cluster"])
+		assert.Equal(t, []string{"test-stream"}, h.Details["streams"])
+	})
+
+	t.Run("StreamMissing", func(t *testing.T) {
+		mockConnManager.EXPECT().Health().Return(upHealth())
+		mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+		mockJS.EXPECT().Stream(ctx, "test-stream").Return(nil, jetstream.ErrStreamNotFound)
+
+		res, err := client.HealthCheck(ctx)
+		require.ErrorIs(t, err, jetstream.ErrStreamNotFound)
+		assert.Equal(t, datasource.StatusDown, res.(*datasource.Health).Status)
+	})
+
+	t.Run("Draining", func(t *testing.T) {
+		mockConnManager.EXPECT().Health().Return(datasource.Health{
+			Status:  datasource.StatusDown,
+			Details: map[string]interface{}{"server": NATSServer, "connection_status": nats.DRAINING_SUBS.String()},
+		})
+
+		res, err := client.HealthCheck(ctx)
+		require.ErrorIs(t, err, errStatusDown)
+
+		h := res.(*datasource.Health)
+		assert.Equal(t, datasource.StatusDown, h.Status)
+		assert.Equal(t, nats.DRAINING_SUBS.String(), h.Details["connection_status"])
+	})
+
+	t.Run("NoConnection", func(t *testing.T) {
+		mockConnManager.EXPECT().Health().Return(datasource.Health{Status: datasource.StatusDown})
+
+		res, err := client.HealthCheck(ctx)
+		require.ErrorIs(t, err, errStatusDown)
+
+		h := res.(*datasource.Health)
+		assert.Equal(t, datasource.StatusDown, h.Status)
+		assert.Equal(t, natsBackend, h.Details["backend"])
+	})
+
+	t.Run("NotConnected", func(t *testing.T) {
+		res, err := (&Client{Config: &Config{}}).HealthCheck(ctx)
+		require.ErrorIs(t, err, errConnectionNotEstablished)
+		assert.Equal(t, datasource.StatusDown, res.(*datasource.Health).Status)
+	})
+}
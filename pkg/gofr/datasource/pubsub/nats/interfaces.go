@@ -2,6 +2,7 @@ package nats
 
 import (
 	"context"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
@@ -16,8 +17,17 @@ import (
 type ConnInterface interface {
 	Status() nats.Status
 	Close()
+	Drain() error
+	ConnectedClusterName() string
+	MaxPayload() int64
 	NATSConn() *nats.Conn
 	JetStream() (jetstream.JetStream, error)
+	RequestWithContext(ctx context.Context, subject string, data []byte) (*nats.Msg, error)
+	RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error)
+	FlushWithContext(ctx context.Context) error
+	RTT() (time.Duration, error)
+	Subscribe(subject string, cb nats.MsgHandler) (*nats.Subscription, error)
+	PublishMsg(msg *nats.Msg) error
 }
 
 // Connector represents the main Client connection.
@@ -25,7 +35,8 @@ type Connector interface {
 	Connect(string, ...nats.Option) (ConnInterface, error)
 }
 
-// JetStreamCreator represents the main Client jStream Client.
+// JetStreamCreator represents the main Client jStream Client. The client only uses the jetstream
+// package API, which replaces the deprecated nats.JetStreamContext, and mocks it through jetstream.JetStream.
 type JetStreamCreator interface {
 	New(conn ConnInterface) (jetstream.JetStream, error)
 }
@@ -38,15 +49,30 @@ type JetStreamClient interface {
 	DeleteStream(ctx context.Context, name string) error
 	CreateStream(ctx context.Context, cfg StreamConfig) error
 	CreateOrUpdateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error)
+	ConsumerInfo(ctx context.Context, stream, consumer string) (*jetstream.ConsumerInfo, error)
+	PurgeStream(ctx context.Context, stream string, opts ...jetstream.StreamPurgeOpt) error
 	Health() datasource.Health
 }
 
 // ConnectionManagerInterface represents the main Client connection.
 type ConnectionManagerInterface interface {
 	Connect() error
-	Close(ctx context.Context)
+	Close(ctx context.Context) error
+	Drain(ctx context.Context) error
 	Publish(ctx context.Context, subject string, message []byte, metrics Metrics) error
+	PublishWithHeaders(ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) error
+	PublishAck(ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) (*jetstream.PubAck, error)
+	PublishAsync(ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) (jetstream.PubAckFuture, error)
+	PublishAsyncComplete(ctx context.Context) error
+	PublishBatch(ctx context.Context, subject string, msgs [][]byte, metrics Metrics) error
+	Flush(ctx context.Context) error
+	RTT(ctx context.Context) (time.Duration, error)
+	Request(ctx context.Context, subject string, data []byte, metrics Metrics) (*pubsub.Message, error)
+	RequestWithHeaders(ctx context.Context, subject string, data []byte, headers nats.Header, metrics Metrics) (*pubsub.Message, error)
+	SubscribeRequests(subject string, handler nats.MsgHandler) (*nats.Subscription, error)
+	PublishReply(reply *nats.Msg) error
 	Health() datasource.Health
+	Status() nats.Status
 	jetStream() (jetstream.JetStream, error)
 }
 
@@ -59,6 +85,7 @@ type SubscriptionManagerInterface interface {
 		cfg *Config,
 		logger pubsub.Logger,
 		metrics Metrics) (*pubsub.Message, error)
+	Unsubscribe(topic string) bool
 	Close()
 }
 
@@ -67,4 +94,8 @@ type StreamManagerInterface interface {
 	CreateStream(ctx context.Context, cfg StreamConfig) error
 	DeleteStream(ctx context.Context, name string) error
 	CreateOrUpdateStream(ctx context.Context, cfg *jetstream.StreamConfig) (jetstream.Stream, error)
+	ConsumerInfo(ctx context.Context, stream, consumer string) (*jetstream.ConsumerInfo, error)
+	PurgeStream(ctx context.Context, stream string, opts ...jetstream.StreamPurgeOpt) error
+	StreamNames(ctx context.Context) ([]string, error)
+	UpdateStream(ctx context.Context, cfg StreamConfig) error
 }
@@ -0,0 +1,130 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// KeyValue is a NATS jStream key-value bucket sharing the connection and logger of the Client.
+type KeyValue struct {
+	kv     jetstream.KeyValue
+	bucket string
+	logger pubsub.Logger
+}
+
+// KeyValueEvent is an update of a key in a watched bucket.
+type KeyValueEvent struct {
+	Key      string
+	Value    []byte
+	Revision uint64
+	Deleted  bool
+}
+
+// KeyValue returns the key-value bucket with the given name, creating it if it does not exist.
+func (c *Client) KeyValue(ctx context.Context, bucket string) (*KeyValue, error) {
+	js, err := c.connManager.jetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := js.KeyValue(ctx, bucket)
+	if errors.Is(err, jetstream.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: bucket})
+	}
+
+	if err != nil {
+		c.logger.Errorf("failed to open key-value bucket %s: %v", bucket, err)
+
+		return nil, fmt.Errorf("failed to open key-value bucket %s: %w", bucket, err)
+	}
+
+	return &KeyValue{kv: kv, bucket: bucket, logger: c.logger}, nil
+}
+
+// Get returns the value stored for key.
+func (k *KeyValue) Get(ctx context.Context, key string) ([]byte, error) {
+	entry, err := k.kv.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key %s from bucket %s: %w", key, k.bucket, err)
+	}
+
+	return entry.Value(), nil
+}
+
+// Put stores value for key.
+func (k *KeyValue) Put(ctx context.Context, key string, value []byte) error {
+	if _, err := k.kv.Put(ctx, key, value); err != nil {
+		return fmt.Errorf("failed to put key %s in bucket %s: %w", key, k.bucket, err)
+	}
+
+	k.logger.Debugf("put key %s in bucket %s", key, k.bucket)
+
+	return nil
+}
+
+// Delete removes key from the bucket.
+func (k *KeyValue) Delete(ctx context.Context, key string) error {
+	if err := k.kv.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete key %s from bucket %s: %w", key, k.bucket, err)
+	}
+
+	k.logger.Debugf("deleted key %s from bucket %s", key, k.bucket)
+
+	return nil
+}
+
+// Watch returns a channel of updates to the keys matching keys, which may contain wildcards.
+// The current values are sent first. The channel is closed when ctx is done.
+func (k *KeyValue) Watch(ctx context.Context, keys string) (<-chan KeyValueEvent, error) {
+	watcher, err := k.kv.Watch(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch keys %s in bucket %s: %w", keys, k.bucket, err)
+	}
+
+	events := make(chan KeyValueEvent)
+
+	go func() {
+		defer close(events)
+
+		defer func() {
+			if err := watcher.Stop(); err != nil {
+				k.logger.Errorf("failed to stop watching keys %s in bucket %s: %v", keys, k.bucket, err)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+
+				// a nil entry marks the end of the initial values
+				if entry == nil {
+					continue
+				}
+
+				event := KeyValueEvent{
+					Key:      entry.Key(),
+					Value:    entry.Value(),
+					Revision: entry.Revision(),
+					Deleted:  entry.Operation() != jetstream.KeyValuePut,
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
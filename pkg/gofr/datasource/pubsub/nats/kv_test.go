@@ -0,0 +1,187 @@
+package nats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func newTestKeyValue(t *testing.T, ctrl *gomock.Controller, bucket string) (*KeyValue, *MockKeyValue, *MockJetStream) {
+	t.Helper()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockKV := NewMockKeyValue(ctrl)
+
+	client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().KeyValue(gomock.Any(), bucket).Return(mockKV, nil)
+
+	kv, err := client.KeyValue(context.Background(), bucket)
+	require.NoError(t, err)
+
+	return kv, mockKV, mockJS
+}
+
+func TestClient_KeyValue_CreatesMissingBucket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockKV := NewMockKeyValue(ctrl)
+
+	client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().KeyValue(ctx, "sessions").Return(nil, jetstream.ErrBucketNotFound)
+	mockJS.EXPECT().CreateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: "sessions"}).Return(mockKV, nil)
+
+	kv, err := client.KeyValue(ctx, "sessions")
+	require.NoError(t, err)
+	assert.Equal(t, mockKV, kv.kv)
+}
+
+func TestClient_KeyValue_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+
+	client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockJS.EXPECT().KeyValue(gomock.Any(), "sessions").Return(nil, errJetStream)
+
+	_, err := client.KeyValue(context.Background(), "sessions")
+	require.ErrorIs(t, err, errJetStream)
+
+	mockConnManager.EXPECT().JetStream().Return(nil, errJetStreamNotConfigured)
+
+	_, err = client.KeyValue(context.Background(), "sessions")
+	require.ErrorIs(t, err, errJetStreamNotConfigured)
+}
+
+func TestKeyValue_PutGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kv, mockKV, _ := newTestKeyValue(t, ctrl, "sessions")
+	ctx := context.Background()
+	store := map[string][]byte{}
+
+	mockKV.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, value []byte) (uint64, error) {
+			store[key] = value
+
+			return uint64(len(store)), nil
+		}).Times(2)
+	mockKV.EXPECT().Get(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string) (jetstream.KeyValueEntry, error) {
+			value, ok := store[key]
+			if !ok {
+				return nil, jetstream.ErrKeyNotFound
+			}
+
+			entry := NewMockKeyValueEntry(ctrl)
+			entry.EXPECT().Value().Return(value)
+
+			return entry, nil
+		}).Times(3)
+
+	require.NoError(t, kv.Put(ctx, "user.1", []byte("alice")))
+	require.NoError(t, kv.Put(ctx, "user.2", []byte("bob")))
+
+	value, err := kv.Get(ctx, "user.1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("alice"), value)
+
+	value, err = kv.Get(ctx, "user.2")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bob"), value)
+
+	_, err = kv.Get(ctx, "user.3")
+	require.ErrorIs(t, err, jetstream.ErrKeyNotFound)
+}
+
+func TestKeyValue_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kv, mockKV, _ := newTestKeyValue(t, ctrl, "sessions")
+	ctx := context.Background()
+
+	mockKV.EXPECT().Put(ctx, "user.1", []byte("alice")).Return(uint64(0), errJetStream)
+	mockKV.EXPECT().Delete(ctx, "user.1").Return(errJetStream)
+	mockKV.EXPECT().Watch(ctx, "user.*").Return(nil, errJetStream)
+
+	require.ErrorIs(t, kv.Put(ctx, "user.1", []byte("alice")), errJetStream)
+	require.ErrorIs(t, kv.Delete(ctx, "user.1"), errJetStream)
+
+	_, err := kv.Watch(ctx, "user.*")
+	require.ErrorIs(t, err, errJetStream)
+}
+
+func TestKeyValue_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kv, mockKV, _ := newTestKeyValue(t, ctrl, "sessions")
+	ctx := context.Background()
+
+	mockKV.EXPECT().Delete(ctx, "user.1").Return(nil)
+
+	require.NoError(t, kv.Delete(ctx, "user.1"))
+}
+
+func TestKeyValue_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kv, mockKV, _ := newTestKeyValue(t, ctrl, "sessions")
+	mockWatcher := NewMockKeyWatcher(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	put := NewMockKeyValueEntry(ctrl)
+	put.EXPECT().Key().Return("user.1")
+	put.EXPECT().Value().Return([]byte("alice"))
+	put.EXPECT().Revision().Return(uint64(1))
+	put.EXPECT().Operation().Return(jetstream.KeyValuePut)
+
+	deleted := NewMockKeyValueEntry(ctrl)
+	deleted.EXPECT().Key().Return("user.1")
+	deleted.EXPECT().Value().Return(nil)
+	deleted.EXPECT().Revision().Return(uint64(2))
+	deleted.EXPECT().Operation().Return(jetstream.KeyValueDelete)
+
+	updates := make(chan jetstream.KeyValueEntry, 3)
+	updates <- put
+	updates <- nil
+	updates <- deleted
+
+	mockKV.EXPECT().Watch(ctx, "user.*").Return(mockWatcher, nil)
+	mockWatcher.EXPECT().Updates().Return(updates).AnyTimes()
+	mockWatcher.EXPECT().Stop().Return(nil)
+
+	events, err := kv.Watch(ctx, "user.*")
+	require.NoError(t, err)
+
+	assert.Equal(t, KeyValueEvent{Key: "user.1", Value: []byte("alice"), Revision: 1}, <-events)
+	assert.Equal(t, KeyValueEvent{Key: "user.1", Revision: 2, Deleted: true}, <-events)
+
+	cancel()
+
+	// the channel is closed once the context is done
+	for range events {
+	}
+}
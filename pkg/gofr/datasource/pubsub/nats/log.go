@@ -0,0 +1,116 @@
+package nats
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+const (
+	defaultLogBodyMaxBytes = 256
+	defaultLoggerName      = "nats"
+)
+
+// messageLog is the log of a published or received message, carrying the name of the client's logger
+// so that the logs of several NATS clients of an application can be told apart.
+type messageLog struct {
+	pubsub.Log
+	Logger string `json:"logger"`
+}
+
+func (l *messageLog) PrettyPrint(writer io.Writer) {
+	fmt.Fprintf(writer, "\u001B[38;5;8m%-32s \u001B[38;5;24m%-6s\u001B[0m %8d\u001B[38;5;8mµs\u001B[0m %-4s %s %s \u001b[38;5;101m%s\u001b[0m\n",
+		l.CorrelationID, l.PubSubBackend, l.Time, l.Mode, l.Logger, l.Topic, l.MessageValue)
+}
+
+// logMessage logs a published or received message at DEBUG level. Message bodies are only included
+// when Config.LogMessageBody is set, as they may contain sensitive data.
+func (c *Client) logMessage(mode, correlationID, subject string, message []byte, start time.Time) {
+	c.logger.Debug(&messageLog{
+		Log: pubsub.Log{
+			Mode:          mode,
+			CorrelationID: correlationID,
+			MessageValue:  messageLogValue(c.Config, message),
+			Topic:         subject,
+			Host:          serverList(c.Config),
+			PubSubBackend: "NATS",
+			Time:          clockOrReal(c.clock).Now().Sub(start).Microseconds(),
+		},
+		Logger: loggerName(c.Config),
+	})
+}
+
+// loggerName returns Config.LoggerName, defaulting to "nats".
+func loggerName(conf *Config) string {
+	if conf == nil || conf.LoggerName == "" {
+		return defaultLoggerName
+	}
+
+	return conf.LoggerName
+}
+
+// namedLogger prefixes the log lines of a client with its logger name. Message logs are passed through
+// as they are, since they carry the name in a field of their own.
+type namedLogger struct {
+	pubsub.Logger
+	prefix string
+}
+
+func newNamedLogger(logger pubsub.Logger, conf *Config) namedLogger {
+	return namedLogger{Logger: logger, prefix: "[" + loggerName(conf) + "] "}
+}
+
+func (l namedLogger) Debugf(format string, args ...any) {
+	l.Logger.Debugf(l.prefix+format, args...)
+}
+
+func (l namedLogger) Debug(args ...any) {
+	l.Logger.Debug(l.named(args)...)
+}
+
+func (l namedLogger) Logf(format string, args ...any) {
+	l.Logger.Logf(l.prefix+format, args...)
+}
+
+func (l namedLogger) Log(args ...any) {
+	l.Logger.Log(l.named(args)...)
+}
+
+func (l namedLogger) Errorf(format string, args ...any) {
+	l.Logger.Errorf(l.prefix+format, args...)
+}
+
+func (l namedLogger) Error(args ...any) {
+	l.Logger.Error(l.named(args)...)
+}
+
+func (l namedLogger) named(args []any) []any {
+	if len(args) == 1 {
+		if _, ok := args[0].(*messageLog); ok {
+			return args
+		}
+	}
+
+	return append([]any{l.prefix}, args...)
+}
+
+// messageLogValue returns the size of message, followed by the message truncated to
+// Config.LogBodyMaxBytes when Config.LogMessageBody is set.
+func messageLogValue(conf *Config, message []byte) string {
+	if !conf.LogMessageBody {
+		return fmt.Sprintf("%d bytes", len(message))
+	}
+
+	limit := conf.LogBodyMaxBytes
+	if limit <= 0 {
+		limit = defaultLogBodyMaxBytes
+	}
+
+	if len(message) <= limit {
+		return string(message)
+	}
+
+	return fmt.Sprintf("%s... (%d bytes)", message[:limit], len(message))
+}
@@ -0,0 +1,196 @@
+package nats
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+	"gofr.dev/pkg/gofr/testutil"
+)
+
+func TestMessageLogValue(t *testing.T) {
+	message := []byte(`{"card":"4111111111111111"}`)
+
+	testCases := []struct {
+		desc     string
+		config   *Config
+		expected string
+	}{
+		{desc: "body disabled", config: &Config{}, expected: "27 bytes"},
+		{desc: "body enabled", config: &Config{LogMessageBody: true}, expected: `{"card":"4111111111111111"}`},
+		{desc: "body truncated", config: &Config{LogMessageBody: true, LogBodyMaxBytes: 8}, expected: `{"card":... (27 bytes)`},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.expected, messageLogValue(tc.config, message), "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNATSClient_Publish_LogsMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	ctx := context.Background()
+	secret := strings.Repeat("secret", 10)
+
+	mockConnManager.EXPECT().Publish(ctx, "payments", []byte(secret), nil).Return(nil).Times(2)
+
+	testCases := []struct {
+		desc     string
+		config   *Config
+		contains string
+	}{
+		{desc: "body absent by default", config: &Config{}, contains: "60 bytes"},
+		{desc: "body truncated when enabled", config: &Config{LogMessageBody: true, LogBodyMaxBytes: 12},
+			contains: "secretsecret... (60 bytes)"},
+	}
+
+	for i, tc := range testCases {
+		out := testutil.StdoutOutputForFunc(func() {
+			client := &Client{connManager: mockConnManager, Config: tc.config, logger: logging.NewMockLogger(logging.DEBUG)}
+
+			require.NoError(t, client.Publish(ctx, "payments", []byte(secret)), "TEST[%d], Failed.\n%s", i, tc.desc)
+		})
+
+		assert.Contains(t, out, "PUB", "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.Contains(t, out, tc.contains, "TEST[%d], Failed.\n%s", i, tc.desc)
+		assert.NotContains(t, out, secret, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNATSClient_Publish_LogsLoggerName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().Publish(ctx, "orders", []byte("order"), nil).Return(nil).Times(2)
+
+	out := testutil.StdoutOutputForFunc(func() {
+		for _, cfg := range []*Config{{}, {LoggerName: "orders-nats"}} {
+			client := &Client{connManager: mockConnManager, Config: cfg}
+			client.UseLogger(logging.NewMockLogger(logging.DEBUG))
+
+			require.NoError(t, client.Publish(ctx, "orders", []byte("order")))
+
+			client.PauseSubscription("orders")
+		}
+	})
+
+	// the logger name follows the fields of the pubsub log
+	assert.Contains(t, out, "PUB")
+	assert.Contains(t, out, "} nats}")
+	assert.Contains(t, out, "} orders-nats}")
+
+	// and prefixes every other log line
+	assert.Contains(t, out, "[nats] paused subscription to orders")
+	assert.Contains(t, out, "[orders-nats] paused subscription to orders")
+}
+
+func TestNATSClient_Publish_LogsElapsedTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	clk := newFakeClock()
+	ctx := context.Background()
+
+	// the time taken is measured on the client's clock
+	mockConnManager.EXPECT().Publish(ctx, "orders", []byte("order"), nil).
+		DoAndReturn(func(context.Context, string, []byte, Metrics) error {
+			clk.Advance(5 * time.Millisecond)
+
+			return nil
+		})
+
+	out := testutil.StdoutOutputForFunc(func() {
+		client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG), clock: clk}
+
+		require.NoError(t, client.Publish(ctx, "orders", []byte("order")))
+	})
+
+	assert.Contains(t, out, "NATS 5000} nats}")
+}
+
+func TestNATSClient_CorrelationID_RoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+
+	// the context of a GoFr request carries its trace, whose ID serves as the correlation ID
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+
+	var published nats.Header
+
+	mockConnManager.EXPECT().PublishWithHeaders(gomock.Any(), "orders.created", []byte("order"), gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, _ string, _ []byte, headers nats.Header, _ Metrics) error {
+			published = headers
+
+			return nil
+		})
+
+	pubOut := testutil.StdoutOutputForFunc(func() {
+		client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+
+		require.NoError(t, client.Publish(ctx, "orders.created", []byte("order")))
+	})
+
+	assert.Equal(t, traceID.String(), published.Get("X-Correlation-ID"))
+	assert.Contains(t, pubOut, traceID.String())
+
+	// the consumer reads the correlation ID back from the header
+	mockMsg := NewMockMsg(ctrl)
+	mockMsg.EXPECT().Headers().Return(published)
+	mockMsg.EXPECT().Data().Return([]byte("order"))
+	mockMsg.EXPECT().Subject().Return("orders.created")
+
+	received, err := newPubSubMessage(mockMsg, &Config{})
+	require.NoError(t, err)
+	assert.Equal(t, traceID.String(), messageCorrelationID(received))
+
+	mockConnManager.EXPECT().JetStream().Return(NewMockJetStream(ctrl), nil)
+	mockSubManager.EXPECT().Subscribe(gomock.Any(), "orders.created", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(received, nil)
+
+	subOut := testutil.StdoutOutputForFunc(func() {
+		client := &Client{connManager: mockConnManager, subManager: mockSubManager, Config: &Config{Consumer: "orders-consumer"},
+			logger: logging.NewMockLogger(logging.DEBUG)}
+
+		_, err := client.Subscribe(context.Background(), "orders.created")
+		require.NoError(t, err)
+	})
+
+	assert.Contains(t, subOut, "SUB")
+	assert.Contains(t, subOut, traceID.String())
+}
+
+func TestInjectCorrelationID(t *testing.T) {
+	// without a trace there is no correlation ID to propagate
+	assert.Nil(t, injectCorrelationID(context.Background(), nil))
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	}))
+
+	// a correlation ID set by the caller is kept
+	headers := injectCorrelationID(ctx, nats.Header{"X-Correlation-ID": []string{"upstream"}})
+	assert.Equal(t, "upstream", headers.Get("X-Correlation-ID"))
+}
@@ -1,10 +1,136 @@
 package nats
 
-import "context"
+import (
+	"context"
+	"slices"
+	"sync"
+)
 
 //go:generate mockgen -destination=mock_metrics.go -package=nats -source=./metrics.go
 
 // Metrics represents the metrics interface.
 type Metrics interface {
+	NewCounter(name, desc string)
+
 	IncrementCounter(ctx context.Context, name string, labels ...string)
+
+	NewHistogram(name, desc string, buckets ...float64)
+
+	RecordHistogram(ctx context.Context, name string, value float64, labels ...string)
+
+	NewGauge(name, desc string)
+
+	SetGauge(name string, value float64, labels ...string)
+}
+
+const (
+	publishDurationMetric        = "app_pubsub_publish_duration_seconds"
+	messageBytesMetric           = "app_pubsub_message_bytes"
+	dlqCountMetric               = "app_pubsub_dlq_total_count"
+	objectStoreBytesMetric       = "app_nats_objectstore_bytes"
+	reconnectCountMetric         = "app_nats_reconnect_total_count"
+	disconnectCountMetric        = "app_nats_disconnect_total_count"
+	publishValidationErrorMetric = "app_pubsub_publish_validation_error_count"
+	consumerPendingMetric        = "app_pubsub_consumer_pending"
+	publishRetryCountMetric      = "app_pubsub_publish_retry_count"
+	fetchTimeoutCountMetric      = "app_pubsub_fetch_timeout_count"
+	fetchErrorCountMetric        = "app_pubsub_fetch_error_count"
+	bufferDepthMetric            = "app_pubsub_buffer_depth"
+	rttMetric                    = "app_nats_rtt_seconds"
+	localDedupCountMetric        = "app_pubsub_local_dedup_count"
+	coreFallbackCountMetric      = "app_pubsub_core_fallback_count"
+	slowHandlerCountMetric       = "app_pubsub_slow_handler_count"
+	activeSubscriptionsMetric    = "app_pubsub_active_subscriptions"
+	circuitStateMetric           = "app_pubsub_circuit_state"
+	rateLimitedCountMetric       = "app_pubsub_rate_limited_count"
+	filteredCountMetric          = "app_pubsub_filtered_count"
+	poisonSuspectCountMetric     = "app_pubsub_poison_suspect_count"
+	requestTotalCountMetric      = "app_pubsub_request_total_count"
+	requestSuccessCountMetric    = "app_pubsub_request_success_count"
+)
+
+// registerMetrics registers the metrics recorded by the client in addition to the common pubsub counters.
+func registerMetrics(metrics Metrics, conf *Config) {
+	metrics.NewCounter(dlqCountMetric, "Number of messages moved to the dead letter queue.")
+	metrics.NewCounter(reconnectCountMetric, "Number of reconnections to the NATS server.")
+	metrics.NewCounter(disconnectCountMetric, "Number of disconnections from the NATS server.")
+	metrics.NewCounter(publishValidationErrorMetric, "Number of messages rejected by the publish validator.")
+	metrics.NewCounter(publishRetryCountMetric, "Number of publish retries after a transient failure.")
+	metrics.NewCounter(fetchTimeoutCountMetric, "Number of fetches that returned no messages before the max wait elapsed.")
+	metrics.NewCounter(fetchErrorCountMetric, "Number of fetches that failed.")
+	metrics.NewCounter(localDedupCountMetric, "Number of publishes skipped because the message ID was published recently.")
+	metrics.NewCounter(coreFallbackCountMetric, "Number of messages published over core NATS because JetStream was unavailable.")
+	metrics.NewCounter(slowHandlerCountMetric, "Number of handler calls that took longer than the slow handler threshold.")
+	metrics.NewCounter(rateLimitedCountMetric, "Number of publishes that exceeded the publish rate limit.")
+	metrics.NewCounter(filteredCountMetric, "Number of received messages dropped by the subscribe filter.")
+	metrics.NewCounter(poisonSuspectCountMetric, "Number of messages delivered as often as the poison attempt threshold.")
+	metrics.NewCounter(requestTotalCountMetric, "Number of requests sent with Request and RequestWithHeaders.")
+	metrics.NewCounter(requestSuccessCountMetric, "Number of requests that received a reply.")
+	metrics.NewGauge(consumerPendingMetric, "Number of stream messages not yet delivered to the consumer.")
+	metrics.NewGauge(rttMetric, "Round trip time to the NATS server in seconds.")
+	metrics.NewGauge(bufferDepthMetric, "Number of fetched messages waiting to be returned by Subscribe.")
+	metrics.NewGauge(activeSubscriptionsMetric, "Number of subscriptions the client currently holds.")
+	metrics.NewGauge(circuitStateMetric, "State of the publish circuit breaker: 0 closed, 1 open, 2 half-open.")
+
+	publishBuckets := []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+	metrics.NewHistogram(publishDurationMetric, "Time taken for a published message to be acknowledged in seconds.", publishBuckets...)
+
+	metrics.NewHistogram(messageBytesMetric, "Size of published and received messages in bytes.", messageBuckets(conf)...)
+
+	objectBuckets := []float64{1024, 16384, 262144, 4194304, 67108864, 1073741824}
+	metrics.NewHistogram(objectStoreBytesMetric, "Size of objects written to and read from the object store in bytes.", objectBuckets...)
+}
+
+// subscriptionCount tracks the subscriptions held by a client, made with either Subscribe or
+// SubscribeWithHandler, and reports their number as a gauge. A nil subscriptionCount tracks nothing.
+type subscriptionCount struct {
+	metrics Metrics
+
+	mu    sync.Mutex
+	count int
+}
+
+func newSubscriptionCount(metrics Metrics) *subscriptionCount {
+	return &subscriptionCount{metrics: metrics}
+}
+
+// add changes the number of subscriptions by delta and updates the gauge.
+func (s *subscriptionCount) add(delta int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count += delta
+
+	if s.metrics != nil {
+		s.metrics.SetGauge(activeSubscriptionsMetric, float64(s.count))
+	}
+}
+
+// streamLabels returns labels preceded by the stream label, unless Config.MetricStreamLabel disables it
+// or Config.MetricStreamAllowlist does not name the stream.
+func streamLabels(conf *Config, stream string, labels ...string) []string {
+	if conf != nil {
+		if conf.MetricStreamLabel != nil && !*conf.MetricStreamLabel {
+			return labels
+		}
+
+		if len(conf.MetricStreamAllowlist) > 0 && !slices.Contains(conf.MetricStreamAllowlist, stream) {
+			return labels
+		}
+	}
+
+	return append([]string{"stream", stream}, labels...)
+}
+
+// messageBuckets returns the configured message size buckets, falling back to powers of 4 from 64B to 1MB.
+func messageBuckets(conf *Config) []float64 {
+	if len(conf.MetricBuckets) > 0 {
+		return conf.MetricBuckets
+	}
+
+	return []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576}
 }
@@ -0,0 +1,61 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStreamLabels(t *testing.T) {
+	disabled := false
+
+	testCases := []struct {
+		desc     string
+		conf     *Config
+		stream   string
+		expected []string
+	}{
+		{desc: "default", conf: &Config{}, stream: "orders", expected: []string{"stream", "orders", "direction", "publish"}},
+		{desc: "no config", stream: "orders", expected: []string{"stream", "orders", "direction", "publish"}},
+		{desc: "disabled", conf: &Config{MetricStreamLabel: &disabled}, stream: "orders", expected: []string{"direction", "publish"}},
+		{
+			desc:     "allowlisted",
+			conf:     &Config{MetricStreamAllowlist: []string{"orders"}},
+			stream:   "orders",
+			expected: []string{"stream", "orders", "direction", "publish"},
+		},
+		{
+			desc:     "not allowlisted",
+			conf:     &Config{MetricStreamAllowlist: []string{"orders"}},
+			stream:   "tenant-42",
+			expected: []string{"direction", "publish"},
+		},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.expected, streamLabels(tc.conf, tc.stream, "direction", "publish"), "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestConnectionManager_recordPublishMetrics_StreamLabelDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	disabled := false
+
+	cm := &ConnectionManager{config: &Config{
+		Stream:            StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}},
+		MetricStreamLabel: &disabled,
+	}}
+	ctx := context.Background()
+
+	// the histograms are recorded without any stream label
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_publish_duration_seconds", gomock.Any())
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_pubsub_message_bytes", float64(5), "direction", "publish")
+
+	cm.recordPublishMetrics(ctx, "orders.created", []byte("order"), time.Now(), mockMetrics)
+}
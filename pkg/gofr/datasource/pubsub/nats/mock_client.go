@@ -12,6 +12,7 @@ package nats
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	nats "github.com/nats-io/nats.go"
 	jetstream "github.com/nats-io/nats.go/jetstream"
@@ -55,6 +56,48 @@ func (mr *MockConnInterfaceMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConnInterface)(nil).Close))
 }
 
+// ConnectedClusterName mocks base method.
+func (m *MockConnInterface) ConnectedClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectedClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ConnectedClusterName indicates an expected call of ConnectedClusterName.
+func (mr *MockConnInterfaceMockRecorder) ConnectedClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectedClusterName", reflect.TypeOf((*MockConnInterface)(nil).ConnectedClusterName))
+}
+
+// Drain mocks base method.
+func (m *MockConnInterface) Drain() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockConnInterfaceMockRecorder) Drain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockConnInterface)(nil).Drain))
+}
+
+// FlushWithContext mocks base method.
+func (m *MockConnInterface) FlushWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushWithContext indicates an expected call of FlushWithContext.
+func (mr *MockConnInterfaceMockRecorder) FlushWithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushWithContext", reflect.TypeOf((*MockConnInterface)(nil).FlushWithContext), ctx)
+}
+
 // JetStream mocks base method.
 func (m *MockConnInterface) JetStream() (jetstream.JetStream, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +113,20 @@ func (mr *MockConnInterfaceMockRecorder) JetStream() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "jStream", reflect.TypeOf((*MockConnInterface)(nil).JetStream))
 }
 
+// MaxPayload mocks base method.
+func (m *MockConnInterface) MaxPayload() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxPayload")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// MaxPayload indicates an expected call of MaxPayload.
+func (mr *MockConnInterfaceMockRecorder) MaxPayload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxPayload", reflect.TypeOf((*MockConnInterface)(nil).MaxPayload))
+}
+
 // NATSConn mocks base method.
 func (m *MockConnInterface) NATSConn() *nats.Conn {
 	m.ctrl.T.Helper()
@@ -84,6 +141,65 @@ func (mr *MockConnInterfaceMockRecorder) NATSConn() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NATSConn", reflect.TypeOf((*MockConnInterface)(nil).NATSConn))
 }
 
+// PublishMsg mocks base method.
+func (m *MockConnInterface) PublishMsg(msg *nats.Msg) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishMsg", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishMsg indicates an expected call of PublishMsg.
+func (mr *MockConnInterfaceMockRecorder) PublishMsg(msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishMsg", reflect.TypeOf((*MockConnInterface)(nil).PublishMsg), msg)
+}
+
+// RTT mocks base method.
+func (m *MockConnInterface) RTT() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RTT")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RTT indicates an expected call of RTT.
+func (mr *MockConnInterfaceMockRecorder) RTT() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RTT", reflect.TypeOf((*MockConnInterface)(nil).RTT))
+}
+
+// RequestMsgWithContext mocks base method.
+func (m *MockConnInterface) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMsgWithContext", ctx, msg)
+	ret0, _ := ret[0].(*nats.Msg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestMsgWithContext indicates an expected call of RequestMsgWithContext.
+func (mr *MockConnInterfaceMockRecorder) RequestMsgWithContext(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMsgWithContext", reflect.TypeOf((*MockConnInterface)(nil).RequestMsgWithContext), ctx, msg)
+}
+
+// RequestWithContext mocks base method.
+func (m *MockConnInterface) RequestWithContext(ctx context.Context, subject string, data []byte) (*nats.Msg, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestWithContext", ctx, subject, data)
+	ret0, _ := ret[0].(*nats.Msg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestWithContext indicates an expected call of RequestWithContext.
+func (mr *MockConnInterfaceMockRecorder) RequestWithContext(ctx, subject, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestWithContext", reflect.TypeOf((*MockConnInterface)(nil).RequestWithContext), ctx, subject, data)
+}
+
 // Status mocks base method.
 func (m *MockConnInterface) Status() nats.Status {
 	m.ctrl.T.Helper()
@@ -98,6 +214,21 @@ func (mr *MockConnInterfaceMockRecorder) Status() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockConnInterface)(nil).Status))
 }
 
+// Subscribe mocks base method.
+func (m *MockConnInterface) Subscribe(subject string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", subject, cb)
+	ret0, _ := ret[0].(*nats.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockConnInterfaceMockRecorder) Subscribe(subject, cb any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockConnInterface)(nil).Subscribe), subject, cb)
+}
+
 // MockNATSConnector is a mock of Connector interface.
 type MockNATSConnector struct {
 	ctrl     *gomock.Controller
@@ -216,6 +347,21 @@ func (mr *MockJetStreamClientMockRecorder) Close(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockJetStreamClient)(nil).Close), ctx)
 }
 
+// ConsumerInfo mocks base method.
+func (m *MockJetStreamClient) ConsumerInfo(ctx context.Context, stream, consumer string) (*jetstream.ConsumerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumerInfo", ctx, stream, consumer)
+	ret0, _ := ret[0].(*jetstream.ConsumerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumerInfo indicates an expected call of ConsumerInfo.
+func (mr *MockJetStreamClientMockRecorder) ConsumerInfo(ctx, stream, consumer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumerInfo", reflect.TypeOf((*MockJetStreamClient)(nil).ConsumerInfo), ctx, stream, consumer)
+}
+
 // CreateOrUpdateStream mocks base method.
 func (m *MockJetStreamClient) CreateOrUpdateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error) {
 	m.ctrl.T.Helper()
@@ -287,6 +433,25 @@ func (mr *MockJetStreamClientMockRecorder) Publish(ctx, subject, message any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockJetStreamClient)(nil).Publish), ctx, subject, message)
 }
 
+// PurgeStream mocks base method.
+func (m *MockJetStreamClient) PurgeStream(ctx context.Context, stream string, opts ...jetstream.StreamPurgeOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, stream}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeStream", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeStream indicates an expected call of PurgeStream.
+func (mr *MockJetStreamClientMockRecorder) PurgeStream(ctx, stream any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, stream}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeStream", reflect.TypeOf((*MockJetStreamClient)(nil).PurgeStream), varargs...)
+}
+
 // Subscribe mocks base method.
 func (m *MockJetStreamClient) Subscribe(ctx context.Context, subject string, handler messageHandler) error {
 	m.ctrl.T.Helper()
@@ -325,9 +490,11 @@ func (m *MockConnectionManagerInterface) EXPECT() *MockConnectionManagerInterfac
 }
 
 // Close mocks base method.
-func (m *MockConnectionManagerInterface) Close(ctx context.Context) {
+func (m *MockConnectionManagerInterface) Close(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Close", ctx)
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Close indicates an expected call of Close.
@@ -350,6 +517,34 @@ func (mr *MockConnectionManagerInterfaceMockRecorder) Connect() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockConnectionManagerInterface)(nil).Connect))
 }
 
+// Drain mocks base method.
+func (m *MockConnectionManagerInterface) Drain(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockConnectionManagerInterfaceMockRecorder) Drain(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockConnectionManagerInterface)(nil).Drain), ctx)
+}
+
+// Flush mocks base method.
+func (m *MockConnectionManagerInterface) Flush(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockConnectionManagerInterfaceMockRecorder) Flush(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockConnectionManagerInterface)(nil).Flush), ctx)
+}
+
 // Health mocks base method.
 func (m *MockConnectionManagerInterface) Health() datasource.Health {
 	m.ctrl.T.Helper()
@@ -393,6 +588,166 @@ func (mr *MockConnectionManagerInterfaceMockRecorder) Publish(ctx, subject, mess
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockConnectionManagerInterface)(nil).Publish), ctx, subject, message, metrics)
 }
 
+// PublishAck mocks base method.
+func (m *MockConnectionManagerInterface) PublishAck(ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) (*jetstream.PubAck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAck", ctx, subject, message, headers, metrics)
+	ret0, _ := ret[0].(*jetstream.PubAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishAck indicates an expected call of PublishAck.
+func (mr *MockConnectionManagerInterfaceMockRecorder) PublishAck(ctx, subject, message, headers, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAck", reflect.TypeOf((*MockConnectionManagerInterface)(nil).PublishAck), ctx, subject, message, headers, metrics)
+}
+
+// PublishAsync mocks base method.
+func (m *MockConnectionManagerInterface) PublishAsync(ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) (jetstream.PubAckFuture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAsync", ctx, subject, message, headers, metrics)
+	ret0, _ := ret[0].(jetstream.PubAckFuture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishAsync indicates an expected call of PublishAsync.
+func (mr *MockConnectionManagerInterfaceMockRecorder) PublishAsync(ctx, subject, message, headers, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAsync", reflect.TypeOf((*MockConnectionManagerInterface)(nil).PublishAsync), ctx, subject, message, headers, metrics)
+}
+
+// PublishAsyncComplete mocks base method.
+func (m *MockConnectionManagerInterface) PublishAsyncComplete(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAsyncComplete", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishAsyncComplete indicates an expected call of PublishAsyncComplete.
+func (mr *MockConnectionManagerInterfaceMockRecorder) PublishAsyncComplete(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAsyncComplete", reflect.TypeOf((*MockConnectionManagerInterface)(nil).PublishAsyncComplete), ctx)
+}
+
+// PublishBatch mocks base method.
+func (m *MockConnectionManagerInterface) PublishBatch(ctx context.Context, subject string, msgs [][]byte, metrics Metrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishBatch", ctx, subject, msgs, metrics)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishBatch indicates an expected call of PublishBatch.
+func (mr *MockConnectionManagerInterfaceMockRecorder) PublishBatch(ctx, subject, msgs, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishBatch", reflect.TypeOf((*MockConnectionManagerInterface)(nil).PublishBatch), ctx, subject, msgs, metrics)
+}
+
+// PublishReply mocks base method.
+func (m *MockConnectionManagerInterface) PublishReply(reply *nats.Msg) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishReply", reply)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishReply indicates an expected call of PublishReply.
+func (mr *MockConnectionManagerInterfaceMockRecorder) PublishReply(reply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishReply", reflect.TypeOf((*MockConnectionManagerInterface)(nil).PublishReply), reply)
+}
+
+// PublishWithHeaders mocks base method.
+func (m *MockConnectionManagerInterface) PublishWithHeaders(ctx context.Context, subject string, message []byte, headers nats.Header, metrics Metrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishWithHeaders", ctx, subject, message, headers, metrics)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishWithHeaders indicates an expected call of PublishWithHeaders.
+func (mr *MockConnectionManagerInterfaceMockRecorder) PublishWithHeaders(ctx, subject, message, headers, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishWithHeaders", reflect.TypeOf((*MockConnectionManagerInterface)(nil).PublishWithHeaders), ctx, subject, message, headers, metrics)
+}
+
+// RTT mocks base method.
+func (m *MockConnectionManagerInterface) RTT(ctx context.Context) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RTT", ctx)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RTT indicates an expected call of RTT.
+func (mr *MockConnectionManagerInterfaceMockRecorder) RTT(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RTT", reflect.TypeOf((*MockConnectionManagerInterface)(nil).RTT), ctx)
+}
+
+// Request mocks base method.
+func (m *MockConnectionManagerInterface) Request(ctx context.Context, subject string, data []byte, metrics Metrics) (*pubsub.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, subject, data, metrics)
+	ret0, _ := ret[0].(*pubsub.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockConnectionManagerInterfaceMockRecorder) Request(ctx, subject, data, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockConnectionManagerInterface)(nil).Request), ctx, subject, data, metrics)
+}
+
+// RequestWithHeaders mocks base method.
+func (m *MockConnectionManagerInterface) RequestWithHeaders(ctx context.Context, subject string, data []byte, headers nats.Header, metrics Metrics) (*pubsub.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestWithHeaders", ctx, subject, data, headers, metrics)
+	ret0, _ := ret[0].(*pubsub.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestWithHeaders indicates an expected call of RequestWithHeaders.
+func (mr *MockConnectionManagerInterfaceMockRecorder) RequestWithHeaders(ctx, subject, data, headers, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestWithHeaders", reflect.TypeOf((*MockConnectionManagerInterface)(nil).RequestWithHeaders), ctx, subject, data, headers, metrics)
+}
+
+// Status mocks base method.
+func (m *MockConnectionManagerInterface) Status() nats.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(nats.Status)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockConnectionManagerInterfaceMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockConnectionManagerInterface)(nil).Status))
+}
+
+// SubscribeRequests mocks base method.
+func (m *MockConnectionManagerInterface) SubscribeRequests(subject string, handler nats.MsgHandler) (*nats.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeRequests", subject, handler)
+	ret0, _ := ret[0].(*nats.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeRequests indicates an expected call of SubscribeRequests.
+func (mr *MockConnectionManagerInterfaceMockRecorder) SubscribeRequests(subject, handler any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeRequests", reflect.TypeOf((*MockConnectionManagerInterface)(nil).SubscribeRequests), subject, handler)
+}
+
 // MockSubscriptionManagerInterface is a mock of SubscriptionManagerInterface interface.
 type MockSubscriptionManagerInterface struct {
 	ctrl     *gomock.Controller
@@ -443,6 +798,20 @@ func (mr *MockSubscriptionManagerInterfaceMockRecorder) Subscribe(ctx, topic, js
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockSubscriptionManagerInterface)(nil).Subscribe), ctx, topic, js, cfg, logger, metrics)
 }
 
+// Unsubscribe mocks base method.
+func (m *MockSubscriptionManagerInterface) Unsubscribe(topic string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", topic)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockSubscriptionManagerInterfaceMockRecorder) Unsubscribe(topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockSubscriptionManagerInterface)(nil).Unsubscribe), topic)
+}
+
 // MockStreamManagerInterface is a mock of StreamManagerInterface interface.
 type MockStreamManagerInterface struct {
 	ctrl     *gomock.Controller
@@ -466,6 +835,21 @@ func (m *MockStreamManagerInterface) EXPECT() *MockStreamManagerInterfaceMockRec
 	return m.recorder
 }
 
+// ConsumerInfo mocks base method.
+func (m *MockStreamManagerInterface) ConsumerInfo(ctx context.Context, stream, consumer string) (*jetstream.ConsumerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumerInfo", ctx, stream, consumer)
+	ret0, _ := ret[0].(*jetstream.ConsumerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumerInfo indicates an expected call of ConsumerInfo.
+func (mr *MockStreamManagerInterfaceMockRecorder) ConsumerInfo(ctx, stream, consumer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumerInfo", reflect.TypeOf((*MockStreamManagerInterface)(nil).ConsumerInfo), ctx, stream, consumer)
+}
+
 // CreateOrUpdateStream mocks base method.
 func (m *MockStreamManagerInterface) CreateOrUpdateStream(ctx context.Context, cfg *jetstream.StreamConfig) (jetstream.Stream, error) {
 	m.ctrl.T.Helper()
@@ -508,3 +892,51 @@ func (mr *MockStreamManagerInterfaceMockRecorder) DeleteStream(ctx, name any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStream", reflect.TypeOf((*MockStreamManagerInterface)(nil).DeleteStream), ctx, name)
 }
+
+// PurgeStream mocks base method.
+func (m *MockStreamManagerInterface) PurgeStream(ctx context.Context, stream string, opts ...jetstream.StreamPurgeOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, stream}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeStream", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeStream indicates an expected call of PurgeStream.
+func (mr *MockStreamManagerInterfaceMockRecorder) PurgeStream(ctx, stream any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, stream}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeStream", reflect.TypeOf((*MockStreamManagerInterface)(nil).PurgeStream), varargs...)
+}
+
+// StreamNames mocks base method.
+func (m *MockStreamManagerInterface) StreamNames(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamNames", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamNames indicates an expected call of StreamNames.
+func (mr *MockStreamManagerInterfaceMockRecorder) StreamNames(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamNames", reflect.TypeOf((*MockStreamManagerInterface)(nil).StreamNames), ctx)
+}
+
+// UpdateStream mocks base method.
+func (m *MockStreamManagerInterface) UpdateStream(ctx context.Context, cfg StreamConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStream", ctx, cfg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStream indicates an expected call of UpdateStream.
+func (mr *MockStreamManagerInterfaceMockRecorder) UpdateStream(ctx, cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStream", reflect.TypeOf((*MockStreamManagerInterface)(nil).UpdateStream), ctx, cfg)
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/nats-io/nats.go/jetstream (interfaces: jStream,Stream,Consumer,Msg,MessageBatch)
+// Source: github.com/nats-io/nats.go/jetstream (interfaces: JetStream,Stream,Consumer,Msg,MessageBatch,PubAckFuture,KeyValue,KeyWatcher,KeyValueEntry,ObjectStore,ObjectResult)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mock_jetstream.go -package=nats github.com/nats-io/nats.go/jetstream jStream,Stream,Consumer,Msg,MessageBatch
+//	mockgen -destination=mock_jetstream.go -package=nats github.com/nats-io/nats.go/jetstream JetStream,Stream,Consumer,Msg,MessageBatch,PubAckFuture,KeyValue,KeyWatcher,KeyValueEntry,ObjectStore,ObjectResult
 //
 
 // Package nats is a generated GoMock package.
@@ -11,6 +11,7 @@ package nats
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
@@ -19,7 +20,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockJetStream is a mock of jStream interface.
+// MockJetStream is a mock of JetStream interface.
 type MockJetStream struct {
 	ctrl     *gomock.Controller
 	recorder *MockJetStreamMockRecorder
@@ -1265,3 +1266,935 @@ func (mr *MockMessageBatchMockRecorder) Messages() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Messages", reflect.TypeOf((*MockMessageBatch)(nil).Messages))
 }
+
+// MockPubAckFuture is a mock of PubAckFuture interface.
+type MockPubAckFuture struct {
+	ctrl     *gomock.Controller
+	recorder *MockPubAckFutureMockRecorder
+	isgomock struct{}
+}
+
+// MockPubAckFutureMockRecorder is the mock recorder for MockPubAckFuture.
+type MockPubAckFutureMockRecorder struct {
+	mock *MockPubAckFuture
+}
+
+// NewMockPubAckFuture creates a new mock instance.
+func NewMockPubAckFuture(ctrl *gomock.Controller) *MockPubAckFuture {
+	mock := &MockPubAckFuture{ctrl: ctrl}
+	mock.recorder = &MockPubAckFutureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPubAckFuture) EXPECT() *MockPubAckFutureMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method.
+func (m *MockPubAckFuture) Err() <-chan error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(<-chan error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockPubAckFutureMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockPubAckFuture)(nil).Err))
+}
+
+// Msg mocks base method.
+func (m *MockPubAckFuture) Msg() *nats.Msg {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Msg")
+	ret0, _ := ret[0].(*nats.Msg)
+	return ret0
+}
+
+// Msg indicates an expected call of Msg.
+func (mr *MockPubAckFutureMockRecorder) Msg() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Msg", reflect.TypeOf((*MockPubAckFuture)(nil).Msg))
+}
+
+// Ok mocks base method.
+func (m *MockPubAckFuture) Ok() <-chan *jetstream.PubAck {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ok")
+	ret0, _ := ret[0].(<-chan *jetstream.PubAck)
+	return ret0
+}
+
+// Ok indicates an expected call of Ok.
+func (mr *MockPubAckFutureMockRecorder) Ok() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ok", reflect.TypeOf((*MockPubAckFuture)(nil).Ok))
+}
+
+// MockKeyValue is a mock of KeyValue interface.
+type MockKeyValue struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyValueMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyValueMockRecorder is the mock recorder for MockKeyValue.
+type MockKeyValueMockRecorder struct {
+	mock *MockKeyValue
+}
+
+// NewMockKeyValue creates a new mock instance.
+func NewMockKeyValue(ctrl *gomock.Controller) *MockKeyValue {
+	mock := &MockKeyValue{ctrl: ctrl}
+	mock.recorder = &MockKeyValueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyValue) EXPECT() *MockKeyValueMockRecorder {
+	return m.recorder
+}
+
+// Bucket mocks base method.
+func (m *MockKeyValue) Bucket() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bucket")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Bucket indicates an expected call of Bucket.
+func (mr *MockKeyValueMockRecorder) Bucket() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bucket", reflect.TypeOf((*MockKeyValue)(nil).Bucket))
+}
+
+// Create mocks base method.
+func (m *MockKeyValue) Create(ctx context.Context, key string, value []byte) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key, value)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockKeyValueMockRecorder) Create(ctx, key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKeyValue)(nil).Create), ctx, key, value)
+}
+
+// Delete mocks base method.
+func (m *MockKeyValue) Delete(ctx context.Context, key string, opts ...jetstream.KVDeleteOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockKeyValueMockRecorder) Delete(ctx, key any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockKeyValue)(nil).Delete), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockKeyValue) Get(ctx context.Context, key string) (jetstream.KeyValueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(jetstream.KeyValueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockKeyValueMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeyValue)(nil).Get), ctx, key)
+}
+
+// GetRevision mocks base method.
+func (m *MockKeyValue) GetRevision(ctx context.Context, key string, revision uint64) (jetstream.KeyValueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, key, revision)
+	ret0, _ := ret[0].(jetstream.KeyValueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockKeyValueMockRecorder) GetRevision(ctx, key, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockKeyValue)(nil).GetRevision), ctx, key, revision)
+}
+
+// History mocks base method.
+func (m *MockKeyValue) History(ctx context.Context, key string, opts ...jetstream.WatchOpt) ([]jetstream.KeyValueEntry, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "History", varargs...)
+	ret0, _ := ret[0].([]jetstream.KeyValueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockKeyValueMockRecorder) History(ctx, key any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockKeyValue)(nil).History), varargs...)
+}
+
+// Keys mocks base method.
+func (m *MockKeyValue) Keys(ctx context.Context, opts ...jetstream.WatchOpt) ([]string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Keys", varargs...)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockKeyValueMockRecorder) Keys(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockKeyValue)(nil).Keys), varargs...)
+}
+
+// ListKeys mocks base method.
+func (m *MockKeyValue) ListKeys(ctx context.Context, opts ...jetstream.WatchOpt) (jetstream.KeyLister, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListKeys", varargs...)
+	ret0, _ := ret[0].(jetstream.KeyLister)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeys indicates an expected call of ListKeys.
+func (mr *MockKeyValueMockRecorder) ListKeys(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockKeyValue)(nil).ListKeys), varargs...)
+}
+
+// Purge mocks base method.
+func (m *MockKeyValue) Purge(ctx context.Context, key string, opts ...jetstream.KVDeleteOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Purge", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockKeyValueMockRecorder) Purge(ctx, key any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockKeyValue)(nil).Purge), varargs...)
+}
+
+// PurgeDeletes mocks base method.
+func (m *MockKeyValue) PurgeDeletes(ctx context.Context, opts ...jetstream.KVPurgeOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeDeletes", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeletes indicates an expected call of PurgeDeletes.
+func (mr *MockKeyValueMockRecorder) PurgeDeletes(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletes", reflect.TypeOf((*MockKeyValue)(nil).PurgeDeletes), varargs...)
+}
+
+// Put mocks base method.
+func (m *MockKeyValue) Put(ctx context.Context, key string, value []byte) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, value)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockKeyValueMockRecorder) Put(ctx, key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockKeyValue)(nil).Put), ctx, key, value)
+}
+
+// PutString mocks base method.
+func (m *MockKeyValue) PutString(ctx context.Context, key, value string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutString", ctx, key, value)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutString indicates an expected call of PutString.
+func (mr *MockKeyValueMockRecorder) PutString(ctx, key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutString", reflect.TypeOf((*MockKeyValue)(nil).PutString), ctx, key, value)
+}
+
+// Status mocks base method.
+func (m *MockKeyValue) Status(ctx context.Context) (jetstream.KeyValueStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(jetstream.KeyValueStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockKeyValueMockRecorder) Status(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockKeyValue)(nil).Status), ctx)
+}
+
+// Update mocks base method.
+func (m *MockKeyValue) Update(ctx context.Context, key string, value []byte, revision uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, key, value, revision)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockKeyValueMockRecorder) Update(ctx, key, value, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockKeyValue)(nil).Update), ctx, key, value, revision)
+}
+
+// Watch mocks base method.
+func (m *MockKeyValue) Watch(ctx context.Context, keys string, opts ...jetstream.WatchOpt) (jetstream.KeyWatcher, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, keys}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(jetstream.KeyWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockKeyValueMockRecorder) Watch(ctx, keys any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, keys}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockKeyValue)(nil).Watch), varargs...)
+}
+
+// WatchAll mocks base method.
+func (m *MockKeyValue) WatchAll(ctx context.Context, opts ...jetstream.WatchOpt) (jetstream.KeyWatcher, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchAll", varargs...)
+	ret0, _ := ret[0].(jetstream.KeyWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchAll indicates an expected call of WatchAll.
+func (mr *MockKeyValueMockRecorder) WatchAll(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchAll", reflect.TypeOf((*MockKeyValue)(nil).WatchAll), varargs...)
+}
+
+// MockKeyWatcher is a mock of KeyWatcher interface.
+type MockKeyWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyWatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyWatcherMockRecorder is the mock recorder for MockKeyWatcher.
+type MockKeyWatcherMockRecorder struct {
+	mock *MockKeyWatcher
+}
+
+// NewMockKeyWatcher creates a new mock instance.
+func NewMockKeyWatcher(ctrl *gomock.Controller) *MockKeyWatcher {
+	mock := &MockKeyWatcher{ctrl: ctrl}
+	mock.recorder = &MockKeyWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyWatcher) EXPECT() *MockKeyWatcherMockRecorder {
+	return m.recorder
+}
+
+// Stop mocks base method.
+func (m *MockKeyWatcher) Stop() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockKeyWatcherMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockKeyWatcher)(nil).Stop))
+}
+
+// Updates mocks base method.
+func (m *MockKeyWatcher) Updates() <-chan jetstream.KeyValueEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Updates")
+	ret0, _ := ret[0].(<-chan jetstream.KeyValueEntry)
+	return ret0
+}
+
+// Updates indicates an expected call of Updates.
+func (mr *MockKeyWatcherMockRecorder) Updates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Updates", reflect.TypeOf((*MockKeyWatcher)(nil).Updates))
+}
+
+// MockKeyValueEntry is a mock of KeyValueEntry interface.
+type MockKeyValueEntry struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyValueEntryMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyValueEntryMockRecorder is the mock recorder for MockKeyValueEntry.
+type MockKeyValueEntryMockRecorder struct {
+	mock *MockKeyValueEntry
+}
+
+// NewMockKeyValueEntry creates a new mock instance.
+func NewMockKeyValueEntry(ctrl *gomock.Controller) *MockKeyValueEntry {
+	mock := &MockKeyValueEntry{ctrl: ctrl}
+	mock.recorder = &MockKeyValueEntryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyValueEntry) EXPECT() *MockKeyValueEntryMockRecorder {
+	return m.recorder
+}
+
+// Bucket mocks base method.
+func (m *MockKeyValueEntry) Bucket() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bucket")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Bucket indicates an expected call of Bucket.
+func (mr *MockKeyValueEntryMockRecorder) Bucket() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bucket", reflect.TypeOf((*MockKeyValueEntry)(nil).Bucket))
+}
+
+// Created mocks base method.
+func (m *MockKeyValueEntry) Created() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Created")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// Created indicates an expected call of Created.
+func (mr *MockKeyValueEntryMockRecorder) Created() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Created", reflect.TypeOf((*MockKeyValueEntry)(nil).Created))
+}
+
+// Delta mocks base method.
+func (m *MockKeyValueEntry) Delta() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delta")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// Delta indicates an expected call of Delta.
+func (mr *MockKeyValueEntryMockRecorder) Delta() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delta", reflect.TypeOf((*MockKeyValueEntry)(nil).Delta))
+}
+
+// Key mocks base method.
+func (m *MockKeyValueEntry) Key() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockKeyValueEntryMockRecorder) Key() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockKeyValueEntry)(nil).Key))
+}
+
+// Operation mocks base method.
+func (m *MockKeyValueEntry) Operation() jetstream.KeyValueOp {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Operation")
+	ret0, _ := ret[0].(jetstream.KeyValueOp)
+	return ret0
+}
+
+// Operation indicates an expected call of Operation.
+func (mr *MockKeyValueEntryMockRecorder) Operation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Operation", reflect.TypeOf((*MockKeyValueEntry)(nil).Operation))
+}
+
+// Revision mocks base method.
+func (m *MockKeyValueEntry) Revision() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// Revision indicates an expected call of Revision.
+func (mr *MockKeyValueEntryMockRecorder) Revision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockKeyValueEntry)(nil).Revision))
+}
+
+// Value mocks base method.
+func (m *MockKeyValueEntry) Value() []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Value")
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// Value indicates an expected call of Value.
+func (mr *MockKeyValueEntryMockRecorder) Value() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockKeyValueEntry)(nil).Value))
+}
+
+// MockObjectStore is a mock of ObjectStore interface.
+type MockObjectStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockObjectStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockObjectStoreMockRecorder is the mock recorder for MockObjectStore.
+type MockObjectStoreMockRecorder struct {
+	mock *MockObjectStore
+}
+
+// NewMockObjectStore creates a new mock instance.
+func NewMockObjectStore(ctrl *gomock.Controller) *MockObjectStore {
+	mock := &MockObjectStore{ctrl: ctrl}
+	mock.recorder = &MockObjectStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockObjectStore) EXPECT() *MockObjectStoreMockRecorder {
+	return m.recorder
+}
+
+// AddBucketLink mocks base method.
+func (m *MockObjectStore) AddBucketLink(ctx context.Context, name string, bucket jetstream.ObjectStore) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBucketLink", ctx, name, bucket)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBucketLink indicates an expected call of AddBucketLink.
+func (mr *MockObjectStoreMockRecorder) AddBucketLink(ctx, name, bucket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBucketLink", reflect.TypeOf((*MockObjectStore)(nil).AddBucketLink), ctx, name, bucket)
+}
+
+// AddLink mocks base method.
+func (m *MockObjectStore) AddLink(ctx context.Context, name string, obj *jetstream.ObjectInfo) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLink", ctx, name, obj)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLink indicates an expected call of AddLink.
+func (mr *MockObjectStoreMockRecorder) AddLink(ctx, name, obj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLink", reflect.TypeOf((*MockObjectStore)(nil).AddLink), ctx, name, obj)
+}
+
+// Delete mocks base method.
+func (m *MockObjectStore) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockObjectStoreMockRecorder) Delete(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockObjectStore)(nil).Delete), ctx, name)
+}
+
+// Get mocks base method.
+func (m *MockObjectStore) Get(ctx context.Context, name string, opts ...jetstream.GetObjectOpt) (jetstream.ObjectResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
+	ret0, _ := ret[0].(jetstream.ObjectResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockObjectStoreMockRecorder) Get(ctx, name any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockObjectStore)(nil).Get), varargs...)
+}
+
+// GetBytes mocks base method.
+func (m *MockObjectStore) GetBytes(ctx context.Context, name string, opts ...jetstream.GetObjectOpt) ([]byte, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBytes", varargs...)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBytes indicates an expected call of GetBytes.
+func (mr *MockObjectStoreMockRecorder) GetBytes(ctx, name any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBytes", reflect.TypeOf((*MockObjectStore)(nil).GetBytes), varargs...)
+}
+
+// GetFile mocks base method.
+func (m *MockObjectStore) GetFile(ctx context.Context, name, file string, opts ...jetstream.GetObjectOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name, file}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFile", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockObjectStoreMockRecorder) GetFile(ctx, name, file any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name, file}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockObjectStore)(nil).GetFile), varargs...)
+}
+
+// GetInfo mocks base method.
+func (m *MockObjectStore) GetInfo(ctx context.Context, name string, opts ...jetstream.GetObjectInfoOpt) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetInfo", varargs...)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfo indicates an expected call of GetInfo.
+func (mr *MockObjectStoreMockRecorder) GetInfo(ctx, name any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockObjectStore)(nil).GetInfo), varargs...)
+}
+
+// GetString mocks base method.
+func (m *MockObjectStore) GetString(ctx context.Context, name string, opts ...jetstream.GetObjectOpt) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetString", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetString indicates an expected call of GetString.
+func (mr *MockObjectStoreMockRecorder) GetString(ctx, name any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetString", reflect.TypeOf((*MockObjectStore)(nil).GetString), varargs...)
+}
+
+// List mocks base method.
+func (m *MockObjectStore) List(ctx context.Context, opts ...jetstream.ListObjectsOpt) ([]*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "List", varargs...)
+	ret0, _ := ret[0].([]*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockObjectStoreMockRecorder) List(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockObjectStore)(nil).List), varargs...)
+}
+
+// Put mocks base method.
+func (m *MockObjectStore) Put(ctx context.Context, obj jetstream.ObjectMeta, reader io.Reader) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, obj, reader)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockObjectStoreMockRecorder) Put(ctx, obj, reader any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockObjectStore)(nil).Put), ctx, obj, reader)
+}
+
+// PutBytes mocks base method.
+func (m *MockObjectStore) PutBytes(ctx context.Context, name string, data []byte) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutBytes", ctx, name, data)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutBytes indicates an expected call of PutBytes.
+func (mr *MockObjectStoreMockRecorder) PutBytes(ctx, name, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutBytes", reflect.TypeOf((*MockObjectStore)(nil).PutBytes), ctx, name, data)
+}
+
+// PutFile mocks base method.
+func (m *MockObjectStore) PutFile(ctx context.Context, file string) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutFile", ctx, file)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutFile indicates an expected call of PutFile.
+func (mr *MockObjectStoreMockRecorder) PutFile(ctx, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutFile", reflect.TypeOf((*MockObjectStore)(nil).PutFile), ctx, file)
+}
+
+// PutString mocks base method.
+func (m *MockObjectStore) PutString(ctx context.Context, name, data string) (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutString", ctx, name, data)
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutString indicates an expected call of PutString.
+func (mr *MockObjectStoreMockRecorder) PutString(ctx, name, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutString", reflect.TypeOf((*MockObjectStore)(nil).PutString), ctx, name, data)
+}
+
+// Seal mocks base method.
+func (m *MockObjectStore) Seal(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seal", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Seal indicates an expected call of Seal.
+func (mr *MockObjectStoreMockRecorder) Seal(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seal", reflect.TypeOf((*MockObjectStore)(nil).Seal), ctx)
+}
+
+// Status mocks base method.
+func (m *MockObjectStore) Status(ctx context.Context) (jetstream.ObjectStoreStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(jetstream.ObjectStoreStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockObjectStoreMockRecorder) Status(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockObjectStore)(nil).Status), ctx)
+}
+
+// UpdateMeta mocks base method.
+func (m *MockObjectStore) UpdateMeta(ctx context.Context, name string, meta jetstream.ObjectMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMeta", ctx, name, meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMeta indicates an expected call of UpdateMeta.
+func (mr *MockObjectStoreMockRecorder) UpdateMeta(ctx, name, meta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMeta", reflect.TypeOf((*MockObjectStore)(nil).UpdateMeta), ctx, name, meta)
+}
+
+// Watch mocks base method.
+func (m *MockObjectStore) Watch(ctx context.Context, opts ...jetstream.WatchOpt) (jetstream.ObjectWatcher, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(jetstream.ObjectWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockObjectStoreMockRecorder) Watch(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockObjectStore)(nil).Watch), varargs...)
+}
+
+// MockObjectResult is a mock of ObjectResult interface.
+type MockObjectResult struct {
+	ctrl     *gomock.Controller
+	recorder *MockObjectResultMockRecorder
+	isgomock struct{}
+}
+
+// MockObjectResultMockRecorder is the mock recorder for MockObjectResult.
+type MockObjectResultMockRecorder struct {
+	mock *MockObjectResult
+}
+
+// NewMockObjectResult creates a new mock instance.
+func NewMockObjectResult(ctrl *gomock.Controller) *MockObjectResult {
+	mock := &MockObjectResult{ctrl: ctrl}
+	mock.recorder = &MockObjectResultMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockObjectResult) EXPECT() *MockObjectResultMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockObjectResult) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockObjectResultMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockObjectResult)(nil).Close))
+}
+
+// Error mocks base method.
+func (m *MockObjectResult) Error() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockObjectResultMockRecorder) Error() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockObjectResult)(nil).Error))
+}
+
+// Info mocks base method.
+func (m *MockObjectResult) Info() (*jetstream.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info")
+	ret0, _ := ret[0].(*jetstream.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockObjectResultMockRecorder) Info() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockObjectResult)(nil).Info))
+}
+
+// Read mocks base method.
+func (m *MockObjectResult) Read(p []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", p)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockObjectResultMockRecorder) Read(p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockObjectResult)(nil).Read), p)
+}
@@ -56,3 +56,78 @@ func (mr *MockMetricsMockRecorder) IncrementCounter(ctx, name any, labels ...any
 	varargs := append([]any{ctx, name}, labels...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementCounter", reflect.TypeOf((*MockMetrics)(nil).IncrementCounter), varargs...)
 }
+
+// NewCounter mocks base method.
+func (m *MockMetrics) NewCounter(name, desc string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NewCounter", name, desc)
+}
+
+// NewCounter indicates an expected call of NewCounter.
+func (mr *MockMetricsMockRecorder) NewCounter(name, desc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCounter", reflect.TypeOf((*MockMetrics)(nil).NewCounter), name, desc)
+}
+
+// NewGauge mocks base method.
+func (m *MockMetrics) NewGauge(name, desc string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NewGauge", name, desc)
+}
+
+// NewGauge indicates an expected call of NewGauge.
+func (mr *MockMetricsMockRecorder) NewGauge(name, desc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewGauge", reflect.TypeOf((*MockMetrics)(nil).NewGauge), name, desc)
+}
+
+// NewHistogram mocks base method.
+func (m *MockMetrics) NewHistogram(name, desc string, buckets ...float64) {
+	m.ctrl.T.Helper()
+	varargs := []any{name, desc}
+	for _, a := range buckets {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "NewHistogram", varargs...)
+}
+
+// NewHistogram indicates an expected call of NewHistogram.
+func (mr *MockMetricsMockRecorder) NewHistogram(name, desc any, buckets ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{name, desc}, buckets...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewHistogram", reflect.TypeOf((*MockMetrics)(nil).NewHistogram), varargs...)
+}
+
+// RecordHistogram mocks base method.
+func (m *MockMetrics) RecordHistogram(ctx context.Context, name string, value float64, labels ...string) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name, value}
+	for _, a := range labels {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "RecordHistogram", varargs...)
+}
+
+// RecordHistogram indicates an expected call of RecordHistogram.
+func (mr *MockMetricsMockRecorder) RecordHistogram(ctx, name, value any, labels ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name, value}, labels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHistogram", reflect.TypeOf((*MockMetrics)(nil).RecordHistogram), varargs...)
+}
+
+// SetGauge mocks base method.
+func (m *MockMetrics) SetGauge(name string, value float64, labels ...string) {
+	m.ctrl.T.Helper()
+	varargs := []any{name, value}
+	for _, a := range labels {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "SetGauge", varargs...)
+}
+
+// SetGauge indicates an expected call of SetGauge.
+func (mr *MockMetricsMockRecorder) SetGauge(name, value any, labels ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{name, value}, labels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGauge", reflect.TypeOf((*MockMetrics)(nil).SetGauge), varargs...)
+}
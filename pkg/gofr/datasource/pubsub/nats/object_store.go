@@ -0,0 +1,76 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// ObjectStore is a NATS jStream object store bucket for payloads larger than the maximum message size.
+// It shares the connection, logger and metrics of the Client.
+type ObjectStore struct {
+	obs     jetstream.ObjectStore
+	bucket  string
+	logger  pubsub.Logger
+	metrics Metrics
+}
+
+// ObjectStore returns the object store bucket with the given name, creating it if it does not exist.
+func (c *Client) ObjectStore(ctx context.Context, bucket string) (*ObjectStore, error) {
+	js, err := c.connManager.jetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	obs, err := js.ObjectStore(ctx, bucket)
+	if errors.Is(err, jetstream.ErrBucketNotFound) {
+		obs, err = js.CreateObjectStore(ctx, jetstream.ObjectStoreConfig{Bucket: bucket})
+	}
+
+	if err != nil {
+		c.logger.Errorf("failed to open object store %s: %v", bucket, err)
+
+		return nil, fmt.Errorf("failed to open object store %s: %w", bucket, err)
+	}
+
+	return &ObjectStore{obs: obs, bucket: bucket, logger: c.logger, metrics: c.metrics}, nil
+}
+
+// Put stores the contents of r as the object with the given name, replacing any existing object.
+func (o *ObjectStore) Put(ctx context.Context, name string, r io.Reader) error {
+	info, err := o.obs.Put(ctx, jetstream.ObjectMeta{Name: name}, r)
+	if err != nil {
+		return fmt.Errorf("failed to put object %s in bucket %s: %w", name, o.bucket, err)
+	}
+
+	o.recordBytes(ctx, "put", info.Size)
+	o.logger.Debugf("put object %s of %d bytes in bucket %s", name, info.Size, o.bucket)
+
+	return nil
+}
+
+// Get returns a reader for the object with the given name. The caller must close the reader.
+func (o *ObjectStore) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	result, err := o.obs.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s from bucket %s: %w", name, o.bucket, err)
+	}
+
+	if info, err := result.Info(); err == nil {
+		o.recordBytes(ctx, "get", info.Size)
+	}
+
+	return result, nil
+}
+
+func (o *ObjectStore) recordBytes(ctx context.Context, operation string, size uint64) {
+	if o.metrics == nil {
+		return
+	}
+
+	o.metrics.RecordHistogram(ctx, objectStoreBytesMetric, float64(size), "bucket", o.bucket, "operation", operation)
+}
@@ -0,0 +1,107 @@
+package nats
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestClient_ObjectStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+	mockObs := NewMockObjectStore(ctrl)
+
+	client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil).Times(3)
+	mockJS.EXPECT().ObjectStore(ctx, "reports").Return(mockObs, nil)
+	mockJS.EXPECT().ObjectStore(ctx, "exports").Return(nil, jetstream.ErrBucketNotFound)
+	mockJS.EXPECT().CreateObjectStore(ctx, jetstream.ObjectStoreConfig{Bucket: "exports"}).Return(mockObs, nil)
+	mockJS.EXPECT().ObjectStore(ctx, "broken").Return(nil, errJetStream)
+
+	obs, err := client.ObjectStore(ctx, "reports")
+	require.NoError(t, err)
+	assert.Equal(t, mockObs, obs.obs)
+
+	obs, err = client.ObjectStore(ctx, "exports")
+	require.NoError(t, err)
+	assert.Equal(t, mockObs, obs.obs)
+
+	_, err = client.ObjectStore(ctx, "broken")
+	require.ErrorIs(t, err, errJetStream)
+}
+
+func TestObjectStore_PutGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockObs := NewMockObjectStore(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	obs := &ObjectStore{obs: mockObs, bucket: "reports", logger: logging.NewMockLogger(logging.DEBUG), metrics: mockMetrics}
+
+	ctx := context.Background()
+	payload := strings.Repeat("x", 4096)
+	store := map[string][]byte{}
+
+	mockObs.EXPECT().Put(ctx, jetstream.ObjectMeta{Name: "report.csv"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, meta jetstream.ObjectMeta, r io.Reader) (*jetstream.ObjectInfo, error) {
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+
+			store[meta.Name] = data
+
+			return &jetstream.ObjectInfo{ObjectMeta: meta, Size: uint64(len(data))}, nil
+		})
+	mockObs.EXPECT().Get(ctx, "report.csv").
+		DoAndReturn(func(_ context.Context, name string, _ ...jetstream.GetObjectOpt) (jetstream.ObjectResult, error) {
+			reader := bytes.NewReader(store[name])
+
+			result := NewMockObjectResult(ctrl)
+			result.EXPECT().Info().Return(&jetstream.ObjectInfo{Size: uint64(reader.Len())}, nil)
+			result.EXPECT().Read(gomock.Any()).DoAndReturn(reader.Read).AnyTimes()
+			result.EXPECT().Close().Return(nil)
+
+			return result, nil
+		})
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_nats_objectstore_bytes", float64(4096), "bucket", "reports", "operation", "put")
+	mockMetrics.EXPECT().RecordHistogram(ctx, "app_nats_objectstore_bytes", float64(4096), "bucket", "reports", "operation", "get")
+
+	require.NoError(t, obs.Put(ctx, "report.csv", strings.NewReader(payload)))
+
+	reader, err := obs.Get(ctx, "report.csv")
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, payload, string(data))
+}
+
+func TestObjectStore_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockObs := NewMockObjectStore(ctrl)
+	obs := &ObjectStore{obs: mockObs, bucket: "reports", logger: logging.NewMockLogger(logging.DEBUG)}
+	ctx := context.Background()
+
+	mockObs.EXPECT().Put(ctx, jetstream.ObjectMeta{Name: "report.csv"}, gomock.Any()).Return(nil, errJetStream)
+	mockObs.EXPECT().Get(ctx, "missing.csv").Return(nil, jetstream.ErrObjectNotFound)
+
+	require.ErrorIs(t, obs.Put(ctx, "report.csv", strings.NewReader("data")), errJetStream)
+
+	_, err := obs.Get(ctx, "missing.csv")
+	require.ErrorIs(t, err, jetstream.ErrObjectNotFound)
+}
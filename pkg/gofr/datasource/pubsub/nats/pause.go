@@ -0,0 +1,152 @@
+package nats
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pausedPollInterval is how often a paused consume loop checks whether it was resumed.
+const pausedPollInterval = 100 * time.Millisecond
+
+// pausedSubjects is the set of subjects paused with PauseSubscription and not yet resumed with
+// ResumeSubscription. Every consume loop checks it before fetching the next batch. It also counts the
+// fetches of handler subscriptions in flight, so that Shutdown can wait for them once everything is
+// paused.
+type pausedSubjects struct {
+	mu       sync.Mutex
+	subjects map[string]struct{}
+	all      bool
+	inFlight int
+	// clock defaults to the real clock when nil.
+	clock clock
+}
+
+func newPausedSubjects(clk clock) *pausedSubjects {
+	return &pausedSubjects{subjects: make(map[string]struct{}), clock: clk}
+}
+
+// set pauses or resumes the consume loop of subject.
+func (p *pausedSubjects) set(subject string, paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if paused {
+		p.subjects[subject] = struct{}{}
+
+		return
+	}
+
+	delete(p.subjects, subject)
+}
+
+func (p *pausedSubjects) isPaused(subject string) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.isPausedLocked(subject)
+}
+
+func (p *pausedSubjects) isPausedLocked(subject string) bool {
+	_, paused := p.subjects[subject]
+
+	return p.all || paused
+}
+
+// pauseAll pauses the consume loops of all subjects, including the ones subscribed later.
+func (p *pausedSubjects) pauseAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.all = true
+}
+
+// wait blocks while subject is paused, returning the context error if ctx is done first.
+func (p *pausedSubjects) wait(ctx context.Context, subject string) error {
+	for p.isPaused(subject) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clockOrReal(p.clock).After(pausedPollInterval):
+		}
+	}
+
+	return ctx.Err()
+}
+
+// acquire is like wait, but counts the fetch that follows as in flight until release is called. The
+// pause is checked and the fetch counted at once, so no fetch starts after idle has seen none in flight.
+func (p *pausedSubjects) acquire(ctx context.Context, subject string) error {
+	for ctx.Err() == nil {
+		p.mu.Lock()
+		if !p.isPausedLocked(subject) {
+			p.inFlight++
+			p.mu.Unlock()
+
+			return nil
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-clockOrReal(p.clock).After(pausedPollInterval):
+		}
+	}
+
+	return ctx.Err()
+}
+
+// release marks a fetch started by acquire as done.
+func (p *pausedSubjects) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight--
+}
+
+// idle blocks until no fetch is in flight, returning the context error if ctx is done first.
+func (p *pausedSubjects) idle(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		inFlight := p.inFlight
+		p.mu.Unlock()
+
+		if inFlight == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clockOrReal(p.clock).After(pausedPollInterval):
+		}
+	}
+}
+
+// PauseSubscription stops fetching messages for subject until ResumeSubscription is called, keeping
+// its consumer in place. Messages fetched before the pause are still delivered.
+func (c *Client) PauseSubscription(subject string) {
+	c.pausedSubjects().set(prefixSubject(c.Config, subject), true)
+
+	c.logger.Logf("paused subscription to %s", subject)
+}
+
+// ResumeSubscription resumes fetching messages for a subject paused with PauseSubscription.
+func (c *Client) ResumeSubscription(subject string) {
+	c.pausedSubjects().set(prefixSubject(c.Config, subject), false)
+
+	c.logger.Logf("resumed subscription to %s", subject)
+}
+
+// pausedSubjects returns the set of paused subjects, which survives a Reconnect.
+func (c *Client) pausedSubjects() *pausedSubjects {
+	c.pauseOnce.Do(func() {
+		c.paused = newPausedSubjects(c.clock)
+	})
+
+	return c.paused
+}
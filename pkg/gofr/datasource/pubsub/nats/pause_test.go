@@ -0,0 +1,130 @@
+package nats
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestClient_PauseResumeSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumer := NewMockConsumer(ctrl)
+	logger := logging.NewMockLogger(logging.DEBUG)
+	client := &Client{Config: &Config{}, logger: logger}
+
+	sm := newSubscriptionManager(1)
+	sm.paused = client.pausedSubjects()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var fetches atomic.Int32
+
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+			fetches.Add(1)
+			cancel()
+
+			return nil, context.Canceled
+		})
+
+	client.PauseSubscription("orders.created")
+	assert.True(t, client.pausedSubjects().isPaused("orders.created"))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		sm.consumeMessages(ctx, nil, mockConsumer, "orders.created", make(chan *pubsub.Message, 1), &Config{}, logger, nil, nil)
+	}()
+
+	// a paused loop performs no fetches
+	time.Sleep(3 * pausedPollInterval)
+	assert.Zero(t, fetches.Load())
+
+	client.ResumeSubscription("orders.created")
+	assert.False(t, client.pausedSubjects().isPaused("orders.created"))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("consume loop did not resume fetching")
+	}
+
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestPausedSubjects_Wait(t *testing.T) {
+	paused := newPausedSubjects(nil)
+	paused.set("orders.created", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a cancelled context ends the wait even while paused
+	assert.ErrorIs(t, paused.wait(ctx, "orders.created"), context.Canceled)
+
+	// a nil set pauses nothing
+	var none *pausedSubjects
+
+	assert.NoError(t, none.wait(context.Background(), "orders.created"))
+}
+
+func TestPausedSubjects_Idle(t *testing.T) {
+	clk := newFakeClock()
+	paused := newPausedSubjects(clk)
+
+	require.NoError(t, paused.acquire(context.Background(), "orders.created"))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- paused.idle(context.Background())
+	}()
+
+	// idle polls on the clock while a fetch is in flight
+	require.Eventually(t, func() bool { return clk.pending() == 1 }, time.Second, time.Millisecond)
+
+	paused.release()
+	clk.Advance(pausedPollInterval)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("idle did not return once no fetch was in flight")
+	}
+
+	// a paused subject is polled on the clock until it is resumed
+	paused.pauseAll()
+
+	go func() {
+		done <- paused.acquire(context.Background(), "orders.created")
+	}()
+
+	require.Eventually(t, func() bool { return clk.pending() == 1 }, time.Second, time.Millisecond)
+
+	paused.mu.Lock()
+	paused.all = false
+	paused.mu.Unlock()
+
+	clk.Advance(pausedPollInterval)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("acquire did not return once the subject was resumed")
+	}
+}
@@ -0,0 +1,128 @@
+package nats
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// publishRateLimiter limits the rate of publishes per subject with a token bucket holding up to one
+// second worth of messages. Buckets that have filled up again are dropped, as a new bucket starts out
+// full, so subjects that are no longer published to do not pile up. A nil publishRateLimiter does not
+// limit anything.
+type publishRateLimiter struct {
+	rate     float64
+	burst    float64
+	blocking bool
+	clock    clock
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newPublishRateLimiter returns nil unless Config.PublishRateLimit is positive.
+func newPublishRateLimiter(conf *Config, clk clock) *publishRateLimiter {
+	if conf.PublishRateLimit <= 0 {
+		return nil
+	}
+
+	return &publishRateLimiter{
+		rate:     conf.PublishRateLimit,
+		burst:    max(conf.PublishRateLimit, 1),
+		blocking: conf.RateLimitBlocking,
+		clock:    clockOrReal(clk),
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// wait takes a token for a publish to subject and reports whether the publish exceeded the limit. A
+// blocking limiter waits until the token is available or ctx is done, any other returns errRateLimited.
+func (l *publishRateLimiter) wait(ctx context.Context, subject string) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+
+	delay, ok := l.reserve(subject)
+
+	switch {
+	case !ok:
+		return true, errRateLimited
+	case delay <= 0:
+		return false, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		l.cancel(subject)
+
+		return true, ctx.Err()
+	case <-l.clock.After(delay):
+		return true, nil
+	}
+}
+
+// cancel puts back the token a blocking publish to subject reserved but gave up waiting for.
+func (l *publishRateLimiter) cancel(subject string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket, ok := l.buckets[subject]; ok {
+		bucket.tokens = min(l.burst, bucket.tokens+1)
+	}
+}
+
+// reserve takes a token from the bucket of subject and returns how long to wait until it is
+// available. Without blocking, no token is taken when none is available and reserve reports false.
+func (l *publishRateLimiter) reserve(subject string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[subject]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[subject] = bucket
+	}
+
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+
+		return 0, true
+	}
+
+	if !l.blocking {
+		return 0, false
+	}
+
+	// the token is taken ahead of time, so that waiting publishes are served in order
+	missing := 1 - bucket.tokens
+	bucket.tokens--
+
+	return time.Duration(missing / l.rate * float64(time.Second)), true
+}
+
+// sweep drops the buckets that have refilled completely, at most once per second.
+func (l *publishRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Second {
+		return
+	}
+
+	l.lastSweep = now
+
+	for subject, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, subject)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestPublishRateLimiter_NonBlocking(t *testing.T) {
+	clk := newFakeClock()
+	l := newPublishRateLimiter(&Config{PublishRateLimit: 2}, clk)
+	ctx := context.Background()
+
+	for range 2 {
+		limited, err := l.wait(ctx, "orders")
+		require.NoError(t, err)
+		assert.False(t, limited)
+	}
+
+	// the bucket of the subject is empty, while other subjects have their own
+	limited, err := l.wait(ctx, "orders")
+	require.ErrorIs(t, err, errRateLimited)
+	assert.True(t, limited)
+
+	_, err = l.wait(ctx, "payments")
+	require.NoError(t, err)
+
+	clk.Advance(500 * time.Millisecond)
+
+	_, err = l.wait(ctx, "orders")
+	require.NoError(t, err)
+}
+
+func TestPublishRateLimiter_Blocking(t *testing.T) {
+	clk := newFakeClock()
+	l := newPublishRateLimiter(&Config{PublishRateLimit: 1, RateLimitBlocking: true}, clk)
+	ctx := context.Background()
+
+	limited, err := l.wait(ctx, "orders")
+	require.NoError(t, err)
+	assert.False(t, limited)
+
+	type result struct {
+		limited bool
+		err     error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		limited, err := l.wait(ctx, "orders")
+		done <- result{limited: limited, err: err}
+	}()
+
+	// the publish over the limit waits for the next token
+	require.Eventually(t, func() bool { return clk.pending() == 1 }, time.Second, time.Millisecond)
+
+	select {
+	case <-done:
+		t.Fatal("returned before a token was available")
+	default:
+	}
+
+	clk.Advance(time.Second)
+
+	select {
+	case res := <-done:
+		require.NoError(t, res.err)
+		assert.True(t, res.limited)
+	case <-time.After(time.Second):
+		t.Fatal("did not return once a token was available")
+	}
+
+	// a waiting publish gives up when its context is done
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = l.wait(canceled, "orders")
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the token it reserved is put back for the next publish
+	clk.Advance(time.Second)
+
+	limited, err = l.wait(ctx, "orders")
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestPublishRateLimiter_DropsIdleBuckets(t *testing.T) {
+	clk := newFakeClock()
+	l := newPublishRateLimiter(&Config{PublishRateLimit: 2}, clk)
+	ctx := context.Background()
+
+	_, err := l.wait(ctx, "payments")
+	require.NoError(t, err)
+
+	clk.Advance(500 * time.Millisecond)
+
+	for range 2 {
+		_, err = l.wait(ctx, "orders")
+		require.NoError(t, err)
+	}
+
+	// by the next sweep payments has refilled and is dropped, while orders has not
+	clk.Advance(500 * time.Millisecond)
+
+	_, err = l.wait(ctx, "refunds")
+	require.NoError(t, err)
+
+	l.mu.Lock()
+	assert.Contains(t, l.buckets, "orders")
+	assert.NotContains(t, l.buckets, "payments")
+	l.mu.Unlock()
+}
+
+func TestPublishRateLimiter_Disabled(t *testing.T) {
+	l := newPublishRateLimiter(&Config{}, nil)
+	assert.Nil(t, l)
+
+	limited, err := l.wait(context.Background(), "orders")
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestNATSClient_Publish_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{},
+		metrics:     mockMetrics,
+		logger:      logging.NewMockLogger(logging.DEBUG),
+		limiter:     newPublishRateLimiter(&Config{PublishRateLimit: 1}, newFakeClock()),
+	}
+
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().Publish(ctx, "orders", []byte("test-message"), mockMetrics).Return(nil)
+	mockMetrics.EXPECT().IncrementCounter(ctx, "app_pubsub_rate_limited_count", "subject", "orders")
+
+	require.NoError(t, client.Publish(ctx, "orders", []byte("test-message")))
+
+	err := client.Publish(ctx, "orders", []byte("test-message"))
+	require.ErrorIs(t, err, errRateLimited)
+	require.ErrorIs(t, err, &NatsError{Kind: PublishError})
+}
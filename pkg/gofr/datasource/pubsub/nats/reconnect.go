@@ -0,0 +1,64 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Reconnect replaces the connection of the client with one built from cfg, for instance to move to a
+// new server during a rolling upgrade. Existing subscriptions and responders are stopped and the old
+// connection is drained within ctx before connecting again. Subjects consumed with SubscribeWithHandler
+// are then subscribed again with the same handlers and the contexts they were first subscribed with,
+// so ctx only bounds the reconnect itself. Subscribe subscriptions resume on their next call.
+// Responders must be registered again. The client keeps its connection when cfg is invalid.
+func (c *Client) Reconnect(ctx context.Context, cfg Config) error {
+	if err := validateConfigs(&cfg); err != nil {
+		c.logger.Errorf("could not reconnect to NATS: %v", err)
+
+		return newNatsError(ConfigError, err)
+	}
+
+	c.subMutex.Lock()
+	handlers := make(map[string]handlerSubscription, len(c.handlers))
+
+	// handlers are keyed by the prefixed subject, while SubscribeWithHandler prefixes it again
+	for subject, sub := range c.handlers {
+		if sub.ctx.Err() == nil {
+			handlers[trimSubjectPrefix(c.Config, subject)] = sub
+		}
+
+		c.cancelExistingSubscription(subject)
+	}
+	c.subMutex.Unlock()
+
+	if c.subManager != nil {
+		c.subManager.Close()
+	}
+
+	c.drainResponders("")
+
+	if c.connManager != nil {
+		if err := c.connManager.Drain(ctx); err != nil {
+			c.logger.Errorf("failed to drain NATS connection before reconnecting: %v", err)
+		}
+	}
+
+	c.Config = &cfg
+
+	if err := c.Connect(); err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+
+	for subject, sub := range handlers {
+		if err := c.SubscribeWithHandler(sub.ctx, subject, sub.handler); err != nil {
+			errs = append(errs, fmt.Errorf("failed to subscribe to subject %s again: %w", subject, err))
+		}
+	}
+
+	c.logger.Logf("reconnected to NATS server '%s' with %d handler subscriptions", serverList(&cfg), len(handlers)-len(errs))
+
+	return errors.Join(errs...)
+}
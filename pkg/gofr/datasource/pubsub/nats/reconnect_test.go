@@ -0,0 +1,187 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestClient_Reconnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldConnManager := NewMockConnectionManagerInterface(ctrl)
+	oldJS := NewMockJetStream(ctrl)
+	oldConsumer := NewMockConsumer(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+	mockConn := NewMockConnInterface(ctrl)
+	newJS := NewMockJetStream(ctrl)
+	newConsumer := NewMockConsumer(ctrl)
+
+	client := &Client{
+		connManager: oldConnManager,
+		subManager:  newSubscriptionManager(1),
+		Config: &Config{
+			Server:   "nats://old:4222",
+			Stream:   StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}},
+			Consumer: "orders-consumer",
+		},
+		logger:           logging.NewMockLogger(logging.DEBUG),
+		natsConnector:    mockNATSConnector,
+		jetStreamCreator: mockJSCreator,
+	}
+
+	ctx := context.Background()
+	stop := make(chan struct{})
+	received := make(chan jetstream.Msg, 1)
+
+	defer close(stop)
+
+	blockingFetch := func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+		<-stop
+
+		return nil, context.Canceled
+	}
+
+	oldConnManager.EXPECT().JetStream().Return(oldJS, nil)
+	oldJS.EXPECT().CreateOrUpdateConsumer(ctx, "orders", gomock.Any()).Return(oldConsumer, nil)
+	oldConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).DoAndReturn(blockingFetch).AnyTimes()
+
+	handler := func(_ context.Context, msg jetstream.Msg) error {
+		received <- msg
+
+		return nil
+	}
+
+	require.NoError(t, client.SubscribeWithHandler(ctx, "orders.created", handler))
+
+	reconnectCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+	// the old connection is drained and a new one is built from the new configuration
+	oldConnManager.EXPECT().Drain(reconnectCtx).Return(nil)
+	mockNATSConnector.EXPECT().Connect("nats://new:4222", gomock.Any()).Return(mockConn, nil)
+	mockJSCreator.EXPECT().New(mockConn).Return(newJS, nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	// the handler is subscribed again on the new connection, within the context it was subscribed with
+	mockMsg := NewMockMsg(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	msgs := make(chan jetstream.Msg, 1)
+	msgs <- mockMsg
+	close(msgs)
+
+	newJS.EXPECT().CreateOrUpdateConsumer(ctx, "orders", gomock.Any()).Return(newConsumer, nil)
+	gomock.InOrder(
+		newConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil),
+		newConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).DoAndReturn(blockingFetch).AnyTimes(),
+	)
+	mockBatch.EXPECT().Messages().Return(msgs)
+	mockBatch.EXPECT().Error().Return(nil)
+	mockMsg.EXPECT().Ack().Return(nil)
+
+	newConfig := *client.Config
+	newConfig.Server = "nats://new:4222"
+
+	err := client.Reconnect(reconnectCtx, newConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "nats://new:4222", client.Config.Server)
+
+	// the subscription outlives the context of the reconnect
+	cancel()
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, mockMsg, msg)
+	case <-time.After(time.Second):
+		t.Fatal("handler was not subscribed again after reconnecting")
+	}
+
+	client.subMutex.Lock()
+	assert.Contains(t, client.handlers, "orders.created")
+	client.subMutex.Unlock()
+}
+
+func TestClient_Reconnect_SubjectPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldConnManager := NewMockConnectionManagerInterface(ctrl)
+	oldJS := NewMockJetStream(ctrl)
+	mockNATSConnector := NewMockNATSConnector(ctrl)
+	mockJSCreator := NewMockJetStreamCreator(ctrl)
+	mockConn := NewMockConnInterface(ctrl)
+	newJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
+	client := &Client{
+		connManager: oldConnManager,
+		subManager:  newSubscriptionManager(1),
+		Config: &Config{
+			Server:        "nats://old:4222",
+			Stream:        StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}},
+			Consumer:      "orders-consumer",
+			SubjectPrefix: "prod.",
+		},
+		logger:           logging.NewMockLogger(logging.DEBUG),
+		natsConnector:    mockNATSConnector,
+		jetStreamCreator: mockJSCreator,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filterSubject := func(_ context.Context, _ string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+		assert.Equal(t, "prod.orders.created", cfg.FilterSubject)
+
+		return mockConsumer, nil
+	}
+
+	oldConnManager.EXPECT().JetStream().Return(oldJS, nil)
+	oldJS.EXPECT().CreateOrUpdateConsumer(ctx, "orders", gomock.Any()).DoAndReturn(filterSubject)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+
+	handler := func(context.Context, jetstream.Msg) error { return nil }
+
+	require.NoError(t, client.SubscribeWithHandler(ctx, "orders.created", handler))
+
+	oldConnManager.EXPECT().Drain(ctx).Return(nil)
+	mockNATSConnector.EXPECT().Connect("nats://new:4222", gomock.Any()).Return(mockConn, nil)
+	mockJSCreator.EXPECT().New(mockConn).Return(newJS, nil)
+	mockConn.EXPECT().MaxPayload().Return(int64(1048576))
+
+	// the handler is subscribed to the same prefixed subject, not to prod.prod.orders.created
+	newJS.EXPECT().CreateOrUpdateConsumer(ctx, "orders", gomock.Any()).DoAndReturn(filterSubject)
+
+	newConfig := *client.Config
+	newConfig.Server = "nats://new:4222"
+
+	require.NoError(t, client.Reconnect(ctx, newConfig))
+
+	client.subMutex.Lock()
+	assert.Contains(t, client.handlers, "prod.orders.created")
+	assert.Len(t, client.handlers, 1)
+	client.subMutex.Unlock()
+}
+
+func TestClient_Reconnect_InvalidConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the current connection is kept, so nothing is called on it
+	client := &Client{
+		connManager: NewMockConnectionManagerInterface(ctrl),
+		Config:      &Config{Server: "nats://old:4222", Consumer: "orders-consumer"},
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	err := client.Reconnect(context.Background(), Config{Consumer: "orders-consumer"})
+	require.ErrorIs(t, err, errServerNotProvided)
+	assert.Equal(t, "nats://old:4222", client.Config.Server)
+}
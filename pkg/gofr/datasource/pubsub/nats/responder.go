@@ -0,0 +1,122 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// serviceErrorHeader carries the error of a failed request, as done by NATS micro services.
+const serviceErrorHeader = "Nats-Service-Error"
+
+// requestCommitter is the Committer of a request served by a responder, which needs no acknowledgement.
+type requestCommitter struct{}
+
+func (requestCommitter) Commit() {}
+
+// RegisterResponder serves the requests sent to subject, e.g. with Request, replying with the output
+// of handler. When handler fails, the reply carries no data and the error in the Nats-Service-Error
+// header, so that requesters fail fast instead of timing out. Responders stop when ctx is done and
+// are drained on Unsubscribe of subject or on Close.
+func (c *Client) RegisterResponder(ctx context.Context, subject string, handler func(*pubsub.Message) ([]byte, error)) error {
+	if c.connManager == nil {
+		return newNatsError(RequestError, errConnectionNotEstablished)
+	}
+
+	sub, err := c.connManager.SubscribeRequests(prefixSubject(c.Config, subject), func(msg *nats.Msg) {
+		c.respond(ctx, msg, handler)
+	})
+	if err != nil {
+		c.logger.Errorf("failed to register responder for subject %s: %v", subject, err)
+
+		return newNatsError(RequestError, err)
+	}
+
+	// the responder is stopped by whichever comes first of ctx being done and drainResponders
+	stopCtx, stop := context.WithCancel(ctx)
+
+	c.subMutex.Lock()
+	if c.responders == nil {
+		c.responders = make(map[*nats.Subscription]context.CancelFunc)
+	}
+
+	c.responders[sub] = stop
+	c.subMutex.Unlock()
+
+	go func() {
+		<-stopCtx.Done()
+
+		c.subMutex.Lock()
+		_, registered := c.responders[sub]
+		delete(c.responders, sub)
+		c.subMutex.Unlock()
+
+		if !registered {
+			return
+		}
+
+		if err := sub.Drain(); err != nil && sub.IsValid() {
+			c.logger.Errorf("failed to drain responder for subject %s: %v", subject, err)
+		}
+	}()
+
+	c.logger.Debugf("registered responder for subject %s", subject)
+
+	return nil
+}
+
+// respond calls handler for the request msg and publishes the result to its reply subject.
+func (c *Client) respond(ctx context.Context, msg *nats.Msg, handler func(*pubsub.Message) ([]byte, error)) {
+	request := pubsub.NewMessage(extractTraceContext(ctx, msg.Header))
+	request.Topic = trimSubjectPrefix(c.Config, msg.Subject)
+	request.Value = msg.Data
+	request.MetaData = msg.Header
+	request.Committer = requestCommitter{}
+
+	data, err := handler(request)
+
+	if msg.Reply == "" {
+		c.logger.Debugf("request on subject %s has no reply subject, dropping the reply", msg.Subject)
+
+		return
+	}
+
+	reply := &nats.Msg{Subject: msg.Reply, Data: data}
+
+	if err != nil {
+		c.logger.Errorf("failed to handle request on subject %s: %v", msg.Subject, err)
+
+		reply.Data = nil
+		reply.Header = nats.Header{serviceErrorHeader: []string{err.Error()}}
+	}
+
+	if err := c.connManager.PublishReply(reply); err != nil {
+		c.logger.Errorf("failed to reply to request on subject %s: %v", msg.Subject, err)
+	}
+}
+
+// drainResponders stops the responders of subject, or all of them when subject is empty, after the
+// requests they are serving have been replied to. It reports whether there were any.
+func (c *Client) drainResponders(subject string) bool {
+	c.subMutex.Lock()
+	responders := make(map[*nats.Subscription]context.CancelFunc)
+
+	for sub, stop := range c.responders {
+		if subject == "" || sub.Subject == subject {
+			responders[sub] = stop
+			delete(c.responders, sub)
+		}
+	}
+	c.subMutex.Unlock()
+
+	for sub, stop := range responders {
+		stop()
+
+		if err := sub.Drain(); err != nil && sub.IsValid() {
+			c.logger.Errorf("failed to drain responder for subject %s: %v", sub.Subject, err)
+		}
+	}
+
+	return len(responders) > 0
+}
@@ -0,0 +1,241 @@
+package nats
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestClient_RegisterResponder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	client := &Client{connManager: mockConnManager, subManager: mockSubManager, Config: &Config{},
+		logger: logging.NewMockLogger(logging.DEBUG)}
+
+	var serve nats.MsgHandler
+
+	mockConnManager.EXPECT().SubscribeRequests("orders.get", gomock.Any()).
+		DoAndReturn(func(_ string, handler nats.MsgHandler) (*nats.Subscription, error) {
+			serve = handler
+
+			return &nats.Subscription{}, nil
+		})
+
+	err := client.RegisterResponder(context.Background(), "orders.get", func(msg *pubsub.Message) ([]byte, error) {
+		if string(msg.Value) == "" {
+			return nil, errHandlerError
+		}
+
+		return []byte(strings.ToUpper(string(msg.Value))), nil
+	})
+	require.NoError(t, err)
+
+	mockConnManager.EXPECT().PublishReply(&nats.Msg{Subject: "_INBOX.1", Data: []byte("ORDER-1")}).Return(nil)
+	mockConnManager.EXPECT().PublishReply(&nats.Msg{Subject: "_INBOX.2",
+		Header: nats.Header{"Nats-Service-Error": []string{errHandlerError.Error()}}}).Return(nil)
+
+	serve(&nats.Msg{Subject: "orders.get", Reply: "_INBOX.1", Data: []byte("order-1")})
+	// a failed request is answered with the error instead of timing out
+	serve(&nats.Msg{Subject: "orders.get", Reply: "_INBOX.2"})
+	// messages without a reply subject are handled without replying
+	serve(&nats.Msg{Subject: "orders.get", Data: []byte("order-3")})
+
+	mockSubManager.EXPECT().Close()
+	mockConnManager.EXPECT().Close(gomock.Any()).Return(nil)
+
+	require.NoError(t, client.Close(context.Background()))
+	assert.Zero(t, responderCount(client))
+}
+
+func TestClient_RegisterResponder_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := func(*pubsub.Message) ([]byte, error) { return nil, nil }
+
+	client := &Client{Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+	require.ErrorIs(t, client.RegisterResponder(context.Background(), "orders.get", handler), errConnectionNotEstablished)
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockConnManager.EXPECT().SubscribeRequests("orders.get", gomock.Any()).Return(nil, errSubscriptionError)
+
+	client.connManager = mockConnManager
+	require.ErrorIs(t, client.RegisterResponder(context.Background(), "orders.get", handler), errSubscriptionError)
+	assert.Zero(t, responderCount(client))
+}
+
+func TestClient_RegisterResponder_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	client := &Client{connManager: mockConnManager, subManager: mockSubManager, Config: &Config{},
+		logger: logging.NewMockLogger(logging.DEBUG)}
+
+	handler := func(*pubsub.Message) ([]byte, error) { return nil, nil }
+
+	mockConnManager.EXPECT().SubscribeRequests("orders.get", gomock.Any()).Return(&nats.Subscription{Subject: "orders.get"}, nil)
+	mockConnManager.EXPECT().SubscribeRequests("orders.list", gomock.Any()).Return(&nats.Subscription{Subject: "orders.list"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	require.NoError(t, client.RegisterResponder(ctx, "orders.get", handler))
+	require.NoError(t, client.RegisterResponder(context.Background(), "orders.list", handler))
+
+	// a responder whose context is done is removed
+	cancel()
+
+	require.Eventually(t, func() bool { return responderCount(client) == 1 }, time.Second, time.Millisecond)
+
+	// Unsubscribe drains the responders of the subject
+	mockSubManager.EXPECT().Unsubscribe("orders.list").Return(false)
+
+	require.NoError(t, client.Unsubscribe(context.Background(), "orders.list", false))
+
+	assert.Zero(t, responderCount(client))
+}
+
+// responderCount reads the number of registered responders under subMutex, as the responder
+// goroutines remove themselves concurrently.
+func responderCount(client *Client) int {
+	client.subMutex.Lock()
+	defer client.subMutex.Unlock()
+
+	return len(client.responders)
+}
+
+func TestClient_RegisterResponder_Request(t *testing.T) {
+	ns, url := startNATSServer(t)
+	defer ns.Shutdown()
+
+	nc, err := nats.Connect(url)
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), gomock.Any(), "subject", "orders.get").AnyTimes()
+
+	cm := &ConnectionManager{conn: &natsConnWrapper{conn: nc}, logger: logging.NewMockLogger(logging.DEBUG)}
+	client := &Client{connManager: cm, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err = client.RegisterResponder(ctx, "orders.get", func(msg *pubsub.Message) ([]byte, error) {
+		return append([]byte("reply to "), msg.Value...), nil
+	})
+	require.NoError(t, err)
+
+	reqCtx, reqCancel := context.WithTimeout(context.Background(), time.Second)
+	defer reqCancel()
+
+	reply, err := cm.Request(reqCtx, "orders.get", []byte("order-1"), mockMetrics)
+	require.NoError(t, err)
+	assert.Equal(t, "reply to order-1", string(reply.Value))
+
+	// the responder stops once its context is done
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, err := cm.Request(reqCtx, "orders.get", []byte("order-2"), mockMetrics)
+
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClient_RequestWithHeaders(t *testing.T) {
+	ns, url := startNATSServer(t)
+	defer ns.Shutdown()
+
+	nc, err := nats.Connect(url)
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), gomock.Any(), "subject", "orders.get").AnyTimes()
+
+	cm := &ConnectionManager{conn: &natsConnWrapper{conn: nc}, logger: logging.NewMockLogger(logging.DEBUG)}
+	client := &Client{connManager: cm, Config: &Config{}, metrics: mockMetrics, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the responder receives the request headers as the message MetaData
+	err = client.RegisterResponder(ctx, "orders.get", func(msg *pubsub.Message) ([]byte, error) {
+		headers, ok := msg.MetaData.(nats.Header)
+		if !ok {
+			return nil, errNotNATSMessage
+		}
+
+		return []byte(headers.Get("Authorization")), nil
+	})
+	require.NoError(t, err)
+
+	reqCtx, reqCancel := context.WithTimeout(context.Background(), time.Second)
+	defer reqCancel()
+
+	reply, err := client.RequestWithHeaders(reqCtx, "orders.get", []byte("order-1"),
+		nats.Header{"Authorization": []string{"Bearer secret"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", string(reply.Value))
+}
+
+func TestClient_Request_SubjectPrefix(t *testing.T) {
+	ns, url := startNATSServer(t)
+	defer ns.Shutdown()
+
+	nc, err := nats.Connect(url)
+	require.NoError(t, err)
+
+	defer nc.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetrics(ctrl)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), gomock.Any(), "subject", "prod.orders.get").AnyTimes()
+
+	cm := &ConnectionManager{conn: &natsConnWrapper{conn: nc}, logger: logging.NewMockLogger(logging.DEBUG)}
+	client := &Client{connManager: cm, Config: &Config{SubjectPrefix: "prod."}, metrics: mockMetrics,
+		logger: logging.NewMockLogger(logging.DEBUG)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the responder listens on prod.orders.get but sees the subject without the prefix
+	err = client.RegisterResponder(ctx, "orders.get", func(msg *pubsub.Message) ([]byte, error) {
+		return []byte(msg.Topic), nil
+	})
+	require.NoError(t, err)
+
+	reqCtx, reqCancel := context.WithTimeout(context.Background(), time.Second)
+	defer reqCancel()
+
+	reply, err := client.Request(reqCtx, "orders.get", []byte("order-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "orders.get", string(reply.Value))
+	assert.Equal(t, "orders.get", reply.Topic)
+
+	raw, err := nc.RequestWithContext(reqCtx, "prod.orders.get", []byte("order-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "orders.get", string(raw.Data))
+}
@@ -0,0 +1,111 @@
+package nats
+
+import (
+	"sync"
+	"time"
+)
+
+// streamNameCacheSize bounds the number of subjects a streamNameCache remembers.
+const streamNameCacheSize = 1024
+
+// streamNameCache remembers which stream captures a subject for Config.StreamInfoCacheTTL, so that
+// repeated lookups avoid a round trip to the server. Once streamNameCacheSize subjects are cached, the
+// expired entries are dropped, or else the entry closest to expiry. A nil streamNameCache caches nothing.
+type streamNameCache struct {
+	ttl   time.Duration
+	clock clock
+
+	mu      sync.Mutex
+	entries map[string]streamNameEntry
+}
+
+type streamNameEntry struct {
+	stream  string
+	found   bool
+	expires time.Time
+}
+
+// newStreamNameCache returns a cache keeping entries for ttl as measured by clk, which defaults to the
+// real clock when nil, or nil when ttl is not positive.
+func newStreamNameCache(ttl time.Duration, clk clock) *streamNameCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &streamNameCache{ttl: ttl, clock: clockOrReal(clk), entries: make(map[string]streamNameEntry)}
+}
+
+// get returns the cached stream of subject and whether one was found, with ok false when the subject
+// is not cached or its entry expired.
+func (c *streamNameCache) get(subject string) (stream string, found, ok bool) {
+	if c == nil {
+		return "", false, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[subject]
+	if !ok || c.clock.Now().After(entry.expires) {
+		delete(c.entries, subject)
+
+		return "", false, false
+	}
+
+	return entry.stream, entry.found, true
+}
+
+// set caches the stream of subject, where found is false when no stream captures the subject.
+func (c *streamNameCache) set(subject, stream string, found bool) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+
+	if _, cached := c.entries[subject]; !cached && len(c.entries) >= streamNameCacheSize {
+		c.evict(now)
+	}
+
+	c.entries[subject] = streamNameEntry{stream: stream, found: found, expires: now.Add(c.ttl)}
+}
+
+// evict makes room for an entry by dropping the expired entries, or the one closest to expiry when none
+// expired. The lock must be held.
+func (c *streamNameCache) evict(now time.Time) {
+	var (
+		oldest  string
+		expires time.Time
+	)
+
+	for subject, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, subject)
+
+			continue
+		}
+
+		if oldest == "" || entry.expires.Before(expires) {
+			oldest, expires = subject, entry.expires
+		}
+	}
+
+	if len(c.entries) >= streamNameCacheSize {
+		delete(c.entries, oldest)
+	}
+}
+
+// clear drops all entries, as streams were created or deleted.
+func (c *streamNameCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
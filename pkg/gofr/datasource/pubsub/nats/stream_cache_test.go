@@ -0,0 +1,103 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+func TestStreamNameCache(t *testing.T) {
+	clk := newFakeClock()
+	cache := newStreamNameCache(time.Minute, clk)
+
+	cache.set("orders.created", "orders", true)
+	cache.set("unknown.subject", "", false)
+
+	stream, found, ok := cache.get("orders.created")
+	assert.True(t, ok)
+	assert.True(t, found)
+	assert.Equal(t, "orders", stream)
+
+	_, found, ok = cache.get("unknown.subject")
+	assert.True(t, ok)
+	assert.False(t, found)
+
+	// entries expire after the TTL
+	clk.Advance(time.Minute + time.Second)
+
+	_, _, ok = cache.get("orders.created")
+	assert.False(t, ok)
+
+	cache.set("orders.created", "orders", true)
+	cache.clear()
+
+	_, _, ok = cache.get("orders.created")
+	assert.False(t, ok)
+
+	// without a TTL nothing is cached
+	disabled := newStreamNameCache(0, clk)
+	disabled.set("orders.created", "orders", true)
+
+	_, _, ok = disabled.get("orders.created")
+	assert.False(t, ok)
+}
+
+func TestStreamNameCache_Eviction(t *testing.T) {
+	clk := newFakeClock()
+	cache := newStreamNameCache(time.Minute, clk)
+
+	for i := range streamNameCacheSize {
+		cache.set(fmt.Sprintf("orders.%d", i), "orders", true)
+		clk.Advance(time.Millisecond)
+	}
+
+	// a full cache drops the entry closest to expiry
+	cache.set("payments.created", "payments", true)
+	assert.Len(t, cache.entries, streamNameCacheSize)
+
+	_, _, ok := cache.get("orders.0")
+	assert.False(t, ok)
+
+	_, _, ok = cache.get("payments.created")
+	assert.True(t, ok)
+
+	// or all expired entries when there are any
+	clk.Advance(time.Minute)
+	cache.set("billing.created", "billing", true)
+	assert.Len(t, cache.entries, 2)
+}
+
+func TestClient_CreateStream_ClearsStreamNameCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreamManager := NewMockStreamManagerInterface(ctrl)
+	client := &Client{
+		streamManager: mockStreamManager,
+		streamNames:   newStreamNameCache(time.Minute, nil),
+		Config:        &Config{},
+		logger:        logging.NewMockLogger(logging.DEBUG),
+	}
+	ctx := context.Background()
+
+	mockStreamManager.EXPECT().CreateStream(ctx, StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}}).Return(nil)
+	mockStreamManager.EXPECT().DeleteStream(ctx, "orders").Return(nil)
+
+	client.streamNames.set("orders.created", "", false)
+	require.NoError(t, client.CreateStream(ctx, StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}}))
+
+	_, _, ok := client.streamNames.get("orders.created")
+	assert.False(t, ok)
+
+	client.streamNames.set("orders.created", "orders", true)
+	require.NoError(t, client.DeleteStream(ctx, "orders"))
+
+	_, _, ok = client.streamNames.get("orders.created")
+	assert.False(t, ok)
+}
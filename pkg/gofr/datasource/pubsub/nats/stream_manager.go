@@ -3,6 +3,8 @@ package nats
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/nats-io/nats.go/jetstream"
 	"gofr.dev/pkg/gofr/datasource/pubsub"
@@ -12,6 +14,12 @@ import (
 type StreamManager struct {
 	js     jetstream.JetStream
 	logger pubsub.Logger
+	// dryRun validates and logs stream changes instead of applying them.
+	dryRun bool
+	// verifyOnly makes CreateStream check that the stream exists instead of creating it.
+	verifyOnly bool
+	// updateIfExists makes CreateStream update a stream that exists with a different configuration.
+	updateIfExists bool
 }
 
 // newStreamManager creates a new StreamManager.
@@ -25,13 +33,34 @@ func newStreamManager(js jetstream.JetStream, logger pubsub.Logger) *StreamManag
 // CreateStream creates a new jStream stream.
 func (sm *StreamManager) CreateStream(ctx context.Context, cfg StreamConfig) error {
 	sm.logger.Debugf("creating stream %s", cfg.Stream)
-	jsCfg := jetstream.StreamConfig{
-		Name:     cfg.Stream,
-		Subjects: cfg.Subjects,
-		MaxBytes: cfg.MaxBytes,
+
+	if err := validateStreamConfig(&cfg); err != nil {
+		return err
+	}
+
+	if sm.verifyOnly {
+		return sm.verifyStream(ctx, cfg.Stream)
+	}
+
+	jsCfg := jetStreamConfig(&cfg)
+
+	if sm.dryRun {
+		sm.logger.Logf("dry run: would create stream %s with subjects %v", cfg.Stream, cfg.Subjects)
+
+		return nil
+	}
+
+	err := runWithContext(ctx, func() error {
+		_, err := sm.js.CreateStream(ctx, jsCfg)
+
+		return err
+	})
+	if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) && sm.updateIfExists {
+		sm.logger.Debugf("stream %s exists with a different configuration, updating it", cfg.Stream)
+
+		return sm.updateStream(ctx, jsCfg)
 	}
 
-	_, err := sm.js.CreateStream(ctx, jsCfg)
 	if err != nil {
 		sm.logger.Errorf("failed to create stream: %v", err)
 		return err
@@ -40,11 +69,170 @@ func (sm *StreamManager) CreateStream(ctx context.Context, cfg StreamConfig) err
 	return nil
 }
 
+// UpdateStream changes the configuration of an existing jStream stream, e.g. its retention or limits.
+func (sm *StreamManager) UpdateStream(ctx context.Context, cfg StreamConfig) error {
+	sm.logger.Debugf("updating stream %s", cfg.Stream)
+
+	if err := validateStreamConfig(&cfg); err != nil {
+		return err
+	}
+
+	if sm.dryRun {
+		sm.logger.Logf("dry run: would update stream %s with subjects %v", cfg.Stream, cfg.Subjects)
+
+		return nil
+	}
+
+	return sm.updateStream(ctx, jetStreamConfig(&cfg))
+}
+
+func (sm *StreamManager) updateStream(ctx context.Context, jsCfg jetstream.StreamConfig) error {
+	err := runWithContext(ctx, func() error {
+		_, err := sm.js.UpdateStream(ctx, jsCfg)
+
+		return err
+	})
+	if err != nil {
+		sm.logger.Errorf("failed to update stream %s: %v", jsCfg.Name, err)
+
+		return err
+	}
+
+	sm.logger.Debugf("successfully updated stream %s", jsCfg.Name)
+
+	return nil
+}
+
+// jetStreamConfig converts a StreamConfig into its jStream form.
+func jetStreamConfig(cfg *StreamConfig) jetstream.StreamConfig {
+	replicas := cfg.Replicas
+	if replicas <= 0 {
+		replicas = 1
+	}
+
+	jsCfg := jetstream.StreamConfig{
+		Name:       cfg.Stream,
+		Subjects:   cfg.Subjects,
+		MaxBytes:   cfg.MaxBytes,
+		MaxAge:     cfg.MaxAge,
+		MaxMsgs:    cfg.MaxMsgs,
+		Replicas:   replicas,
+		Retention:  cfg.Retention,
+		Storage:    cfg.Storage,
+		Discard:    cfg.Discard,
+		Duplicates: cfg.DuplicateWindow,
+		Mirror:     streamSource(cfg.Mirror),
+	}
+
+	if cfg.RePublish != nil {
+		jsCfg.RePublish = &jetstream.RePublish{
+			Source:      cfg.RePublish.Source,
+			Destination: cfg.RePublish.Destination,
+			HeadersOnly: cfg.RePublish.HeadersOnly,
+		}
+	}
+
+	for i := range cfg.Sources {
+		jsCfg.Sources = append(jsCfg.Sources, streamSource(&cfg.Sources[i]))
+	}
+
+	return jsCfg
+}
+
+// runWithContext runs op unless ctx is already done. The jStream calls op makes honour ctx, so an
+// error op returns once ctx is done is wrapped with errStreamOperationAborted.
+func runWithContext(ctx context.Context, op func() error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", errStreamOperationAborted, err)
+	}
+
+	err := op()
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", errStreamOperationAborted, err)
+	}
+
+	return err
+}
+
+// verifyStream returns errStreamMissing when the stream with the given name does not exist.
+func (sm *StreamManager) verifyStream(ctx context.Context, name string) error {
+	_, err := sm.js.Stream(ctx, name)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		sm.logger.Errorf("stream %s does not exist and automatic stream creation is disabled", name)
+
+		return fmt.Errorf("%w: %s", errStreamMissing, name)
+	}
+
+	if err != nil {
+		sm.logger.Errorf("failed to verify stream %s: %v", name, err)
+
+		return err
+	}
+
+	sm.logger.Debugf("stream %s exists, attaching to it", name)
+
+	return nil
+}
+
+// validateStreamConfig checks the settings of a stream the server would reject.
+func validateStreamConfig(cfg *StreamConfig) error {
+	if cfg.Stream == "" {
+		return jetstream.ErrStreamNameRequired
+	}
+
+	if strings.ContainsAny(cfg.Stream, ">*. /\\") {
+		return fmt.Errorf("%w: %s", jetstream.ErrInvalidStreamName, cfg.Stream)
+	}
+
+	if cfg.Mirror != nil && len(cfg.Subjects) > 0 {
+		return errMirrorWithSubjects
+	}
+
+	if cfg.RePublish != nil && (cfg.RePublish.Source == "" || cfg.RePublish.Destination == "") {
+		return errInvalidRePublish
+	}
+
+	if cfg.Storage != jetstream.FileStorage && cfg.Storage != jetstream.MemoryStorage {
+		return fmt.Errorf("%w: %d", errInvalidStorageType, cfg.Storage)
+	}
+
+	return nil
+}
+
+// streamSource converts a StreamSource into its jStream form, returning nil for a nil source.
+func streamSource(src *StreamSource) *jetstream.StreamSource {
+	if src == nil {
+		return nil
+	}
+
+	source := &jetstream.StreamSource{
+		Name:          src.Name,
+		FilterSubject: src.FilterSubject,
+		OptStartSeq:   src.StartSequence,
+		Domain:        src.Domain,
+	}
+
+	if !src.StartTime.IsZero() {
+		startTime := src.StartTime
+		source.OptStartTime = &startTime
+	}
+
+	return source
+}
+
 // DeleteStream deletes a jStream stream.
 func (sm *StreamManager) DeleteStream(ctx context.Context, name string) error {
 	sm.logger.Debugf("deleting stream %s", name)
 
-	err := sm.js.DeleteStream(ctx, name)
+	if sm.dryRun {
+		sm.logger.Logf("dry run: would delete stream %s", name)
+
+		return nil
+	}
+
+	err := runWithContext(ctx, func() error {
+		return sm.js.DeleteStream(ctx, name)
+	})
 	if err != nil {
 		if errors.Is(err, jetstream.ErrStreamNotFound) {
 			sm.logger.Debugf("stream %s not found, considering delete successful", name)
@@ -95,3 +283,57 @@ func (sm *StreamManager) GetStream(ctx context.Context, name string) (jetstream.
 
 	return stream, nil
 }
+
+// ConsumerInfo returns the state of a consumer, including its pending, delivered and ack floor counts.
+func (sm *StreamManager) ConsumerInfo(ctx context.Context, stream, consumer string) (*jetstream.ConsumerInfo, error) {
+	cons, err := sm.js.Consumer(ctx, stream, consumer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer %s on stream %s: %w", consumer, stream, err)
+	}
+
+	info, err := cons.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info of consumer %s on stream %s: %w", consumer, stream, err)
+	}
+
+	return info, nil
+}
+
+// PurgeStream removes the messages of a stream while keeping the stream itself. The opts can limit
+// the purge to a subject or sequence, see jetstream.WithPurgeSubject and jetstream.WithPurgeSequence.
+func (sm *StreamManager) PurgeStream(ctx context.Context, stream string, opts ...jetstream.StreamPurgeOpt) error {
+	s, err := sm.js.Stream(ctx, stream)
+	if err != nil {
+		sm.logger.Errorf("failed to get stream %s: %v", stream, err)
+
+		return err
+	}
+
+	if err := s.Purge(ctx, opts...); err != nil {
+		sm.logger.Errorf("failed to purge stream %s: %v", stream, err)
+
+		return err
+	}
+
+	sm.logger.Logf("purged stream %s", stream)
+
+	return nil
+}
+
+// StreamNames returns the names of all streams on the server.
+func (sm *StreamManager) StreamNames(ctx context.Context) ([]string, error) {
+	lister := sm.js.StreamNames(ctx)
+
+	names := make([]string, 0)
+	for name := range lister.Name() {
+		names = append(names, name)
+	}
+
+	if err := lister.Err(); err != nil {
+		sm.logger.Errorf("failed to list streams: %v", err)
+
+		return nil, err
+	}
+
+	return names, nil
+}
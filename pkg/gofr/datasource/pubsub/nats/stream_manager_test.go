@@ -3,12 +3,14 @@ package nats
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"gofr.dev/pkg/gofr/logging"
+	"gofr.dev/pkg/gofr/testutil"
 )
 
 func TestNewStreamManager(t *testing.T) {
@@ -46,6 +48,136 @@ func TestStreamManager_CreateStream(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStreamManager_CreateStream_Config(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		cfg      StreamConfig
+		expected jetstream.StreamConfig
+	}{
+		{
+			desc: "defaults",
+			cfg:  StreamConfig{Stream: "test-stream", Subjects: []string{"test.subject"}},
+			expected: jetstream.StreamConfig{
+				Name:     "test-stream",
+				Subjects: []string{"test.subject"},
+				Replicas: 1,
+			},
+		},
+		{
+			desc: "retention settings",
+			cfg: StreamConfig{
+				Stream:    "test-stream",
+				Subjects:  []string{"test.subject"},
+				MaxBytes:  1024,
+				MaxAge:    time.Hour,
+				MaxMsgs:   100,
+				Replicas:  3,
+				Retention: jetstream.WorkQueuePolicy,
+			},
+			expected: jetstream.StreamConfig{
+				Name:      "test-stream",
+				Subjects:  []string{"test.subject"},
+				MaxBytes:  1024,
+				MaxAge:    time.Hour,
+				MaxMsgs:   100,
+				Replicas:  3,
+				Retention: jetstream.WorkQueuePolicy,
+			},
+		},
+		{
+			desc: "memory storage",
+			cfg:  StreamConfig{Stream: "test-stream", Subjects: []string{"test.subject"}, Storage: jetstream.MemoryStorage},
+			expected: jetstream.StreamConfig{
+				Name:     "test-stream",
+				Subjects: []string{"test.subject"},
+				Replicas: 1,
+				Storage:  jetstream.MemoryStorage,
+			},
+		},
+		{
+			desc: "discard policy and duplicate window",
+			cfg: StreamConfig{
+				Stream:          "test-stream",
+				Subjects:        []string{"test.subject"},
+				MaxMsgs:         100,
+				Discard:         jetstream.DiscardNew,
+				DuplicateWindow: 10 * time.Minute,
+			},
+			expected: jetstream.StreamConfig{
+				Name:       "test-stream",
+				Subjects:   []string{"test.subject"},
+				MaxMsgs:    100,
+				Replicas:   1,
+				Discard:    jetstream.DiscardNew,
+				Duplicates: 10 * time.Minute,
+			},
+		},
+		{
+			desc: "mirror",
+			cfg: StreamConfig{
+				Stream: "orders-mirror",
+				Mirror: &StreamSource{Name: "orders", Domain: "eu-west", StartSequence: 42},
+			},
+			expected: jetstream.StreamConfig{
+				Name:     "orders-mirror",
+				Replicas: 1,
+				Mirror:   &jetstream.StreamSource{Name: "orders", Domain: "eu-west", OptStartSeq: 42},
+			},
+		},
+		{
+			desc: "sources",
+			cfg: StreamConfig{
+				Stream:   "orders-all",
+				Subjects: []string{"orders.local"},
+				Sources: []StreamSource{
+					{Name: "orders-eu", FilterSubject: "orders.eu.>", StartTime: startTime},
+					{Name: "orders-us"},
+				},
+			},
+			expected: jetstream.StreamConfig{
+				Name:     "orders-all",
+				Subjects: []string{"orders.local"},
+				Replicas: 1,
+				Sources: []*jetstream.StreamSource{
+					{Name: "orders-eu", FilterSubject: "orders.eu.>", OptStartTime: &startTime},
+					{Name: "orders-us"},
+				},
+			},
+		},
+		{
+			desc: "republish",
+			cfg: StreamConfig{
+				Stream:    "orders",
+				Subjects:  []string{"orders.>"},
+				RePublish: &RePublish{Source: "orders.>", Destination: "audit.orders.>", HeadersOnly: true},
+			},
+			expected: jetstream.StreamConfig{
+				Name:      "orders",
+				Subjects:  []string{"orders.>"},
+				Replicas:  1,
+				RePublish: &jetstream.RePublish{Source: "orders.>", Destination: "audit.orders.>", HeadersOnly: true},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		mockJS.EXPECT().CreateStream(ctx, tc.expected).Return(nil, nil)
+
+		err := sm.CreateStream(ctx, tc.cfg)
+
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
 func TestStreamManager_CreateStream_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -69,6 +201,172 @@ func TestStreamManager_CreateStream_Error(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestStreamManager_CreateStream_UpdateIfExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+	cfg := StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}, MaxAge: time.Hour}
+	expected := jetstream.StreamConfig{Name: "orders", Subjects: []string{"orders.>"}, MaxAge: time.Hour, Replicas: 1}
+
+	// without UpdateStreamIfExists, a stream with a different configuration is left as it is
+	mockJS.EXPECT().CreateStream(ctx, expected).Return(nil, jetstream.ErrStreamNameAlreadyInUse)
+
+	require.ErrorIs(t, sm.CreateStream(ctx, cfg), jetstream.ErrStreamNameAlreadyInUse)
+
+	sm.updateIfExists = true
+
+	mockJS.EXPECT().CreateStream(ctx, expected).Return(nil, jetstream.ErrStreamNameAlreadyInUse)
+	mockJS.EXPECT().UpdateStream(ctx, expected).Return(nil, nil)
+
+	require.NoError(t, sm.CreateStream(ctx, cfg))
+}
+
+func TestStreamManager_UpdateStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().UpdateStream(ctx, jetstream.StreamConfig{
+		Name:     "orders",
+		Subjects: []string{"orders.>"},
+		MaxMsgs:  1000,
+		Replicas: 1,
+	}).Return(nil, nil)
+
+	require.NoError(t, sm.UpdateStream(ctx, StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}, MaxMsgs: 1000}))
+
+	mockJS.EXPECT().UpdateStream(ctx, gomock.Any()).Return(nil, jetstream.ErrStreamNotFound)
+
+	require.ErrorIs(t, sm.UpdateStream(ctx, StreamConfig{Stream: "missing", Subjects: []string{"missing"}}),
+		jetstream.ErrStreamNotFound)
+
+	require.ErrorIs(t, sm.UpdateStream(ctx, StreamConfig{}), jetstream.ErrStreamNameRequired)
+}
+
+func TestStreamManager_CreateStream_ContextCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a context done before the call never reaches the server
+	mockJS.EXPECT().CreateStream(gomock.Any(), gomock.Any()).Times(0)
+
+	err := sm.CreateStream(ctx, StreamConfig{Stream: "test-stream", Subjects: []string{"test.subject"}})
+	require.ErrorIs(t, err, errStreamOperationAborted)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStreamManager_CreateStream_MirrorWithSubjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := newStreamManager(NewMockJetStream(ctrl), logging.NewMockLogger(logging.DEBUG))
+
+	err := sm.CreateStream(context.Background(), StreamConfig{
+		Stream:   "orders-mirror",
+		Subjects: []string{"orders.>"},
+		Mirror:   &StreamSource{Name: "orders"},
+	})
+	require.ErrorIs(t, err, errMirrorWithSubjects)
+}
+
+func TestStreamManager_CreateStream_InvalidRePublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := newStreamManager(NewMockJetStream(ctrl), logging.NewMockLogger(logging.DEBUG))
+
+	err := sm.CreateStream(context.Background(), StreamConfig{
+		Stream:    "orders",
+		Subjects:  []string{"orders.>"},
+		RePublish: &RePublish{Source: "orders.>"},
+	})
+	require.ErrorIs(t, err, errInvalidRePublish)
+}
+
+func TestStreamManager_CreateStream_InvalidStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := newStreamManager(NewMockJetStream(ctrl), logging.NewMockLogger(logging.DEBUG))
+
+	err := sm.CreateStream(context.Background(), StreamConfig{
+		Stream:   "orders",
+		Subjects: []string{"orders.>"},
+		Storage:  jetstream.StorageType(7),
+	})
+	require.ErrorIs(t, err, errInvalidStorageType)
+}
+
+func TestStreamManager_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the mock fails the test on any call, so nothing reaches the server
+	sm := &StreamManager{js: NewMockJetStream(ctrl), dryRun: true}
+	ctx := context.Background()
+
+	out := testutil.StdoutOutputForFunc(func() {
+		sm.logger = logging.NewMockLogger(logging.DEBUG)
+
+		require.NoError(t, sm.CreateStream(ctx, StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}}))
+		require.NoError(t, sm.DeleteStream(ctx, "orders"))
+	})
+
+	assert.Contains(t, out, "dry run: would create stream orders with subjects [orders.>]")
+	assert.Contains(t, out, "dry run: would delete stream orders")
+
+	// invalid configurations are still reported
+	err := sm.CreateStream(ctx, StreamConfig{Stream: "orders.eu", Subjects: []string{"orders.>"}})
+	require.ErrorIs(t, err, jetstream.ErrInvalidStreamName)
+
+	err = sm.CreateStream(ctx, StreamConfig{Subjects: []string{"orders.>"}})
+	require.ErrorIs(t, err, jetstream.ErrStreamNameRequired)
+}
+
+func TestStreamManager_CreateStream_AutoCreateDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+	sm.verifyOnly = true
+
+	ctx := context.Background()
+	cfg := StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}}
+
+	// the stream is only looked up, never created
+	mockJS.EXPECT().Stream(ctx, "orders").Return(NewMockStream(ctrl), nil)
+	require.NoError(t, sm.CreateStream(ctx, cfg))
+
+	mockJS.EXPECT().Stream(ctx, "orders").Return(nil, jetstream.ErrStreamNotFound)
+	require.ErrorIs(t, sm.CreateStream(ctx, cfg), errStreamMissing)
+
+	mockJS.EXPECT().Stream(ctx, "orders").Return(nil, errJetStream)
+	require.ErrorIs(t, sm.CreateStream(ctx, cfg), errJetStream)
+}
+
+func TestAutoCreateStream(t *testing.T) {
+	disabled, enabled := false, true
+
+	assert.True(t, autoCreateStream(&Config{}))
+	assert.True(t, autoCreateStream(&Config{AutoCreateStream: &enabled}))
+	assert.False(t, autoCreateStream(&Config{AutoCreateStream: &disabled}))
+}
+
 func TestStreamManager_DeleteStream(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -125,6 +423,28 @@ func TestStreamManager_DeleteStream_Error(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestStreamManager_DeleteStream_DeadlineExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// the server never answers, so the call returns once the deadline passes
+	mockJS.EXPECT().DeleteStream(ctx, "test-stream").DoAndReturn(func(ctx context.Context, _ string) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	err := sm.DeleteStream(ctx, "test-stream")
+	require.ErrorIs(t, err, errStreamOperationAborted)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestStreamManager_CreateOrUpdateStream(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -232,3 +552,139 @@ func TestStreamManager_GetStream_Error(t *testing.T) {
 	assert.Nil(t, stream)
 	assert.Equal(t, expectedErr, err)
 }
+
+func TestStreamManager_ConsumerInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+	expected := &jetstream.ConsumerInfo{
+		Stream:     "test-stream",
+		Name:       "test-consumer",
+		NumPending: 5,
+		Delivered:  jetstream.SequenceInfo{Consumer: 10, Stream: 12},
+		AckFloor:   jetstream.SequenceInfo{Consumer: 8, Stream: 9},
+	}
+
+	mockJS.EXPECT().Consumer(ctx, "test-stream", "test-consumer").Return(mockConsumer, nil)
+	mockConsumer.EXPECT().Info(ctx).Return(expected, nil)
+
+	info, err := sm.ConsumerInfo(ctx, "test-stream", "test-consumer")
+	require.NoError(t, err)
+	assert.Same(t, expected, info)
+}
+
+func TestStreamManager_ConsumerInfo_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().Consumer(ctx, "test-stream", "missing").Return(nil, jetstream.ErrConsumerNotFound)
+
+	_, err := sm.ConsumerInfo(ctx, "test-stream", "missing")
+	require.ErrorIs(t, err, jetstream.ErrConsumerNotFound)
+	assert.Contains(t, err.Error(), "consumer missing on stream test-stream")
+
+	mockJS.EXPECT().Consumer(ctx, "test-stream", "test-consumer").Return(mockConsumer, nil)
+	mockConsumer.EXPECT().Info(ctx).Return(nil, errJetStream)
+
+	_, err = sm.ConsumerInfo(ctx, "test-stream", "test-consumer")
+	require.ErrorIs(t, err, errJetStream)
+}
+
+func TestStreamManager_PurgeStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockStream := NewMockStream(ctrl)
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().Stream(ctx, "test-stream").Return(mockStream, nil)
+	mockStream.EXPECT().Purge(ctx, gomock.Len(1)).Return(nil)
+
+	out := testutil.StdoutOutputForFunc(func() {
+		sm := newStreamManager(mockJS, logging.NewMockLogger(logging.INFO))
+
+		err := sm.PurgeStream(ctx, "test-stream", jetstream.WithPurgeSubject("test.subject"))
+		require.NoError(t, err)
+	})
+
+	assert.Contains(t, out, "purged stream test-stream")
+}
+
+func TestStreamManager_PurgeStream_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockStream := NewMockStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().Stream(ctx, "missing").Return(nil, jetstream.ErrStreamNotFound)
+
+	err := sm.PurgeStream(ctx, "missing")
+	require.ErrorIs(t, err, jetstream.ErrStreamNotFound)
+
+	mockJS.EXPECT().Stream(ctx, "test-stream").Return(mockStream, nil)
+	mockStream.EXPECT().Purge(ctx).Return(errJetStream)
+
+	err = sm.PurgeStream(ctx, "test-stream")
+	require.ErrorIs(t, err, errJetStream)
+}
+
+// streamNameLister lists a fixed set of stream names, failing with err once they are listed.
+type streamNameLister struct {
+	names []string
+	err   error
+}
+
+func (l *streamNameLister) Name() <-chan string {
+	names := make(chan string, len(l.names))
+
+	for _, name := range l.names {
+		names <- name
+	}
+
+	close(names)
+
+	return names
+}
+
+func (l *streamNameLister) Err() error {
+	return l.err
+}
+
+func TestStreamManager_StreamNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newStreamManager(mockJS, logging.NewMockLogger(logging.DEBUG))
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().StreamNames(ctx).Return(&streamNameLister{names: []string{"orders", "payments"}})
+
+	names, err := sm.StreamNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders", "payments"}, names)
+
+	mockJS.EXPECT().StreamNames(ctx).Return(&streamNameLister{names: []string{"orders"}, err: errJetStream})
+
+	names, err = sm.StreamNames(ctx)
+	require.ErrorIs(t, err, errJetStream)
+	assert.Nil(t, names)
+}
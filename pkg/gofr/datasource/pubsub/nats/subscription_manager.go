@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"gofr.dev/pkg/gofr/datasource/pubsub"
 )
@@ -22,10 +22,18 @@ type SubscriptionManager struct {
 	topicBuffers  map[string]chan *pubsub.Message
 	bufferMutex   sync.RWMutex
 	bufferSize    int
+	active        *subscriptionCount
+	paused        *pausedSubjects
+	filter        *subscribeFilter
+	// clock defaults to the real clock when nil.
+	clock clock
 }
 
 type subscription struct {
 	cancel context.CancelFunc
+	// failed is closed once err stopped the subscription, e.g. because its consumer was deleted.
+	failed chan struct{}
+	err    error
 }
 
 func newSubscriptionManager(bufferSize int) *SubscriptionManager {
@@ -43,7 +51,7 @@ func (sm *SubscriptionManager) Subscribe(
 	cfg *Config,
 	logger pubsub.Logger,
 	metrics Metrics) (*pubsub.Message, error) {
-	metrics.IncrementCounter(ctx, "app_pubsub_subscribe_total_count", "topic", topic)
+	metrics.IncrementCounter(ctx, "app_pubsub_subscribe_total_count", subscribeLabels(topic, cfg)...)
 
 	if err := sm.validateSubscribePrerequisites(js, cfg); err != nil {
 		return nil, err
@@ -51,40 +59,152 @@ func (sm *SubscriptionManager) Subscribe(
 
 	sm.subMutex.Lock()
 
-	_, exists := sm.subscriptions[topic]
+	var created jetstream.Consumer
+
+	sub, exists := sm.subscriptions[topic]
 	if !exists {
-		cons, err := sm.createOrUpdateConsumer(ctx, js, topic, cfg)
+		cons, err := sm.createOrUpdateConsumer(ctx, js, topic, cfg, logger)
 		if err != nil {
 			sm.subMutex.Unlock()
 			return nil, err
 		}
 
 		subCtx, cancel := context.WithCancel(ctx)
-		sm.subscriptions[topic] = &subscription{cancel: cancel}
+		sub = &subscription{cancel: cancel, failed: make(chan struct{})}
+		sm.subscriptions[topic] = sub
+		sm.active.add(1)
+
+		created = cons
 
 		buffer := sm.getOrCreateBuffer(topic)
-		go sm.consumeMessages(subCtx, cons, topic, buffer, cfg, logger)
+		dlq := newDeadLetterQueue(js, cfg, logger, metrics)
+
+		go func(sub *subscription) {
+			err := sm.consumeMessages(subCtx, js, cons, topic, buffer, cfg, logger, metrics, dlq)
+			sm.releaseSubscription(topic, sub, err)
+		}(sub)
+
+		if cfg.LagReportInterval > 0 {
+			go reportConsumerLag(subCtx, cons, clockOrReal(sm.clock), cfg.LagReportInterval, cfg, logger, metrics)
+		}
 	}
 
 	sm.subMutex.Unlock()
 
+	if created != nil {
+		notifySubscribed(cfg, topic, created, logger)
+	}
+
 	buffer := sm.getOrCreateBuffer(topic)
 
+	// only the call creating the subscription waits for its first message within the deadline
+	var firstMessage <-chan time.Time
+
+	if created != nil && cfg.firstMessageDeadline > 0 {
+		timer := clockOrReal(sm.clock).NewTimer(cfg.firstMessageDeadline)
+		defer timer.Stop()
+
+		firstMessage = timer.C()
+	}
+
 	select {
-	case msg := <-buffer:
-		metrics.IncrementCounter(ctx, "app_pubsub_subscribe_success_count", "topic", topic)
+	case msg, ok := <-buffer:
+		// the buffer is closed when the subscription manager is closed
+		if !ok {
+			return nil, nil
+		}
+
+		if cfg.MaxBufferedMessages > 0 {
+			metrics.SetGauge(bufferDepthMetric, float64(len(buffer)), "subject", topic)
+		}
+
+		metrics.RecordHistogram(ctx, messageBytesMetric, float64(len(msg.Value)),
+			streamLabels(cfg, consumerStream(cfg, topic), "direction", "subscribe")...)
+		metrics.IncrementCounter(ctx, "app_pubsub_subscribe_success_count", subscribeLabels(topic, cfg)...)
 		return msg, nil
+	case <-firstMessage:
+		return nil, fmt.Errorf("%w on %s within %v", errNoFirstMessage, topic, cfg.firstMessageDeadline)
+	case <-sub.failed:
+		return nil, sub.err
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 }
 
+// Unsubscribe stops consuming from topic and reports whether there was a subscription to stop.
+func (sm *SubscriptionManager) Unsubscribe(topic string) bool {
+	sm.subMutex.Lock()
+	defer sm.subMutex.Unlock()
+
+	sub, exists := sm.subscriptions[topic]
+	if !exists {
+		return false
+	}
+
+	sub.cancel()
+	delete(sm.subscriptions, topic)
+	sm.active.add(-1)
+
+	sm.bufferMutex.Lock()
+	delete(sm.topicBuffers, topic)
+	sm.bufferMutex.Unlock()
+
+	return true
+}
+
+// releaseSubscription removes sub once its consumer stopped, so that the next Subscribe call on the
+// topic starts consuming again instead of waiting on a buffer nothing fills.
+func (sm *SubscriptionManager) releaseSubscription(topic string, sub *subscription, err error) {
+	sm.subMutex.Lock()
+	defer sm.subMutex.Unlock()
+
+	if err != nil {
+		sub.err = err
+		close(sub.failed)
+	}
+
+	if sm.subscriptions[topic] == sub {
+		sub.cancel()
+		delete(sm.subscriptions, topic)
+		sm.active.add(-1)
+	}
+}
+
+// notifySubscribed calls Config.OnSubscribed for the consumer of subject, recovering from a panic in it.
+func notifySubscribed(cfg *Config, subject string, cons jetstream.Consumer, logger pubsub.Logger) {
+	if cfg.OnSubscribed == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("OnSubscribed callback panicked for subject %s: %v", subject, r)
+		}
+	}()
+
+	var consumer string
+
+	if info := cons.CachedInfo(); info != nil {
+		consumer = info.Name
+	}
+
+	cfg.OnSubscribed(subject, consumer)
+}
+
+func subscribeLabels(topic string, cfg *Config) []string {
+	if cfg.QueueGroup == "" {
+		return []string{"topic", topic}
+	}
+
+	return []string{"topic", topic, "queue_group", cfg.QueueGroup}
+}
+
 func (*SubscriptionManager) validateSubscribePrerequisites(js jetstream.JetStream, cfg *Config) error {
 	if js == nil {
 		return errJetStreamNotConfigured
 	}
 
-	if cfg.Consumer == "" {
+	if isDurable(cfg) && cfg.Consumer == "" && cfg.QueueGroup == "" {
 		return errConsumerNotProvided
 	}
 
@@ -106,88 +226,327 @@ func (sm *SubscriptionManager) getOrCreateBuffer(topic string) chan *pubsub.Mess
 }
 
 func (*SubscriptionManager) createOrUpdateConsumer(
-	ctx context.Context, js jetstream.JetStream, topic string, cfg *Config) (jetstream.Consumer, error) {
-	consumerName := fmt.Sprintf("%s_%s", cfg.Consumer, strings.ReplaceAll(topic, ".", "_"))
-	cons, err := js.CreateOrUpdateConsumer(ctx, cfg.Stream.Stream, jetstream.ConsumerConfig{
-		Durable:       consumerName,
+	ctx context.Context, js jetstream.JetStream, topic string, cfg *Config, logger pubsub.Logger) (jetstream.Consumer, error) {
+	if cfg.OrderedConsumer {
+		return js.OrderedConsumer(ctx, consumerStream(cfg, topic), orderedConsumerConfig(cfg, topic))
+	}
+
+	if cfg.AttachOnly {
+		return attachConsumer(ctx, js, consumerStream(cfg, topic), durableName(cfg, topic))
+	}
+
+	consumerCfg := jetstream.ConsumerConfig{
 		AckPolicy:     jetstream.AckExplicitPolicy,
-		FilterSubject: topic,
-		MaxDeliver:    cfg.Stream.MaxDeliver,
-		DeliverPolicy: jetstream.DeliverNewPolicy,
-		AckWait:       30 * time.Second,
-	})
+		FilterSubject: consumerFilterSubject(cfg, topic),
+		MaxDeliver:    consumerMaxDeliver(cfg),
+		AckWait:       consumerAckWait(cfg),
+		BackOff:       cfg.BackoffSchedule,
+	}
+
+	consumerCfg.DeliverPolicy, consumerCfg.OptStartSeq, consumerCfg.OptStartTime = deliverOptions(cfg, jetstream.DeliverNewPolicy)
+
+	// ephemeral consumers are created without a durable name
+	if isDurable(cfg) {
+		consumerCfg.Durable = durableName(cfg, topic)
+	}
+
+	return createConsumer(ctx, js, consumerStream(cfg, topic), consumerCfg, logger)
+}
+
+// createConsumer creates or updates the consumer on stream. When another client creates the same
+// durable consumer at the same time, the server may report that it already exists; the consumer
+// is then attached to instead.
+func createConsumer(
+	ctx context.Context, js jetstream.JetStream, stream string, cfg jetstream.ConsumerConfig, logger pubsub.Logger) (jetstream.Consumer, error) {
+	cons, err := js.CreateOrUpdateConsumer(ctx, stream, cfg)
+	if !errors.Is(err, jetstream.ErrConsumerExists) || cfg.Durable == "" {
+		return cons, err
+	}
+
+	logger.Debugf("consumer %s already exists on stream %s, attaching to it", cfg.Durable, stream)
+
+	return js.Consumer(ctx, stream, cfg.Durable)
+}
+
+// attachConsumer returns the existing consumer on stream without creating or updating it.
+func attachConsumer(ctx context.Context, js jetstream.JetStream, stream, consumer string) (jetstream.Consumer, error) {
+	cons, err := js.Consumer(ctx, stream, consumer)
+	if errors.Is(err, jetstream.ErrConsumerNotFound) {
+		return nil, fmt.Errorf("%w: %s on stream %s", errConsumerNotFound, consumer, stream)
+	}
 
 	return cons, err
 }
 
+// consumeMessages fetches the messages of topic into buffer until ctx is done. It returns
+// errConsumerDeleted when the consumer was deleted on the server, unless
+// Config.RecreateConsumerOnDelete is set, in which case the consumer is created again on js.
 func (sm *SubscriptionManager) consumeMessages(
 	ctx context.Context,
+	js jetstream.JetStream,
 	cons jetstream.Consumer,
 	topic string,
 	buffer chan *pubsub.Message,
 	cfg *Config,
-	logger pubsub.Logger) {
+	logger pubsub.Logger,
+	metrics Metrics,
+	dlq *deadLetterQueue) error {
 	// TODO: propagate errors to caller
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return nil
 		default:
-			if err := sm.fetchAndProcessMessages(ctx, cons, topic, buffer, cfg, logger); err != nil {
+			if sm.paused.wait(ctx, topic) != nil {
+				return nil
+			}
+
+			err := sm.fetchAndProcessMessages(ctx, cons, topic, buffer, cfg, logger, metrics, dlq)
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if isConsumerDeleted(err) {
+				if !cfg.RecreateConsumerOnDelete {
+					logger.Logf("WARN: consumer for topic %s was deleted, stopping the subscription", topic)
+
+					return fmt.Errorf("%w for topic %s", errConsumerDeleted, topic)
+				}
+
+				logger.Logf("WARN: consumer for topic %s was deleted, recreating it", topic)
+
+				cons = sm.recreateConsumer(ctx, js, cons, topic, cfg, logger)
+
+				continue
+			}
+
+			if err != nil {
 				logger.Errorf("Error fetching messages for topic %s: %v", topic, err)
 			}
 		}
 	}
 }
 
+// recreateConsumer creates the consumer of topic again, returning cons when that fails so that
+// the next fetch retries.
+func (sm *SubscriptionManager) recreateConsumer(ctx context.Context, js jetstream.JetStream, cons jetstream.Consumer,
+	topic string, cfg *Config, logger pubsub.Logger) jetstream.Consumer {
+	recreated, err := sm.createOrUpdateConsumer(ctx, js, topic, cfg, logger)
+	if err != nil {
+		logger.Errorf("failed to recreate consumer for topic %s: %v", topic, err)
+
+		select {
+		case <-ctx.Done():
+		case <-clockOrReal(sm.clock).After(consumeMessageDelay):
+		}
+
+		return cons
+	}
+
+	return recreated
+}
+
+// isConsumerDeleted reports whether err is caused by the consumer no longer existing on the server.
+func isConsumerDeleted(err error) bool {
+	return errors.Is(err, jetstream.ErrConsumerNotFound) || errors.Is(err, jetstream.ErrConsumerDeleted)
+}
+
 func (sm *SubscriptionManager) fetchAndProcessMessages(
 	ctx context.Context,
 	cons jetstream.Consumer,
 	topic string,
 	buffer chan *pubsub.Message,
 	cfg *Config,
-	logger pubsub.Logger) error {
-	msgs, err := cons.Fetch(1, jetstream.FetchMaxWait(cfg.MaxWait))
+	logger pubsub.Logger,
+	metrics Metrics,
+	dlq *deadLetterQueue) error {
+	size := fetchBatchSize(cfg)
+
+	if cfg.MaxBufferedMessages > 0 {
+		room, err := waitForBufferRoom(ctx, clockOrReal(sm.clock), buffer, cfg.MaxBufferedMessages, topic, metrics)
+		if err != nil {
+			return err
+		}
+
+		size = min(size, room)
+	}
+
+	msgs, err := cons.Fetch(size, fetchOptions(cfg)...)
 	if err != nil {
-		return sm.handleFetchError(err, topic, logger)
+		if ctx.Err() == nil {
+			recordFetchError(ctx, err, topic, metrics)
+		}
+
+		// leave a deleted consumer to consumeMessages instead of fetching from it again
+		if isConsumerDeleted(err) {
+			return err
+		}
+
+		return sm.handleFetchError(ctx, err, topic, logger)
 	}
 
-	return sm.processFetchedMessages(msgs, topic, buffer, logger)
-}
+	count, err := sm.processFetchedMessages(ctx, msgs, topic, buffer, cfg, logger, metrics, dlq)
+	if ctx.Err() != nil {
+		return err
+	}
 
-func (sm *SubscriptionManager) handleFetchError(err error, topic string, logger pubsub.Logger) error {
-	if !errors.Is(err, context.DeadlineExceeded) {
-		logger.Errorf("Error fetching messages for topic %s: %v", topic, err)
+	if err != nil {
+		recordFetchError(ctx, err, topic, metrics)
+
+		// a fetch that timed out without messages is not a failure
+		if !isFetchTimeout(err) {
+			return err
+		}
 	}
 
-	time.Sleep(consumeMessageDelay)
+	if count == 0 {
+		return waitAfterEmptyFetch(ctx, clockOrReal(sm.clock), cfg)
+	}
 
 	return nil
 }
 
+// waitAfterEmptyFetch pauses for Config.EmptyFetchBackoff after a fetch returned no messages, so that
+// a short MaxWait on an empty stream does not turn the consume loop into a busy loop.
+func waitAfterEmptyFetch(ctx context.Context, clk clock, cfg *Config) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clk.After(emptyFetchBackoff(cfg)):
+		return nil
+	}
+}
+
+// waitForBufferRoom blocks until the buffer holds fewer than limit messages, reporting its depth as the
+// app_pubsub_buffer_depth gauge, and returns how many more messages it can take.
+func waitForBufferRoom(ctx context.Context, clk clock, buffer chan *pubsub.Message, limit int, topic string, metrics Metrics) (int, error) {
+	limit = min(limit, cap(buffer))
+
+	for {
+		depth := len(buffer)
+		metrics.SetGauge(bufferDepthMetric, float64(depth), "subject", topic)
+
+		if depth < limit {
+			return limit - depth, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-clk.After(consumeMessageDelay):
+		}
+	}
+}
+
+// isFetchTimeout reports whether a fetch ended because MaxWait elapsed before any message arrived.
+func isFetchTimeout(err error) bool {
+	return errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// recordFetchError counts a failed fetch as a timeout or as an error.
+func recordFetchError(ctx context.Context, err error, topic string, metrics Metrics) {
+	if isFetchTimeout(err) {
+		metrics.IncrementCounter(ctx, fetchTimeoutCountMetric, "subject", topic)
+
+		return
+	}
+
+	metrics.IncrementCounter(ctx, fetchErrorCountMetric, "subject", topic)
+}
+
+func (sm *SubscriptionManager) handleFetchError(ctx context.Context, err error, topic string, logger pubsub.Logger) error {
+	if !isFetchTimeout(err) {
+		logger.Errorf("Error fetching messages for topic %s: %v", topic, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clockOrReal(sm.clock).After(consumeMessageDelay):
+		return nil
+	}
+}
+
+// processFetchedMessages moves the messages of a batch to the buffer and returns how many it received.
 func (sm *SubscriptionManager) processFetchedMessages(
+	ctx context.Context,
 	msgs jetstream.MessageBatch,
 	topic string,
 	buffer chan *pubsub.Message,
-	logger pubsub.Logger) error {
-	for msg := range msgs.Messages() {
-		pubsubMsg := sm.createPubSubMessage(msg, topic)
+	cfg *Config,
+	logger pubsub.Logger,
+	metrics Metrics,
+	dlq *deadLetterQueue) (int, error) {
+	messages := msgs.Messages()
+	count := 0
+
+	// a fetch ends once the batch is complete or MaxWait elapsed; stop waiting for it when ctx is done
+	for {
+		var msg jetstream.Msg
+
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		case m, ok := <-messages:
+			if !ok {
+				return count, sm.checkBatchError(msgs, topic, logger)
+			}
+
+			msg = m
+			count++
+		}
+
+		reportPoisonSuspect(ctx, msg, cfg, logger, metrics)
+
+		if dlq.handle(ctx, msg) {
+			continue
+		}
+
+		pubsubMsg, err := sm.createPubSubMessage(msg, cfg)
+		if err != nil {
+			// redelivering a corrupt payload cannot succeed
+			logger.Errorf("failed to decompress message on %s: %v", msg.Subject(), err)
+
+			if err := msg.Term(); err != nil {
+				logger.Errorf("failed to terminate message on %s: %v", msg.Subject(), err)
+			}
+
+			continue
+		}
+
+		if sm.filter.drop(ctx, msg, pubsubMsg, logger, metrics) {
+			continue
+		}
 
 		if !sm.sendToBuffer(pubsubMsg, buffer) {
 			logger.Logf("Message buffer is full for topic %s. Consider increasing buffer size or processing messages faster.", topic)
 		}
 	}
+}
 
-	return sm.checkBatchError(msgs, topic, logger)
+func (*SubscriptionManager) createPubSubMessage(msg jetstream.Msg, cfg *Config) (*pubsub.Message, error) {
+	return newPubSubMessage(msg, cfg)
 }
 
-func (sm *SubscriptionManager) createPubSubMessage(msg jetstream.Msg, topic string) *pubsub.Message {
-	pubsubMsg := pubsub.NewMessage(context.Background()) // Pass a context if needed
-	pubsubMsg.Topic = topic
-	pubsubMsg.Value = msg.Data()
-	pubsubMsg.MetaData = msg.Headers()
-	pubsubMsg.Committer = &natsCommitter{msg: msg}
-	return pubsubMsg
+// newPubSubMessage converts msg, decompressing its payload when it is gzip encoded.
+func newPubSubMessage(msg jetstream.Msg, cfg *Config) (*pubsub.Message, error) {
+	headers := msg.Headers()
+
+	value, err := decompressPayload(cfg, msg.Data(), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	// The message context carries the publisher's trace context so that handler spans join its trace.
+	pubsubMsg := pubsub.NewMessage(extractTraceContext(context.Background(), headers))
+	// Topic is the subject the message was published on, which for wildcard subscriptions
+	// differs from the subscribed one, without SubjectPrefix.
+	pubsubMsg.Topic = trimSubjectPrefix(cfg, msg.Subject())
+	pubsubMsg.Value = value
+	pubsubMsg.MetaData = headers
+	pubsubMsg.Committer = &natsCommitter{msg: msg, manualAck: cfg.ManualAck || cfg.OrderedConsumer, doubleAck: cfg.DoubleAck}
+
+	return pubsubMsg, nil
 }
 
 func (sm *SubscriptionManager) sendToBuffer(msg *pubsub.Message, buffer chan *pubsub.Message) bool {
@@ -201,7 +560,9 @@ func (sm *SubscriptionManager) sendToBuffer(msg *pubsub.Message, buffer chan *pu
 
 func (sm *SubscriptionManager) checkBatchError(msgs jetstream.MessageBatch, topic string, logger pubsub.Logger) error {
 	if err := msgs.Error(); err != nil {
-		logger.Errorf("Error in message batch for topic %s: %v", topic, err)
+		if !isFetchTimeout(err) {
+			logger.Errorf("Error in message batch for topic %s: %v", topic, err)
+		}
 
 		return err
 	}
@@ -215,6 +576,7 @@ func (sm *SubscriptionManager) Close() {
 		sub.cancel()
 	}
 
+	sm.active.add(-len(sm.subscriptions))
 	sm.subscriptions = make(map[string]*subscription)
 	sm.subMutex.Unlock()
 
@@ -5,12 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"gofr.dev/pkg/gofr/datasource/pubsub"
 	"gofr.dev/pkg/gofr/logging"
+	"gofr.dev/pkg/gofr/testutil"
 )
 
 func TestNewSubscriptionManager(t *testing.T) {
@@ -48,6 +50,8 @@ func TestSubscriptionManager_Subscribe(t *testing.T) {
 	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), cfg.Stream.Stream, gomock.Any()).Return(mockConsumer, nil)
 	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_total_count", "topic", topic)
 	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(createMockMessageBatch(ctrl), nil).AnyTimes()
+	mockMetrics.EXPECT().RecordHistogram(gomock.Any(), "app_pubsub_message_bytes", float64(len("test message")),
+		"stream", "test-stream", "direction", "subscribe")
 	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_success_count", "topic", topic)
 
 	msg, err := sm.Subscribe(ctx, topic, mockJS, cfg, mockLogger, mockMetrics)
@@ -56,6 +60,206 @@ func TestSubscriptionManager_Subscribe(t *testing.T) {
 	assert.Equal(t, topic, msg.Topic)
 }
 
+func TestSubscriptionManager_Subscribe_OnSubscribed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	type subscribed struct{ subject, consumer string }
+
+	events := make([]subscribed, 0, 1)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{
+		Consumer: "test-consumer",
+		Stream:   StreamConfig{Stream: "test-stream"},
+		MaxWait:  time.Second,
+		OnSubscribed: func(subject, consumer string) {
+			events = append(events, subscribed{subject: subject, consumer: consumer})
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil)
+	mockConsumer.EXPECT().CachedInfo().Return(&jetstream.ConsumerInfo{Name: "test-consumer_test_topic"})
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMsg := NewMockMsg(ctrl)
+
+	// every fetch gets a fresh batch, set up here so that the consume loop registers no expectations
+	mockMsg.EXPECT().Subject().Return("test.topic").AnyTimes()
+	mockMsg.EXPECT().Data().Return([]byte("test message")).AnyTimes()
+	mockMsg.EXPECT().Headers().Return(nil).AnyTimes()
+	mockBatch.EXPECT().Messages().DoAndReturn(func() <-chan jetstream.Msg {
+		msgChan := make(chan jetstream.Msg, 1)
+		msgChan <- mockMsg
+		close(msgChan)
+
+		return msgChan
+	}).AnyTimes()
+	mockBatch.EXPECT().Error().Return(nil).AnyTimes()
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil).AnyTimes()
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockMetrics.EXPECT().RecordHistogram(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	// the callback fires once, when the consumer of the subscription is created
+	for range 2 {
+		_, err := sm.Subscribe(ctx, "test.topic", mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []subscribed{{subject: "test.topic", consumer: "test-consumer_test_topic"}}, events)
+}
+
+func TestNotifySubscribed_Panic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumer := NewMockConsumer(ctrl)
+	mockConsumer.EXPECT().CachedInfo().Return(nil)
+
+	cfg := &Config{OnSubscribed: func(string, string) { panic("not ready") }}
+
+	out := testutil.StderrOutputForFunc(func() {
+		notifySubscribed(cfg, "test.topic", mockConsumer, logging.NewMockLogger(logging.DEBUG))
+	})
+
+	assert.Contains(t, out, "OnSubscribed callback panicked for subject test.topic: not ready")
+
+	// without a callback the consumer is not inspected
+	notifySubscribed(&Config{}, "test.topic", mockConsumer, logging.NewMockLogger(logging.DEBUG))
+}
+
+func TestSubscriptionManager_Subscribe_ContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}, MaxWait: time.Minute}
+	topic := "test.topic"
+
+	fetching := make(chan struct{})
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_total_count", "topic", topic)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).DoAndReturn(func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+		close(fetching)
+
+		return mockBatch, nil
+	})
+	// the batch never delivers a message, blocking the fetch until MaxWait
+	mockBatch.EXPECT().Messages().Return(make(chan jetstream.Msg))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-fetching
+		cancel()
+	}()
+
+	msg, err := sm.Subscribe(ctx, topic, mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, msg)
+
+	// the consuming goroutine exits and releases the subscription
+	assert.Eventually(t, func() bool {
+		sm.subMutex.Lock()
+		defer sm.subMutex.Unlock()
+
+		return len(sm.subscriptions) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSubscriptionManager_Subscribe_FirstMessageDeadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := SubscribeOptions{FirstMessageDeadline: 50 * time.Millisecond}.apply(
+		&Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}, MaxWait: time.Minute})
+	topic := "test.topic"
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(mockConsumer, nil)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_total_count", "topic", topic)
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil)
+	// the batch never delivers a message, so the fetch never returns
+	mockBatch.EXPECT().Messages().Return(make(chan jetstream.Msg))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+
+	msg, err := sm.Subscribe(ctx, topic, mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+	require.ErrorIs(t, err, errNoFirstMessage)
+	assert.Nil(t, msg)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSubscriptionManager_Subscribe_BatchSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+	mockLogger := logging.NewMockLogger(logging.DEBUG)
+
+	sm := newSubscriptionManager(batchSize)
+	cfg := &Config{
+		Consumer: "test-consumer",
+		Stream: StreamConfig{
+			Stream: "test-stream",
+		},
+		MaxWait:   time.Second,
+		BatchSize: 10,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	topic := "test.topic"
+	release := make(chan struct{})
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), cfg.Stream.Stream, gomock.Any()).Return(mockConsumer, nil)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_total_count", "topic", topic).Times(10)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_success_count", "topic", topic).Times(10)
+	mockMetrics.EXPECT().RecordHistogram(gomock.Any(), "app_pubsub_message_bytes", gomock.Any(),
+		"stream", "test-stream", "direction", "subscribe").Times(10)
+
+	gomock.InOrder(
+		mockConsumer.EXPECT().Fetch(10, gomock.Any()).Return(createMockMessageBatchOfSize(ctrl, 10), nil).Times(1),
+		mockConsumer.EXPECT().Fetch(10, gomock.Any()).
+			DoAndReturn(func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+				<-release
+				return nil, context.DeadlineExceeded
+			}).AnyTimes(),
+	)
+
+	for i := 0; i < 10; i++ {
+		msg, err := sm.Subscribe(ctx, topic, mockJS, cfg, mockLogger, mockMetrics)
+		require.NoError(t, err)
+		assert.Equal(t, topic, msg.Topic)
+	}
+
+	sm.Close()
+	close(release)
+}
+
 func TestSubscriptionManager_Subscribe_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -135,11 +339,327 @@ func TestSubscriptionManager_createOrUpdateConsumer(t *testing.T) {
 
 	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, cfg.Stream.Stream, gomock.Any()).Return(mockConsumer, nil)
 
-	consumer, err := sm.createOrUpdateConsumer(ctx, mockJS, topic, cfg)
+	consumer, err := sm.createOrUpdateConsumer(ctx, mockJS, topic, cfg, logging.NewMockLogger(logging.DEBUG))
 	require.NoError(t, err)
 	assert.Equal(t, mockConsumer, consumer)
 }
 
+func TestSubscriptionManager_createOrUpdateConsumer_AlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}}
+	ctx := context.Background()
+
+	// another replica created the consumer first; the existing consumer is attached to
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "test-stream", gomock.Any()).Return(nil, jetstream.ErrConsumerExists)
+	mockJS.EXPECT().Consumer(ctx, "test-stream", "test-consumer_test_topic").Return(mockConsumer, nil)
+
+	var consumer jetstream.Consumer
+
+	out := testutil.StdoutOutputForFunc(func() {
+		var err error
+
+		consumer, err = sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", cfg, logging.NewMockLogger(logging.DEBUG))
+		require.NoError(t, err)
+	})
+
+	assert.Equal(t, mockConsumer, consumer)
+	assert.Contains(t, out, "consumer test-consumer_test_topic already exists on stream test-stream, attaching to it")
+
+	// ephemeral consumers have no name to attach to
+	cfg = &Config{Ephemeral: true, Stream: StreamConfig{Stream: "test-stream"}}
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "test-stream", gomock.Any()).Return(nil, jetstream.ErrConsumerExists)
+
+	_, err := sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.ErrorIs(t, err, jetstream.ErrConsumerExists)
+}
+
+func TestSubscriptionManager_createOrUpdateConsumer_AttachOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{Consumer: "provisioned", AttachOnly: true, Stream: StreamConfig{Stream: "test-stream"}}
+	ctx := context.Background()
+
+	// the consumer is bound to by its name, without a consumer configuration being sent
+	mockJS.EXPECT().Consumer(ctx, "test-stream", "provisioned").Return(mockConsumer, nil)
+
+	consumer, err := sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.NoError(t, err)
+	assert.Equal(t, mockConsumer, consumer)
+
+	mockJS.EXPECT().Consumer(ctx, "test-stream", "provisioned").Return(nil, jetstream.ErrConsumerNotFound)
+
+	_, err = sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.ErrorIs(t, err, errConsumerNotFound)
+	require.ErrorContains(t, err, "provisioned on stream test-stream")
+}
+
+func TestSubscriptionManager_createOrUpdateConsumer_Ephemeral(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{
+		Ephemeral: true,
+		Stream: StreamConfig{
+			Stream: "test-stream",
+		},
+	}
+
+	ctx := context.Background()
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, cfg.Stream.Stream, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Empty(t, consumerCfg.Durable)
+			assert.Equal(t, "test.topic", consumerCfg.FilterSubject)
+
+			return mockConsumer, nil
+		})
+
+	err := sm.validateSubscribePrerequisites(mockJS, cfg)
+	require.NoError(t, err)
+
+	consumer, err := sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.NoError(t, err)
+	assert.Equal(t, mockConsumer, consumer)
+}
+
+func TestSubscriptionManager_createOrUpdateConsumer_Redelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newSubscriptionManager(1)
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc       string
+		cfg        *Config
+		ackWait    time.Duration
+		maxDeliver int
+		backOff    []time.Duration
+	}{
+		{
+			desc:       "defaults",
+			cfg:        &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}},
+			ackWait:    30 * time.Second,
+			maxDeliver: 0,
+		},
+		{
+			desc:       "stream max deliver",
+			cfg:        &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream", MaxDeliver: 3}},
+			ackWait:    30 * time.Second,
+			maxDeliver: 3,
+		},
+		{
+			desc: "configured values",
+			cfg: &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream", MaxDeliver: 3},
+				AckWait: time.Minute, MaxDeliver: 5},
+			ackWait:    time.Minute,
+			maxDeliver: 5,
+		},
+		{
+			desc: "backoff schedule",
+			cfg: &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}, MaxDeliver: 5,
+				BackoffSchedule: []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}},
+			ackWait:    30 * time.Second,
+			maxDeliver: 5,
+			backOff:    []time.Duration{time.Second, 5 * time.Second, 30 * time.Second},
+		},
+	}
+
+	for i, tc := range testCases {
+		mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "test-stream", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+				assert.Equal(t, tc.ackWait, consumerCfg.AckWait, "TEST[%d], Failed.\n%s", i, tc.desc)
+				assert.Equal(t, tc.maxDeliver, consumerCfg.MaxDeliver, "TEST[%d], Failed.\n%s", i, tc.desc)
+				assert.Equal(t, tc.backOff, consumerCfg.BackOff, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+				return NewMockConsumer(ctrl), nil
+			})
+
+		_, err := sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", tc.cfg, logging.NewMockLogger(logging.DEBUG))
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSubscriptionManager_createOrUpdateConsumer_FilterSubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newSubscriptionManager(1)
+	ctx := context.Background()
+
+	cfg := &Config{
+		Consumer:      "payments",
+		Stream:        StreamConfig{Stream: "events", Subjects: []string{"events.*"}},
+		FilterSubject: "events.payment",
+	}
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "events", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Equal(t, "events.payment", consumerCfg.FilterSubject)
+
+			return NewMockConsumer(ctrl), nil
+		})
+
+	_, err := sm.createOrUpdateConsumer(ctx, mockJS, "events.*", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.NoError(t, err)
+
+	// without a filter subject the subscribed subject is used
+	cfg.FilterSubject = ""
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "events", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Equal(t, "events.*", consumerCfg.FilterSubject)
+
+			return NewMockConsumer(ctrl), nil
+		})
+
+	_, err = sm.createOrUpdateConsumer(ctx, mockJS, "events.*", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.NoError(t, err)
+}
+
+func TestSubscriptionManager_createOrUpdateConsumer_Ordered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	sm := newSubscriptionManager(1)
+	ctx := context.Background()
+
+	cfg := &Config{Stream: StreamConfig{Stream: "events", Subjects: []string{"events.>"}}, OrderedConsumer: true}
+
+	mockJS.EXPECT().OrderedConsumer(ctx, "events", jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{"events.>"},
+		DeliverPolicy:  jetstream.DeliverAllPolicy,
+	}).Return(mockConsumer, nil)
+
+	require.NoError(t, sm.validateSubscribePrerequisites(mockJS, cfg))
+
+	consumer, err := sm.createOrUpdateConsumer(ctx, mockJS, "events.>", cfg, logging.NewMockLogger(logging.DEBUG))
+	require.NoError(t, err)
+	assert.Equal(t, mockConsumer, consumer)
+
+	// messages of ordered consumers are not acknowledged on commit
+	mockMsg := NewMockMsg(ctrl)
+	mockMsg.EXPECT().Subject().Return("events.created")
+	mockMsg.EXPECT().Data().Return([]byte("test message"))
+	mockMsg.EXPECT().Headers().Return(nil)
+
+	msg, err := sm.createPubSubMessage(mockMsg, cfg)
+	require.NoError(t, err)
+
+	msg.Commit()
+}
+
+func TestSubscriptionManager_createOrUpdateConsumer_StartFrom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	sm := newSubscriptionManager(1)
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		desc      string
+		startFrom StartFrom
+		policy    jetstream.DeliverPolicy
+		startSeq  uint64
+		startTime *time.Time
+	}{
+		{desc: "unset", policy: jetstream.DeliverNewPolicy},
+		{desc: "start sequence", startFrom: StartFrom{StartSequence: 42}, policy: jetstream.DeliverByStartSequencePolicy, startSeq: 42},
+		{desc: "start time", startFrom: StartFrom{StartTime: startTime}, policy: jetstream.DeliverByStartTimePolicy, startTime: &startTime},
+	}
+
+	for i, tc := range testCases {
+		cfg := &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}, StartFrom: tc.startFrom}
+
+		mockJS.EXPECT().CreateOrUpdateConsumer(ctx, "test-stream", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+				assert.Equal(t, tc.policy, consumerCfg.DeliverPolicy, "TEST[%d], Failed.\n%s", i, tc.desc)
+				assert.Equal(t, tc.startSeq, consumerCfg.OptStartSeq, "TEST[%d], Failed.\n%s", i, tc.desc)
+				assert.Equal(t, tc.startTime, consumerCfg.OptStartTime, "TEST[%d], Failed.\n%s", i, tc.desc)
+
+				return NewMockConsumer(ctrl), nil
+			})
+
+		_, err := sm.createOrUpdateConsumer(ctx, mockJS, "test.topic", cfg, logging.NewMockLogger(logging.DEBUG))
+		require.NoError(t, err, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestOrderedConsumerConfig_StartFrom(t *testing.T) {
+	cfg := orderedConsumerConfig(&Config{StartFrom: StartFrom{StartSequence: 7}}, "events.>")
+
+	assert.Equal(t, jetstream.DeliverByStartSequencePolicy, cfg.DeliverPolicy)
+	assert.Equal(t, uint64(7), cfg.OptStartSeq)
+
+	cfg = orderedConsumerConfig(&Config{}, "events.>")
+
+	assert.Equal(t, jetstream.DeliverAllPolicy, cfg.DeliverPolicy)
+}
+
+func TestSubscriptionManager_Subscribe_QueueGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{
+		QueueGroup: "workers",
+		Stream: StreamConfig{
+			Stream: "test-stream",
+		},
+		MaxWait: time.Second,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	topic := "test.topic"
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), cfg.Stream.Stream, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Equal(t, "workers_test_topic", consumerCfg.Durable)
+
+			return mockConsumer, nil
+		})
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_total_count",
+		"topic", topic, "queue_group", "workers")
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(createMockMessageBatch(ctrl), nil).AnyTimes()
+	mockMetrics.EXPECT().RecordHistogram(gomock.Any(), "app_pubsub_message_bytes", gomock.Any(), gomock.Any()).AnyTimes()
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_success_count",
+		"topic", topic, "queue_group", "workers")
+
+	msg, err := sm.Subscribe(ctx, topic, mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+	require.NoError(t, err)
+	assert.Equal(t, topic, msg.Topic)
+
+	sm.Close()
+}
+
 func TestSubscriptionManager_consumeMessages(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -158,7 +678,7 @@ func TestSubscriptionManager_consumeMessages(t *testing.T) {
 	mockBatch := createMockMessageBatch(ctrl)
 	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil).AnyTimes()
 
-	go sm.consumeMessages(ctx, mockConsumer, topic, buffer, cfg, mockLogger)
+	go sm.consumeMessages(ctx, nil, mockConsumer, topic, buffer, cfg, mockLogger, NewMockMetrics(ctrl), nil)
 
 	select {
 	case msg := <-buffer:
@@ -169,10 +689,286 @@ func TestSubscriptionManager_consumeMessages(t *testing.T) {
 	}
 }
 
+func TestSubscriptionManager_consumeMessages_ConsumerDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	deletedConsumer := NewMockConsumer(ctrl)
+	recreatedConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{
+		Consumer:                 "test-consumer",
+		Stream:                   StreamConfig{Stream: "test-stream", Subjects: []string{"test.topic"}},
+		MaxWait:                  time.Second,
+		RecreateConsumerOnDelete: true,
+	}
+	buffer := make(chan *pubsub.Message, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the consumer is deleted once, then fetching resumes from the recreated consumer
+	deletedConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, jetstream.ErrConsumerNotFound)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_fetch_error_count", "subject", "test.topic")
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "test-stream", gomock.Any()).Return(recreatedConsumer, nil)
+	recreatedConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(createMockMessageBatch(ctrl), nil).AnyTimes()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- sm.consumeMessages(ctx, mockJS, deletedConsumer, "test.topic", buffer, cfg,
+			logging.NewMockLogger(logging.DEBUG), mockMetrics, nil)
+	}()
+
+	select {
+	case msg := <-buffer:
+		assert.Equal(t, "test.topic", msg.Topic)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for message")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+
+	// without RecreateConsumerOnDelete, the subscription stops
+	stoppedConsumer := NewMockConsumer(ctrl)
+	stoppedConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(nil, jetstream.ErrConsumerDeleted)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_fetch_error_count", "subject", "test.topic")
+
+	cfg.RecreateConsumerOnDelete = false
+
+	err := sm.consumeMessages(context.Background(), mockJS, stoppedConsumer, "test.topic", buffer, cfg,
+		logging.NewMockLogger(logging.DEBUG), mockMetrics, nil)
+	require.ErrorIs(t, err, errConsumerDeleted)
+}
+
+func TestSubscriptionManager_fetchAndProcessMessages_IdleHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumer := NewMockConsumer(ctrl)
+	sm := newSubscriptionManager(1)
+	cfg := &Config{MaxWait: time.Second, FlowControl: true, IdleHeartbeat: 200 * time.Millisecond}
+	buffer := make(chan *pubsub.Message, 1)
+
+	// the heartbeat is passed to the fetch request along with the max wait
+	mockConsumer.EXPECT().Fetch(1, gomock.Any(), gomock.Any()).Return(createMockMessageBatch(ctrl), nil)
+
+	err := sm.fetchAndProcessMessages(context.Background(), mockConsumer, "test.topic", buffer, cfg,
+		logging.NewMockLogger(logging.DEBUG), NewMockMetrics(ctrl), nil)
+	require.NoError(t, err)
+	assert.Len(t, buffer, 1)
+}
+
+func TestSubscriptionManager_fetchAndProcessMessages_FetchErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{MaxWait: time.Second}
+	buffer := make(chan *pubsub.Message, 1)
+
+	emptyBatch := func(batchErr error) jetstream.MessageBatch {
+		msgChan := make(chan jetstream.Msg)
+		close(msgChan)
+
+		batch := NewMockMessageBatch(ctrl)
+		batch.EXPECT().Messages().Return(msgChan)
+		batch.EXPECT().Error().Return(batchErr)
+
+		return batch
+	}
+
+	testCases := []struct {
+		desc     string
+		batch    jetstream.MessageBatch
+		fetchErr error
+		metric   string
+		err      error
+	}{
+		{desc: "batch timed out", batch: emptyBatch(nats.ErrTimeout), metric: "app_pubsub_fetch_timeout_count"},
+		{desc: "fetch timed out", fetchErr: context.DeadlineExceeded, metric: "app_pubsub_fetch_timeout_count"},
+		{desc: "batch failed", batch: emptyBatch(errConnectionError), metric: "app_pubsub_fetch_error_count", err: errConnectionError},
+		{desc: "fetch failed", fetchErr: errConnectionError, metric: "app_pubsub_fetch_error_count"},
+	}
+
+	for i, tc := range testCases {
+		mockConsumer := NewMockConsumer(ctrl)
+		mockMetrics := NewMockMetrics(ctrl)
+
+		mockConsumer.EXPECT().Fetch(1, gomock.Any()).Return(tc.batch, tc.fetchErr)
+		mockMetrics.EXPECT().IncrementCounter(gomock.Any(), tc.metric, "subject", "test.topic")
+
+		err := sm.fetchAndProcessMessages(context.Background(), mockConsumer, "test.topic", buffer, cfg,
+			logging.NewMockLogger(logging.DEBUG), mockMetrics, nil)
+
+		require.ErrorIs(t, err, tc.err, "TEST[%d], Failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSubscriptionManager_consumeMessages_MaxBufferedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(10)
+	cfg := &Config{MaxWait: time.Second, BatchSize: 5, MaxBufferedMessages: 2}
+	buffer := make(chan *pubsub.Message, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetched := make(chan int, 2)
+
+	mockMetrics.EXPECT().SetGauge("app_pubsub_buffer_depth", gomock.Any(), "subject", "test.topic").AnyTimes()
+
+	// the first fetch is limited to the buffer size; the next one waits until a message is consumed
+	gomock.InOrder(
+		mockConsumer.EXPECT().Fetch(2, gomock.Any()).Return(createMockMessageBatchOfSize(ctrl, 2), nil),
+		mockConsumer.EXPECT().Fetch(1, gomock.Any()).
+			DoAndReturn(func(size int, _ ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+				fetched <- size
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			}),
+	)
+
+	go sm.consumeMessages(ctx, nil, mockConsumer, "test.topic", buffer, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics, nil)
+
+	require.Eventually(t, func() bool { return len(buffer) == 2 }, time.Second, 10*time.Millisecond)
+
+	select {
+	case <-fetched:
+		t.Fatal("fetched while the buffer was full")
+	case <-time.After(3 * consumeMessageDelay):
+	}
+
+	<-buffer
+
+	select {
+	case size := <-fetched:
+		assert.Equal(t, 1, size)
+	case <-time.After(time.Second):
+		t.Fatal("did not fetch after a message was consumed")
+	}
+}
+
+func TestSubscriptionManager_consumeMessages_EmptyFetchBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const backoff = 50 * time.Millisecond
+
+	mockConsumer := NewMockConsumer(ctrl)
+	cfg := &Config{MaxWait: time.Millisecond, EmptyFetchBackoff: backoff}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetches := make(chan time.Time, 3)
+
+	emptyBatch := NewMockMessageBatch(ctrl)
+	emptyMessages := make(chan jetstream.Msg)
+	close(emptyMessages)
+
+	emptyBatch.EXPECT().Messages().Return(emptyMessages).AnyTimes()
+	// jetstream reports an empty fetch either as a timeout or as an empty batch
+	emptyBatch.EXPECT().Error().Return(nats.ErrTimeout).Times(1)
+	emptyBatch.EXPECT().Error().Return(nil).AnyTimes()
+
+	mockConsumer.EXPECT().Fetch(1, gomock.Any()).
+		DoAndReturn(func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+			select {
+			case fetches <- time.Now():
+			default:
+				cancel()
+			}
+
+			return emptyBatch, nil
+		}).MinTimes(3)
+
+	mockMetrics := NewMockMetrics(ctrl)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_fetch_timeout_count", "subject", "test.topic").AnyTimes()
+
+	sm := newSubscriptionManager(1)
+	sm.consumeMessages(ctx, nil, mockConsumer, "test.topic", make(chan *pubsub.Message, 1), cfg,
+		logging.NewMockLogger(logging.DEBUG), mockMetrics, nil)
+
+	first, second, third := <-fetches, <-fetches, <-fetches
+
+	assert.GreaterOrEqual(t, second.Sub(first), backoff)
+	assert.GreaterOrEqual(t, third.Sub(second), backoff)
+}
+
+func TestEmptyFetchBackoff(t *testing.T) {
+	assert.Equal(t, defaultEmptyFetchBackoff, emptyFetchBackoff(&Config{}))
+	assert.Equal(t, time.Second, emptyFetchBackoff(&Config{EmptyFetchBackoff: time.Second}))
+}
+
+func TestFetchOptions(t *testing.T) {
+	assert.Len(t, fetchOptions(&Config{MaxWait: time.Second}), 1)
+	assert.Len(t, fetchOptions(&Config{MaxWait: time.Second, FlowControl: true, IdleHeartbeat: 200 * time.Millisecond}), 2)
+}
+
+func TestSubscriptionManager_Subscribe_Wildcard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockBatch := NewMockMessageBatch(ctrl)
+	mockMsg := NewMockMsg(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{
+		Consumer: "test-consumer",
+		Stream:   StreamConfig{Stream: "orders", Subjects: []string{"orders.>"}},
+		MaxWait:  time.Second,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	msgChan := make(chan jetstream.Msg, 1)
+	msgChan <- mockMsg
+	close(msgChan)
+
+	mockMsg.EXPECT().Subject().Return("orders.created")
+	mockMsg.EXPECT().Data().Return([]byte("test message"))
+	mockMsg.EXPECT().Headers().Return(nil)
+	mockBatch.EXPECT().Messages().Return(msgChan).AnyTimes()
+	mockBatch.EXPECT().Error().Return(nil).AnyTimes()
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), "orders", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, consumerCfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+			assert.Equal(t, "test-consumer_orders_all", consumerCfg.Durable)
+			assert.Equal(t, "orders.>", consumerCfg.FilterSubject)
+
+			return mockConsumer, nil
+		})
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).Return(mockBatch, nil).AnyTimes()
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), gomock.Any(), "topic", "orders.>").Times(2)
+	mockMetrics.EXPECT().RecordHistogram(gomock.Any(), "app_pubsub_message_bytes", gomock.Any(), gomock.Any()).AnyTimes()
+
+	msg, err := sm.Subscribe(ctx, "orders.>", mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+	require.NoError(t, err)
+	assert.Equal(t, "orders.created", msg.Topic)
+
+	sm.Close()
+}
+
 func createMockMessageBatch(ctrl *gomock.Controller) jetstream.MessageBatch {
 	mockBatch := NewMockMessageBatch(ctrl)
 	mockMsg := NewMockMsg(ctrl)
 
+	mockMsg.EXPECT().Subject().Return("test.topic").AnyTimes()
 	mockMsg.EXPECT().Data().Return([]byte("test message")).AnyTimes()
 	mockMsg.EXPECT().Headers().Return(nil).AnyTimes()
 
@@ -186,6 +982,44 @@ func createMockMessageBatch(ctrl *gomock.Controller) jetstream.MessageBatch {
 	return mockBatch
 }
 
+func createMockMessageBatchOfSize(ctrl *gomock.Controller, size int) jetstream.MessageBatch {
+	mockBatch := NewMockMessageBatch(ctrl)
+	msgChan := make(chan jetstream.Msg, size)
+
+	for i := 0; i < size; i++ {
+		mockMsg := NewMockMsg(ctrl)
+		mockMsg.EXPECT().Subject().Return("test.topic").AnyTimes()
+		mockMsg.EXPECT().Data().Return([]byte("test message")).AnyTimes()
+		mockMsg.EXPECT().Headers().Return(nil).AnyTimes()
+
+		msgChan <- mockMsg
+	}
+
+	close(msgChan)
+
+	mockBatch.EXPECT().Messages().Return(msgChan).AnyTimes()
+	mockBatch.EXPECT().Error().Return(nil).AnyTimes()
+
+	return mockBatch
+}
+
+func TestSubscriptionManager_Unsubscribe(t *testing.T) {
+	sm := newSubscriptionManager(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sm.subscriptions["test.topic"] = &subscription{cancel: cancel}
+	sm.getOrCreateBuffer("test.topic")
+
+	assert.True(t, sm.Unsubscribe("test.topic"))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.NotContains(t, sm.subscriptions, "test.topic")
+	assert.NotContains(t, sm.topicBuffers, "test.topic")
+
+	assert.False(t, sm.Unsubscribe("test.topic"))
+}
+
 func TestSubscriptionManager_Close(t *testing.T) {
 	sm := newSubscriptionManager(1)
 	topic := "test.topic"
@@ -205,3 +1039,81 @@ func TestSubscriptionManager_Close(t *testing.T) {
 		t.Fatal("Context was not canceled")
 	}
 }
+
+func TestSubscriptionManager_Subscribe_Closed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockConsumer := NewMockConsumer(ctrl)
+	mockMetrics := NewMockMetrics(ctrl)
+
+	sm := newSubscriptionManager(1)
+	cfg := &Config{Consumer: "test-consumer", Stream: StreamConfig{Stream: "test-stream"}, MaxWait: time.Second}
+	topic := "test.topic"
+
+	mockJS.EXPECT().CreateOrUpdateConsumer(gomock.Any(), cfg.Stream.Stream, gomock.Any()).Return(mockConsumer, nil)
+	mockMetrics.EXPECT().IncrementCounter(gomock.Any(), "app_pubsub_subscribe_total_count", "topic", topic)
+
+	// the fetch is still pending when the manager is closed
+	stop := make(chan struct{})
+	defer close(stop)
+
+	mockConsumer.EXPECT().Fetch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+			<-stop
+
+			return nil, context.Canceled
+		}).AnyTimes()
+
+	done := make(chan struct{})
+
+	var (
+		msg *pubsub.Message
+		err error
+	)
+
+	go func() {
+		defer close(done)
+
+		msg, err = sm.Subscribe(context.Background(), topic, mockJS, cfg, logging.NewMockLogger(logging.DEBUG), mockMetrics)
+	}()
+
+	require.Eventually(t, func() bool {
+		sm.bufferMutex.RLock()
+		defer sm.bufferMutex.RUnlock()
+
+		return sm.topicBuffers[topic] != nil
+	}, time.Second, time.Millisecond)
+
+	// closing the manager releases the waiting call without a message
+	sm.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe did not return after Close")
+	}
+
+	require.NoError(t, err)
+	assert.Nil(t, msg)
+}
+
+func TestSubscriptionManager_createPubSubMessage_Headers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsg := NewMockMsg(ctrl)
+	headers := nats.Header{"Content-Type": []string{"application/json"}}
+
+	mockMsg.EXPECT().Subject().Return("test.topic")
+	mockMsg.EXPECT().Data().Return([]byte("test message"))
+	mockMsg.EXPECT().Headers().Return(headers)
+
+	sm := newSubscriptionManager(1)
+	msg, err := sm.createPubSubMessage(mockMsg, &Config{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "test.topic", msg.Topic)
+	assert.Equal(t, headers, msg.MetaData)
+}
@@ -0,0 +1,114 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+)
+
+// startSpan starts a span with the client's tracer. When no tracer is set, the context is returned
+// unchanged along with a no-op span.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, noop.Span{}
+	}
+
+	ctx, span := c.tracer.Start(ctx, name)
+	span.SetAttributes(attrs...)
+
+	return ctx, span
+}
+
+// spanAttributes returns the subject, stream and, when set, consumer attributes of a span.
+func spanAttributes(subject, stream, consumer string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("nats.subject", subject),
+		attribute.String("nats.stream", stream),
+	}
+
+	if consumer != "" {
+		attrs = append(attrs, attribute.String("nats.consumer", consumer))
+	}
+
+	return attrs
+}
+
+// endSpan records err on the span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// correlationIDHeader carries the correlation ID of a message, as in the responses of GoFr HTTP services.
+const correlationIDHeader = "X-Correlation-ID"
+
+// correlationID returns the trace ID of the span in ctx, which GoFr uses as the correlation ID of
+// requests, or an empty string when ctx carries no trace.
+func correlationID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+
+	return spanContext.TraceID().String()
+}
+
+// injectCorrelationID sets the correlation ID header from ctx unless headers already carry one.
+func injectCorrelationID(ctx context.Context, headers nats.Header) nats.Header {
+	id := correlationID(ctx)
+	if id == "" || headers.Get(correlationIDHeader) != "" {
+		return headers
+	}
+
+	if headers == nil {
+		headers = nats.Header{}
+	}
+
+	headers.Set(correlationIDHeader, id)
+
+	return headers
+}
+
+// messageCorrelationID returns the correlation ID header of a received message, falling back to the
+// trace ID of its context.
+func messageCorrelationID(msg *pubsub.Message) string {
+	if headers, ok := msg.MetaData.(nats.Header); ok {
+		if id := headers.Get(correlationIDHeader); id != "" {
+			return id
+		}
+	}
+
+	return correlationID(msg.Context())
+}
+
+// injectTraceContext returns a copy of headers carrying the trace context of ctx.
+func injectTraceContext(ctx context.Context, headers nats.Header) nats.Header {
+	carrier := make(nats.Header, len(headers))
+	for key, values := range headers {
+		carrier[key] = values
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(carrier))
+
+	return carrier
+}
+
+// extractTraceContext returns a context carrying the trace context found in headers.
+func extractTraceContext(ctx context.Context, headers nats.Header) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(headers))
+}
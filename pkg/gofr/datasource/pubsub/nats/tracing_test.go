@@ -0,0 +1,189 @@
+package nats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/mock/gomock"
+	"gofr.dev/pkg/gofr/datasource/pubsub"
+	"gofr.dev/pkg/gofr/logging"
+)
+
+// recordingSpan is a span that records the data set on it.
+type recordingSpan struct {
+	noop.Span
+
+	spanContext trace.SpanContext
+	attributes  []attribute.KeyValue
+	links       []trace.Link
+	status      codes.Code
+	ended       bool
+}
+
+func (s *recordingSpan) SpanContext() trace.SpanContext { return s.spanContext }
+
+func (*recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attributes = append(s.attributes, kv...)
+}
+
+func (s *recordingSpan) AddLink(link trace.Link) { s.links = append(s.links, link) }
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *recordingSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+func newTestSpanContext(t *testing.T, traceID string) trace.SpanContext {
+	t.Helper()
+
+	tid, err := trace.TraceIDFromHex(traceID)
+	require.NoError(t, err)
+
+	sid, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid, SpanID: sid, TraceFlags: trace.FlagsSampled})
+}
+
+// recordingTracer starts recordingSpans and records the names of the started spans.
+type recordingTracer struct {
+	noop.Tracer
+
+	span  *recordingSpan
+	names []string
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	r.names = append(r.names, name)
+
+	return trace.ContextWithSpan(ctx, r.span), r.span
+}
+
+func setupTracing(t *testing.T) *recordingTracer {
+	t.Helper()
+
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	return &recordingTracer{span: &recordingSpan{spanContext: newTestSpanContext(t, "4bf92f3577b34da6a3ce929d0e0e4736")}}
+}
+
+func TestNATSClient_Publish_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tracer := setupTracing(t)
+	span := tracer.span
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		Config:      &Config{Stream: StreamConfig{Stream: "test-stream", Subjects: []string{"orders.*"}}},
+		tracer:      tracer,
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	mockConnManager.EXPECT().
+		PublishWithHeaders(gomock.Any(), "orders.created", []byte("test-message"), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ []byte, headers nats.Header, _ Metrics) error {
+			assert.Contains(t, headers.Get("Traceparent"), "4bf92f3577b34da6a3ce929d0e0e4736")
+
+			return errPublishError
+		})
+
+	err := client.Publish(context.Background(), "orders.created", []byte("test-message"))
+	require.ErrorIs(t, err, errPublishError)
+
+	assert.Equal(t, []string{"nats.publish"}, tracer.names)
+	assert.Contains(t, span.attributes, attribute.String("nats.subject", "orders.created"))
+	assert.Contains(t, span.attributes, attribute.String("nats.stream", "test-stream"))
+	assert.Equal(t, codes.Error, span.status)
+	assert.True(t, span.ended)
+}
+
+func TestNATSClient_Subscribe_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tracer := setupTracing(t)
+	span := tracer.span
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	mockSubManager := NewMockSubscriptionManagerInterface(ctrl)
+	mockJS := NewMockJetStream(ctrl)
+
+	client := &Client{
+		connManager: mockConnManager,
+		subManager:  mockSubManager,
+		Config:      &Config{Stream: StreamConfig{Stream: "test-stream", Subjects: []string{"test"}}, Consumer: "test-consumer"},
+		tracer:      tracer,
+		logger:      logging.NewMockLogger(logging.DEBUG),
+	}
+
+	publisher := newTestSpanContext(t, "0af7651916cd43dd8448eb211c80319c")
+	msg := pubsub.NewMessage(trace.ContextWithRemoteSpanContext(context.Background(), publisher))
+
+	mockConnManager.EXPECT().JetStream().Return(mockJS, nil)
+	mockSubManager.EXPECT().Subscribe(gomock.Any(), "test", mockJS, gomock.Any(), gomock.Any(), gomock.Any()).Return(msg, nil)
+
+	got, err := client.Subscribe(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+
+	assert.Equal(t, []string{"nats.subscribe"}, tracer.names)
+	assert.Contains(t, span.attributes, attribute.String("nats.consumer", "test-consumer_test"))
+	require.Len(t, span.links, 1)
+	assert.Equal(t, publisher.TraceID(), span.links[0].SpanContext.TraceID())
+	assert.Equal(t, codes.Unset, span.status)
+	assert.True(t, span.ended)
+}
+
+func TestNATSClient_Publish_NoTracer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnManager := NewMockConnectionManagerInterface(ctrl)
+	client := &Client{connManager: mockConnManager, Config: &Config{}, logger: logging.NewMockLogger(logging.DEBUG)}
+
+	ctx := context.Background()
+
+	mockConnManager.EXPECT().Publish(ctx, "test", []byte("test-message"), nil).Return(nil)
+
+	require.NoError(t, client.Publish(ctx, "test", []byte("test-message")))
+}
+
+func TestCreatePubSubMessage_TraceContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	defer otel.SetTextMapPropagator(previous)
+
+	headers := nats.Header{}
+	headers.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	mockMsg := NewMockMsg(ctrl)
+	mockMsg.EXPECT().Headers().Return(headers)
+	mockMsg.EXPECT().Subject().Return("test")
+	mockMsg.EXPECT().Data().Return([]byte("test-message"))
+
+	msg, err := newSubscriptionManager(1).createPubSubMessage(mockMsg, &Config{})
+	require.NoError(t, err)
+
+	spanContext := trace.SpanContextFromContext(msg.Context())
+	assert.True(t, spanContext.IsRemote())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", spanContext.TraceID().String())
+}